@@ -1,13 +1,13 @@
 package main
 
 import (
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -305,6 +305,12 @@ func (ci CI) PerformOpenShiftUpgrade() error {
 }
 
 func (ci CI) TestE2E() error {
+	// unregister the PaC server registered while bootstrapping the cluster once the tests are done
+	defer func() {
+		if err := sprayproxy.ReleaseLeases(); err != nil {
+			klog.Error(err)
+		}
+	}()
 
 	if err := ci.init(); err != nil {
 		return fmt.Errorf("error when running ci init: %v", err)
@@ -1082,7 +1088,18 @@ func newSprayProxy() (*sprayproxy.SprayProxyConfig, error) {
 	if sprayProxyToken = os.Getenv("QE_SPRAYPROXY_TOKEN"); sprayProxyToken == "" {
 		return nil, fmt.Errorf("env var QE_SPRAYPROXY_TOKEN is not set")
 	}
-	return sprayproxy.NewSprayProxyConfig(sprayProxyUrl, sprayProxyToken)
+	config, err := sprayproxy.NewSprayProxyConfig(sprayProxyUrl, sprayProxyToken)
+	if err != nil {
+		return nil, err
+	}
+	// leases are shared with the other CI jobs and CleanupRegisteredPacServers through a ConfigMap
+	if config.Leases, err = sprayproxy.NewLeaseStoreFromEnv(); err != nil {
+		return nil, err
+	}
+	if config.Leases == nil {
+		klog.Warning("QE_SPRAYPROXY_LEASE_KUBECONFIG is not set, the leases of PaC servers registered in SprayProxy are not recorded")
+	}
+	return config, nil
 }
 
 func registerPacServer() error {
@@ -1097,11 +1114,13 @@ func registerPacServer() error {
 	if err != nil {
 		return fmt.Errorf("failed to get PaC host: %+v", err)
 	}
-	_, err = sprayProxyConfig.RegisterServer(pacHost)
+	lease, err := sprayProxyConfig.AcquireLease(pacHost, sprayproxy.LeaseOwner, sprayproxy.JobIDFromEnv(), sprayproxy.DefaultLeaseTTL)
 	if err != nil {
 		return fmt.Errorf("error when registering PaC server %s to SprayProxy server %s: %+v", pacHost, sprayProxyConfig.BaseURL, err)
 	}
-	klog.Infof("Registered PaC server: %s", pacHost)
+	// make sure the PaC server doesn't keep receiving webhooks if the job gets interrupted
+	lease.ReleaseOnInterrupt()
+	klog.Infof("Registered PaC server: %s (job %s, lease expires at %s)", pacHost, lease.JobID, lease.ExpiresAt.Format(time.RFC3339))
 	// for debugging purposes
 	err = printRegisteredPacServers()
 	if err != nil {
@@ -1128,6 +1147,15 @@ func unregisterPacServer() error {
 	if err != nil {
 		return fmt.Errorf("failed to get PaC host: %+v", err)
 	}
+	// the lease of the PaC server is released when the tests are done, see CI.TestE2E
+	servers, err := sprayProxyConfig.GetServers()
+	if err != nil {
+		return fmt.Errorf("failed to get registered PaC servers from SprayProxy: %+v", err)
+	}
+	if !slices.Contains(strings.Split(servers, ","), pacHost) {
+		klog.Infof("PaC server %s is not registered in SprayProxy", pacHost)
+		return nil
+	}
 	_, err = sprayProxyConfig.UnregisterServer(pacHost)
 	if err != nil {
 		return fmt.Errorf("error when unregistering PaC server %s from SprayProxy server %s: %+v", pacHost, sprayProxyConfig.BaseURL, err)
@@ -1311,22 +1339,18 @@ func CleanupRegisteredPacServers() error {
 		return fmt.Errorf("failed to initialize SprayProxy config: %+v", err)
 	}
 
-	servers, err := sprayProxyConfig.GetServers()
+	klog.Infof("Before cleaningup Pac servers...")
+	err = printRegisteredPacServers()
 	if err != nil {
-		return fmt.Errorf("failed to get registered PaC servers from SprayProxy: %+v", err)
+		klog.Error(err)
 	}
-	klog.Infof("Before cleaningup Pac servers, the registered PaC servers: %v", servers)
 
-	for _, server := range strings.Split(servers, ",") {
-		// Verify if the server is a valid host, if not, unregister it
-		if !isValidPacHost(server) {
-			_, err := sprayProxyConfig.UnregisterServer(strings.TrimSpace(server))
-			if err != nil {
-				return fmt.Errorf("error when unregistering PaC server %s from SprayProxy server %s: %+v", server, sprayProxyConfig.BaseURL, err)
-			}
-			klog.Infof("Cleanup invalid PaC server: %s", server)
-		}
+	// Remove the PaC servers whose lease expired or which are not reachable anymore
+	removed, err := sprayProxyConfig.Reconcile(nil)
+	if err != nil {
+		return err
 	}
+	klog.Infof("Cleanup invalid PaC servers: %v", removed)
 	klog.Infof("After cleaningup Pac servers...")
 	err = printRegisteredPacServers()
 	if err != nil {
@@ -1335,12 +1359,6 @@ func CleanupRegisteredPacServers() error {
 	return nil
 }
 
-func isValidPacHost(server string) bool {
-	httpClient := http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
-	_, err := httpClient.Get(strings.TrimSpace(server))
-	return err == nil
-}
-
 func (Local) PreviewTestSelection() error {
 
 	rctx := rulesengine.NewRuleCtx()
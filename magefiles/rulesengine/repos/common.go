@@ -17,6 +17,7 @@ import (
 	gh "github.com/google/go-github/v44/github"
 	"github.com/konflux-ci/e2e-tests/magefiles/installation"
 	"github.com/konflux-ci/e2e-tests/magefiles/rulesengine"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/notifier"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/slack"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/sprayproxy"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
@@ -421,11 +422,13 @@ func registerPacServer() error {
 	if err != nil {
 		return fmt.Errorf("failed to get PaC host: %+v", err)
 	}
-	_, err = sprayProxyConfig.RegisterServer(pacHost)
+	lease, err := sprayProxyConfig.AcquireLease(pacHost, sprayproxy.LeaseOwner, sprayproxy.JobIDFromEnv(), sprayproxy.DefaultLeaseTTL)
 	if err != nil {
 		return fmt.Errorf("error when registering PaC server %s to SprayProxy server %s: %+v", pacHost, sprayProxyConfig.BaseURL, err)
 	}
-	klog.Infof("Registered PaC server: %s", pacHost)
+	// make sure the PaC server doesn't keep receiving webhooks if the job gets interrupted
+	lease.ReleaseOnInterrupt()
+	klog.Infof("Registered PaC server: %s (job %s, lease expires at %s)", pacHost, lease.JobID, lease.ExpiresAt.Format(time.RFC3339))
 	// for debugging purposes
 	err = printRegisteredPacServers(sprayProxyConfig)
 	if err != nil {
@@ -442,7 +445,18 @@ func newSprayProxy() (*sprayproxy.SprayProxyConfig, error) {
 	if sprayProxyToken = os.Getenv("QE_SPRAYPROXY_TOKEN"); sprayProxyToken == "" {
 		return nil, fmt.Errorf("env var QE_SPRAYPROXY_TOKEN is not set")
 	}
-	return sprayproxy.NewSprayProxyConfig(sprayProxyUrl, sprayProxyToken)
+	config, err := sprayproxy.NewSprayProxyConfig(sprayProxyUrl, sprayProxyToken)
+	if err != nil {
+		return nil, err
+	}
+	// leases are shared with the other CI jobs and CleanupRegisteredPacServers through a ConfigMap
+	if config.Leases, err = sprayproxy.NewLeaseStoreFromEnv(); err != nil {
+		return nil, err
+	}
+	if config.Leases == nil {
+		klog.Warning("QE_SPRAYPROXY_LEASE_KUBECONFIG is not set, the leases of PaC servers registered in SprayProxy are not recorded")
+	}
+	return config, nil
 }
 
 func printRegisteredPacServers(cfg *sprayproxy.SprayProxyConfig) error {
@@ -455,10 +469,10 @@ func printRegisteredPacServers(cfg *sprayproxy.SprayProxyConfig) error {
 }
 
 func HandleErrorWithAlert(err error, errLevel slack.ErrorSeverityLevel) error {
-	klog.Warning(err.Error() + " - this issue will be reported to a dedicated Slack channel")
+	klog.Warning(err.Error() + " - this issue will be reported to the configured alert notifier")
 
-	if slackErr := slack.ReportIssue(err.Error(), errLevel); slackErr != nil {
-		return fmt.Errorf("failed report an error (%s) to a Slack channel: %s", err, slackErr)
+	if notifyErr := notifier.Notify(err.Error(), notifier.Severity(errLevel)); notifyErr != nil {
+		return fmt.Errorf("failed report an error (%s) to the alert notifier: %s", err, notifyErr)
 	}
 	return nil
 }
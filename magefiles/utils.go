@@ -21,6 +21,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	plumbingHttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	sprig "github.com/go-task/slim-sprig"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/notifier"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/slack"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	"github.com/konflux-ci/image-controller/pkg/quay"
@@ -432,10 +433,10 @@ func mergeBranch(repoPath string, branchToMerge string) error {
 }
 
 func HandleErrorWithAlert(err error, errLevel slack.ErrorSeverityLevel) error {
-	klog.Warning(err.Error() + " - this issue will be reported to a dedicated Slack channel")
+	klog.Warning(err.Error() + " - this issue will be reported to the configured alert notifier")
 
-	if slackErr := slack.ReportIssue(err.Error(), errLevel); slackErr != nil {
-		return fmt.Errorf("failed report an error (%s) to a Slack channel: %s", err, slackErr)
+	if notifyErr := notifier.Notify(err.Error(), notifier.Severity(errLevel)); notifyErr != nil {
+		return fmt.Errorf("failed report an error (%s) to the alert notifier: %s", err, notifyErr)
 	}
 	return nil
 }
@@ -0,0 +1,189 @@
+// Package clienterrors contains the errors returned by the controllers in pkg/clients, so callers can tell
+// a missing resource, a timeout, a failed PipelineRun and an unmet precondition apart with errors.Is and errors.As.
+package clienterrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/apis"
+)
+
+var (
+	// ErrNotFound matches a NotFoundError
+	ErrNotFound = errors.New("not found")
+	// ErrTimeout matches a TimeoutError
+	ErrTimeout = errors.New("timed out")
+	// ErrPipelineFailed matches a PipelineFailedError
+	ErrPipelineFailed = errors.New("pipelinerun failed")
+	// ErrPrecondition matches a PreconditionError
+	ErrPrecondition = errors.New("precondition not met")
+)
+
+// NotFoundError is returned when no resource of the given kind matches, e.g. no PipelineRun was created for a Component yet.
+type NotFoundError struct {
+	Kind      string
+	Namespace string
+	// Name is the name of the resource or a description of what it was searched by
+	Name    string
+	Message string
+}
+
+// NotFound returns a NotFoundError with the message formatted from format and args.
+func NotFound(kind, namespace, name, format string, args ...any) *NotFoundError {
+	return &NotFoundError{Kind: kind, Namespace: namespace, Name: name, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s %s not found in %s namespace", e.Kind, e.Name, e.Namespace)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// TimeoutError is returned when a wait helper gives up, with the state of the resource observed last.
+type TimeoutError struct {
+	// Operation is what was waited for, e.g. "PipelineRun of Component ns/name to finish"
+	Operation string
+	Timeout   time.Duration
+	LastState string
+	// Err is the error the wait ended with, e.g. a NotFoundError when the resource was never created
+	Err error
+}
+
+// Timeout returns a TimeoutError.
+func Timeout(operation string, timeout time.Duration, lastState string, err error) *TimeoutError {
+	return &TimeoutError{Operation: operation, Timeout: timeout, LastState: lastState, Err: err}
+}
+
+func (e *TimeoutError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "timed out after %s waiting for %s", e.Timeout, e.Operation)
+	if e.LastState != "" {
+		fmt.Fprintf(&b, ", last observed state: %s", e.LastState)
+	}
+	if e.Err != nil && !wait.Interrupted(e.Err) {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	return b.String()
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// PipelineFailedError is returned when a PipelineRun doesn't succeed.
+// The failed TaskRun, its pod and step are set when they could be found.
+type PipelineFailedError struct {
+	Namespace     string
+	Name          string
+	Reason        string
+	Message       string
+	FailedTaskRun string
+	PodName       string
+	FailedStep    string
+	// Logs contains the logs of the failed step or the failure message of the PipelineRun
+	Logs string
+}
+
+// PipelineFailed returns a PipelineFailedError with the reason and the message of the Succeeded condition of the PipelineRun.
+func PipelineFailed(pr *pipeline.PipelineRun) *PipelineFailedError {
+	e := &PipelineFailedError{Namespace: pr.GetNamespace(), Name: pr.GetName()}
+	if c := pr.GetStatusCondition().GetCondition(apis.ConditionSucceeded); c != nil {
+		e.Reason = c.GetReason()
+		e.Message = c.GetMessage()
+	}
+	return e
+}
+
+func (e *PipelineFailedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PipelineRun %s/%s failed", e.Namespace, e.Name)
+	if e.Reason != "" {
+		fmt.Fprintf(&b, " with reason %s", e.Reason)
+	}
+	if e.FailedTaskRun != "" {
+		fmt.Fprintf(&b, " in TaskRun %s", e.FailedTaskRun)
+		if e.FailedStep != "" {
+			fmt.Fprintf(&b, " (step %s)", e.FailedStep)
+		}
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Logs != "" {
+		fmt.Fprintf(&b, "\n%s", e.Logs)
+	}
+	return b.String()
+}
+
+func (e *PipelineFailedError) Is(target error) bool {
+	return target == ErrPipelineFailed
+}
+
+// PreconditionError is returned when an operation can't be done on a resource in its current state,
+// e.g. retriggering a PipelineRun lacking the Pipelines as Code labels.
+type PreconditionError struct {
+	Condition string
+	Err       error
+}
+
+// Precondition returns a PreconditionError with the condition formatted from format and args.
+func Precondition(format string, args ...any) *PreconditionError {
+	return &PreconditionError{Condition: fmt.Sprintf(format, args...)}
+}
+
+func (e *PreconditionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Condition, e.Err)
+	}
+	return e.Condition
+}
+
+func (e *PreconditionError) Is(target error) bool {
+	return target == ErrPrecondition
+}
+
+func (e *PreconditionError) Unwrap() error {
+	return e.Err
+}
+
+// WrapTimeout turns the error of a wait.Poll* function into a TimeoutError when the poll ran out of time,
+// keeping lastErr, the error observed by the last poll, as the cause. Other errors, e.g. a PipelineFailedError
+// returned by the condition, are returned unchanged.
+func WrapTimeout(err error, operation string, timeout time.Duration, lastState string, lastErr error) error {
+	if err == nil || !wait.Interrupted(err) {
+		return err
+	}
+	if lastErr == nil {
+		lastErr = err
+	}
+	return Timeout(operation, timeout, lastState, lastErr)
+}
+
+// IsNotFound returns true if err is or wraps a NotFoundError.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsTimeout returns true if err is or wraps a TimeoutError.
+func IsTimeout(err error) bool {
+	return errors.Is(err, ErrTimeout)
+}
+
+// IsPipelineFailed returns true if err is or wraps a PipelineFailedError.
+func IsPipelineFailed(err error) bool {
+	return errors.Is(err, ErrPipelineFailed)
+}
@@ -0,0 +1,74 @@
+package clienterrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestNotFoundWrappedInTimeout(t *testing.T) {
+	notFound := NotFound("PipelineRun", "ns", "comp", "no pipelinerun found for component %s", "comp")
+	assert.Equal(t, "no pipelinerun found for component comp", notFound.Error())
+
+	pollErr := wait.PollUntilContextTimeout(context.Background(), time.Millisecond, 5*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	err := fmt.Errorf("waiting failed: %w", WrapTimeout(pollErr, "PipelineRun of Component ns/comp to finish", time.Minute, "", notFound))
+	assert.True(t, IsTimeout(err))
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsPipelineFailed(err))
+	assert.EqualError(t, err, "waiting failed: timed out after 1m0s waiting for PipelineRun of Component ns/comp to finish: no pipelinerun found for component comp")
+
+	var nf *NotFoundError
+	assert.True(t, errors.As(err, &nf))
+	assert.Equal(t, "PipelineRun", nf.Kind)
+
+	var timeout *TimeoutError
+	assert.True(t, errors.As(WrapTimeout(pollErr, "Component to be ready", time.Minute, "PaC not configured", nil), &timeout))
+	assert.Equal(t, "timed out after 1m0s waiting for Component to be ready, last observed state: PaC not configured", timeout.Error())
+}
+
+func TestWrapTimeoutKeepsOtherErrors(t *testing.T) {
+	assert.NoError(t, WrapTimeout(nil, "anything", time.Minute, "", nil))
+
+	failure := &PipelineFailedError{Namespace: "ns", Name: "pr"}
+	assert.Same(t, failure, WrapTimeout(failure, "anything", time.Minute, "", nil))
+}
+
+func TestPipelineFailed(t *testing.T) {
+	pr := &pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "ns"}}
+	pr.Status.Status = duckv1.Status{Conditions: duckv1.Conditions{{
+		Type:    apis.ConditionSucceeded,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Failed",
+		Message: "Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 0",
+	}}}
+	failure := PipelineFailed(pr)
+	failure.FailedTaskRun = "pr-build-container"
+	failure.FailedStep = "step-build"
+	failure.Logs = "exit status 1"
+
+	var err error = fmt.Errorf("attempt 1: %w", failure)
+	assert.True(t, IsPipelineFailed(err))
+	var pf *PipelineFailedError
+	assert.True(t, errors.As(err, &pf))
+	assert.Equal(t, "pr-build-container", pf.FailedTaskRun)
+	assert.Equal(t, "PipelineRun ns/pr failed with reason Failed in TaskRun pr-build-container (step step-build): Tasks Completed: 2 (Failed: 1, Cancelled 0), Skipped: 0\nexit status 1", failure.Error())
+}
+
+func TestPrecondition(t *testing.T) {
+	err := Precondition("cannot retrigger PipelineRun - required label %q not found", "pipelinesascode.tekton.dev/event-type")
+	assert.True(t, errors.Is(err, ErrPrecondition))
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, `cannot retrigger PipelineRun - required label "pipelinesascode.tekton.dev/event-type" not found`, err.Error())
+}
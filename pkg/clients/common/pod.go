@@ -92,7 +92,8 @@ func (s *SuiteController) WaitForPodSelector(
 
 // ListAllPods returns a list of all pods in a namespace.
 func (s *SuiteController) ListAllPods(namespace string) (*corev1.PodList, error) {
-	return s.KubeInterface().CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+	pods, err := ListResources[corev1.Pod](s.KubeRest(), namespace, nil)
+	return &corev1.PodList{Items: pods}, err
 }
 
 func (s *SuiteController) GetPodLogs(pod *corev1.Pod) map[string][]byte {
@@ -122,17 +123,7 @@ func (s *SuiteController) StorePod(pod *corev1.Pod) error {
 
 // StoreAllPods stores all pods in a given namespace.
 func (s *SuiteController) StoreAllPods(namespace string) error {
-	podList, err := s.ListAllPods(namespace)
-	if err != nil {
-		return err
-	}
-
-	for _, pod := range podList.Items {
-		if err := s.StorePod(&pod); err != nil {
-			return err
-		}
-	}
-	return nil
+	return StoreAllResources[corev1.Pod](s.KubeRest(), namespace, s.StorePod)
 }
 
 func (s *SuiteController) DeletePod(podName string, namespace string) error {
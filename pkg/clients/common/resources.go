@@ -0,0 +1,203 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Object is a pointer to a Kubernetes object type registered in the scheme of the client, e.g. *appservice.Component.
+// The helpers below are instantiated with the object type only, e.g. ListResources[appservice.Component](c, namespace, nil).
+type Object[T any] interface {
+	*T
+	client.Object
+}
+
+// DeleteAllOptions configures DeleteAllResources.
+type DeleteAllOptions struct {
+	// Labels selects the resources to delete, all resources of the namespace are deleted if empty
+	Labels map[string]string
+	// RemoveFinalizers removes the finalizers of the resources, so they are deleted even when their controller doesn't handle them
+	RemoveFinalizers bool
+	// Timeout of waiting until the resources are gone, they are not waited for if zero
+	Timeout time.Duration
+}
+
+// GetResource returns the resource of type T with the given name.
+func GetResource[T any, PT Object[T]](c client.Client, name, namespace string) (PT, error) {
+	obj := PT(new(T))
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// ListResources returns the resources of type T in the namespace matching all the labels.
+func ListResources[T any, PT Object[T]](c client.Client, namespace string, labels map[string]string) ([]T, error) {
+	list, err := newList[T, PT](c)
+	if err != nil {
+		return nil, err
+	}
+	opts := []client.ListOption{client.InNamespace(namespace)}
+	if len(labels) > 0 {
+		opts = append(opts, client.MatchingLabels(labels))
+	}
+	if err := c.List(context.Background(), list, opts...); err != nil {
+		return nil, err
+	}
+	objects, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]T, 0, len(objects))
+	for _, o := range objects {
+		obj, ok := o.(PT)
+		if !ok {
+			return nil, fmt.Errorf("unexpected item %T in the list of %s", o, ResourceKind[T, PT](c))
+		}
+		items = append(items, *obj)
+	}
+	return items, nil
+}
+
+// DeleteAllResources deletes the resources of type T in the namespace and waits until they are gone if a timeout is set.
+// When the finalizers are removed, they are removed again on every poll, in case a controller added them back.
+func DeleteAllResources[T any, PT Object[T]](c client.Client, namespace string, options DeleteAllOptions) error {
+	kind := ResourceKind[T, PT](c)
+	if !options.RemoveFinalizers {
+		opts := []client.DeleteAllOfOption{client.InNamespace(namespace)}
+		if len(options.Labels) > 0 {
+			opts = append(opts, client.MatchingLabels(options.Labels))
+		}
+		if err := c.DeleteAllOf(context.Background(), PT(new(T)), opts...); err != nil {
+			return fmt.Errorf("error deleting %s resources from the namespace %s: %+v", kind, namespace, err)
+		}
+	} else if err := deleteRemovingFinalizers[T, PT](c, namespace, options.Labels); err != nil {
+		return fmt.Errorf("error deleting %s resources from the namespace %s: %+v", kind, namespace, err)
+	}
+	if options.Timeout == 0 {
+		return nil
+	}
+
+	var lastState string
+	err := utils.WaitUntil(func() (done bool, err error) {
+		items, err := ListResources[T, PT](c, namespace, options.Labels)
+		if err != nil {
+			return false, nil
+		}
+		if len(items) == 0 {
+			return true, nil
+		}
+		lastState = fmt.Sprintf("%d %s resources left", len(items), kind)
+		if options.RemoveFinalizers {
+			if err := deleteRemovingFinalizers[T, PT](c, namespace, options.Labels); err != nil {
+				GinkgoWriter.Printf("unable to delete %s resources in '%s': %v\n", kind, namespace, err)
+			}
+		}
+		return false, nil
+	}, options.Timeout)
+	return clienterrors.WrapTimeout(err, fmt.Sprintf("deletion of all %s resources in '%s'", kind, namespace), options.Timeout, lastState, nil)
+}
+
+// WaitForResourceDeletion waits until the resource of type T with the given name is gone.
+func WaitForResourceDeletion[T any, PT Object[T]](c client.Client, name, namespace string, timeout time.Duration) error {
+	err := utils.WaitUntil(func() (done bool, err error) {
+		_, err = GetResource[T, PT](c, name, namespace)
+		return k8sErrors.IsNotFound(err), nil
+	}, timeout)
+	return clienterrors.WrapTimeout(err, fmt.Sprintf("deletion of %s '%s' in '%s'", ResourceKind[T, PT](c), name, namespace), timeout, "", nil)
+}
+
+// StoreResource stores the resource as a YAML artifact named after its kind and name, e.g. "component-my-comp.yaml".
+func StoreResource(c client.Client, obj client.Object) error {
+	return logs.StoreResourceYaml(obj, artifactPrefix(c, obj)+"-"+obj.GetName())
+}
+
+// StoreAllResources stores all the resources of type T in the namespace using store, or StoreResource if store is nil.
+func StoreAllResources[T any, PT Object[T]](c client.Client, namespace string, store func(obj PT) error) error {
+	items, err := ListResources[T, PT](c, namespace, nil)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		obj := PT(&items[i])
+		if store == nil {
+			err = StoreResource(c, obj)
+		} else {
+			err = store(obj)
+		}
+		if err != nil {
+			return fmt.Errorf("error storing %s %s/%s: %+v", ResourceKind[T, PT](c), namespace, obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// ResourceKind returns the kind of the resource type T in the scheme of the client, e.g. "PipelineRun".
+func ResourceKind[T any, PT Object[T]](c client.Client) string {
+	gvk, err := apiutil.GVKForObject(PT(new(T)), c.Scheme())
+	if err != nil {
+		return fmt.Sprintf("%T", PT(new(T)))
+	}
+	return gvk.Kind
+}
+
+func newList[T any, PT Object[T]](c client.Client) (client.ObjectList, error) {
+	gvk, err := apiutil.GVKForObject(PT(new(T)), c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	gvk.Kind += "List"
+	obj, err := c.Scheme().New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", gvk)
+	}
+	return list, nil
+}
+
+func deleteRemovingFinalizers[T any, PT Object[T]](c client.Client, namespace string, labels map[string]string) error {
+	items, err := ListResources[T, PT](c, namespace, labels)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range items {
+		obj := PT(&items[i])
+		if len(obj.GetFinalizers()) > 0 {
+			patch := client.RawPatch(types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`))
+			if err := c.Patch(context.Background(), obj, patch); client.IgnoreNotFound(err) != nil {
+				errs = append(errs, fmt.Errorf("unable to remove finalizers of '%s': %+v", obj.GetName(), err))
+				continue
+			}
+		}
+		if err := c.Delete(context.Background(), obj); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("unable to delete '%s': %+v", obj.GetName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// artifactPrefix returns the kind of the object with the first letter in lower case, e.g. "pipelineRun".
+func artifactPrefix(c client.Client, obj client.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil || gvk.Kind == "" {
+		return "resource"
+	}
+	return strings.ToLower(gvk.Kind[:1]) + gvk.Kind[1:]
+}
@@ -0,0 +1,88 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	appservice "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/stretchr/testify/assert"
+	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestGetAndListResources(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(kubeCl.Scheme()).WithObjects(
+		&appservice.Component{ObjectMeta: metav1.ObjectMeta{Name: "comp-a", Namespace: "ns", Labels: map[string]string{"app": "a"}}},
+		&appservice.Component{ObjectMeta: metav1.ObjectMeta{Name: "comp-b", Namespace: "ns", Labels: map[string]string{"app": "b"}}},
+		&appservice.Component{ObjectMeta: metav1.ObjectMeta{Name: "comp-c", Namespace: "other"}},
+	).Build()
+
+	component, err := GetResource[appservice.Component](c, "comp-a", "ns")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", component.Labels["app"])
+
+	_, err = GetResource[appservice.Component](c, "comp-c", "ns")
+	assert.True(t, k8sErrors.IsNotFound(err))
+
+	components, err := ListResources[appservice.Component](c, "ns", nil)
+	assert.NoError(t, err)
+	assert.Len(t, components, 2)
+
+	components, err = ListResources[appservice.Component](c, "ns", map[string]string{"app": "b"})
+	assert.NoError(t, err)
+	assert.Len(t, components, 1)
+	assert.Equal(t, "comp-b", components[0].Name)
+
+	assert.Equal(t, "Component", ResourceKind[appservice.Component](c))
+	assert.Equal(t, "pipelineRun", artifactPrefix(c, &pipeline.PipelineRun{}))
+}
+
+func TestDeleteAllResources(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(kubeCl.Scheme()).WithObjects(
+		&pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-a", Namespace: "ns", Finalizers: []string{"chains.tekton.dev/pipelinerun"}}},
+		&pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-b", Namespace: "ns"}},
+		&pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-c", Namespace: "other"}},
+	).Build()
+
+	assert.NoError(t, DeleteAllResources[pipeline.PipelineRun](c, "ns", DeleteAllOptions{RemoveFinalizers: true, Timeout: time.Second}))
+	pipelineRuns, err := ListResources[pipeline.PipelineRun](c, "ns", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, pipelineRuns)
+	assert.NoError(t, WaitForResourceDeletion[pipeline.PipelineRun](c, "pr-a", "ns", time.Second))
+
+	pipelineRuns, err = ListResources[pipeline.PipelineRun](c, "other", nil)
+	assert.NoError(t, err)
+	assert.Len(t, pipelineRuns, 1)
+}
+
+func TestDeleteAllResourcesRemovingFinalizersError(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(kubeCl.Scheme()).WithObjects(
+		&pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "pr-a", Namespace: "ns"}},
+	).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(context.Context, client.WithWatch, client.Object, ...client.DeleteOption) error {
+			return fmt.Errorf("forbidden")
+		},
+	}).Build()
+
+	err := DeleteAllResources[pipeline.PipelineRun](c, "ns", DeleteAllOptions{RemoveFinalizers: true, Timeout: time.Second})
+	assert.ErrorContains(t, err, "unable to delete 'pr-a': forbidden")
+	assert.False(t, clienterrors.IsTimeout(err))
+}
+
+func TestDeleteAllResourcesTimeout(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(kubeCl.Scheme()).WithObjects(
+		&appservice.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns", Finalizers: []string{"application.appstudio.redhat.com/finalizer"}}},
+	).Build()
+
+	err := DeleteAllResources[appservice.Application](c, "ns", DeleteAllOptions{Timeout: time.Second})
+	assert.True(t, clienterrors.IsTimeout(err))
+	assert.ErrorContains(t, err, "last observed state: 1 Application resources left")
+}
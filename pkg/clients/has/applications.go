@@ -6,13 +6,12 @@ import (
 	"time"
 
 	appservice "github.com/konflux-ci/application-api/api/v1alpha1"
-	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	rclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // GetApplication returns an application given a name and namespace from kubernetes cluster.
@@ -81,43 +80,22 @@ func (h *HasController) ApplicationDeleted(application *appservice.Application)
 
 // DeleteAllApplicationsInASpecificNamespace removes all application CRs from a specific namespace. Useful when creating a lot of resources and want to remove all of them
 func (h *HasController) DeleteAllApplicationsInASpecificNamespace(namespace string, timeout time.Duration) error {
-	if err := h.KubeRest().DeleteAllOf(context.Background(), &appservice.Application{}, rclient.InNamespace(namespace)); err != nil {
-		return fmt.Errorf("error deleting applications from the namespace %s: %+v", namespace, err)
-	}
-
-	return utils.WaitUntil(func() (done bool, err error) {
-		applicationList, err := h.ListAllApplications(namespace)
-		if err != nil {
-			return false, nil
-		}
-		return len(applicationList.Items) == 0, nil
-	}, timeout)
+	return common.DeleteAllResources[appservice.Application](h.KubeRest(), namespace, common.DeleteAllOptions{Timeout: timeout})
 }
 
 // ListAllApplications returns a list of all Applications in a given namespace.
 func (h *HasController) ListAllApplications(namespace string) (*appservice.ApplicationList, error) {
-	applicationList := &appservice.ApplicationList{}
-	err := h.KubeRest().List(context.Background(), applicationList, &rclient.ListOptions{Namespace: namespace})
+	applications, err := common.ListResources[appservice.Application](h.KubeRest(), namespace, nil)
 
-	return applicationList, err
+	return &appservice.ApplicationList{Items: applications}, err
 }
 
 // StoreApplication stores a given Application as an artifact.
 func (h *HasController) StoreApplication(application *appservice.Application) error {
-	return logs.StoreResourceYaml(application, "application-"+application.Name)
+	return common.StoreResource(h.KubeRest(), application)
 }
 
 // StoreAllApplications stores all Applications in a given namespace.
 func (h *HasController) StoreAllApplications(namespace string) error {
-	applicationList, err := h.ListAllApplications(namespace)
-	if err != nil {
-		return err
-	}
-
-	for _, application := range applicationList.Items {
-		if err := h.StoreApplication(&application); err != nil {
-			return err
-		}
-	}
-	return nil
+	return common.StoreAllResources[appservice.Application](h.KubeRest(), namespace, nil)
 }
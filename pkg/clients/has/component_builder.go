@@ -0,0 +1,244 @@
+package has
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	appservice "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var dns1123LabelRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ComponentBuilder builds Component objects in a fluent way.
+// Use NewComponentBuilder to get a builder with the defaults used by e2e tests:
+// a public image repository generated by image-controller and the default target port.
+type ComponentBuilder struct {
+	component *appservice.Component
+}
+
+// NewComponentBuilder returns a ComponentBuilder for a Component of the given application.
+func NewComponentBuilder(name, applicationName, namespace string) *ComponentBuilder {
+	return &ComponentBuilder{
+		component: &appservice.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					constants.SkipInitialChecksAnnotation: "false",
+				},
+			},
+			Spec: appservice.ComponentSpec{
+				ComponentName: name,
+				Application:   applicationName,
+				TargetPort:    8081,
+			},
+		},
+	}
+}
+
+// FromSpec replaces the spec of the Component, keeping the name and the application of the builder.
+func (b *ComponentBuilder) FromSpec(spec appservice.ComponentSpec) *ComponentBuilder {
+	spec.ComponentName = b.component.Spec.ComponentName
+	spec.Application = b.component.Spec.Application
+	if spec.TargetPort == 0 {
+		spec.TargetPort = b.component.Spec.TargetPort
+	}
+	b.component.Spec = *spec.DeepCopy()
+	return b
+}
+
+// WithGitSource sets the git repository and revision the Component is built from.
+func (b *ComponentBuilder) WithGitSource(url, revision string) *ComponentBuilder {
+	b.gitSource().URL = url
+	b.gitSource().Revision = revision
+	return b
+}
+
+// WithContext sets the directory of the git repository the Component is built from.
+func (b *ComponentBuilder) WithContext(context string) *ComponentBuilder {
+	b.gitSource().Context = context
+	return b
+}
+
+// WithDockerfile sets the path or URL of the Dockerfile.
+func (b *ComponentBuilder) WithDockerfile(dockerfile string) *ComponentBuilder {
+	b.gitSource().DockerfileURL = dockerfile
+	return b
+}
+
+// WithSecret sets the secret used to access the git repository.
+func (b *ComponentBuilder) WithSecret(secret string) *ComponentBuilder {
+	b.component.Spec.Secret = secret
+	return b
+}
+
+// WithBuildPipeline selects the build pipeline and its bundle, "latest" if empty.
+func (b *ComponentBuilder) WithBuildPipeline(pipeline constants.BuildPipelineType, bundle string) *ComponentBuilder {
+	if bundle == "" {
+		bundle = "latest"
+	}
+	b.component.Annotations[constants.BuildPipelineAnnotation] = fmt.Sprintf(`{"name": "%s", "bundle": "%s"}`, pipeline, bundle)
+	return b
+}
+
+// WithPaCBuild requests build-service to configure Pipelines as Code, which opens a PR to the git repository.
+func (b *ComponentBuilder) WithPaCBuild() *ComponentBuilder {
+	return b.WithAnnotations(constants.ComponentPaCRequestAnnotation)
+}
+
+// WithSimpleBuild requests build-service to trigger a build without configuring Pipelines as Code.
+func (b *ComponentBuilder) WithSimpleBuild() *ComponentBuilder {
+	return b.WithAnnotations(constants.ComponentTriggerSimpleBuildAnnotation)
+}
+
+// WithPublicImageRepository requests image-controller to create a public image repository. This is the default.
+func (b *ComponentBuilder) WithPublicImageRepository() *ComponentBuilder {
+	return b.WithAnnotations(constants.ImageControllerAnnotationRequestPublicRepo)
+}
+
+// WithPrivateImageRepository requests image-controller to create a private image repository.
+func (b *ComponentBuilder) WithPrivateImageRepository() *ComponentBuilder {
+	return b.WithAnnotations(constants.ImageControllerAnnotationRequestPrivateRepo)
+}
+
+// WithContainerImage sets the output image of the Component, so no image repository is created by image-controller.
+func (b *ComponentBuilder) WithContainerImage(image string) *ComponentBuilder {
+	b.component.Spec.ContainerImage = image
+	return b
+}
+
+// WithReplicas sets the number of replicas of the Component.
+func (b *ComponentBuilder) WithReplicas(replicas int) *ComponentBuilder {
+	b.component.Spec.Replicas = &replicas
+	return b
+}
+
+// WithMintmakerDisabled stops Mintmaker from creating dependency update PRs for the Component.
+func (b *ComponentBuilder) WithMintmakerDisabled() *ComponentBuilder {
+	return b.WithAnnotations(constants.ComponentMintmakerDisabledAnnotation)
+}
+
+// WithNudges makes a build of the Component nudge the given Components.
+func (b *ComponentBuilder) WithNudges(componentNames ...string) *ComponentBuilder {
+	b.component.Spec.BuildNudgesRef = append(b.component.Spec.BuildNudgesRef, componentNames...)
+	return b
+}
+
+// WithNudgeFiles sets the file patterns of the nudged Components updated by nudges.
+func (b *ComponentBuilder) WithNudgeFiles(patterns ...string) *ComponentBuilder {
+	b.component.Annotations[constants.BuildNudgeFilesAnnotation] = strings.Join(patterns, ",")
+	return b
+}
+
+// SkipInitialChecks sets the skip-initial-checks annotation.
+func (b *ComponentBuilder) SkipInitialChecks(skip bool) *ComponentBuilder {
+	b.component.Annotations[constants.SkipInitialChecksAnnotation] = strconv.FormatBool(skip)
+	return b
+}
+
+// WithAnnotations sets the given annotations on the Component, overriding the ones already set.
+func (b *ComponentBuilder) WithAnnotations(annotations map[string]string) *ComponentBuilder {
+	for key, value := range annotations {
+		b.component.Annotations[key] = value
+	}
+	return b
+}
+
+// WithLabel sets a label on the Component.
+func (b *ComponentBuilder) WithLabel(key, value string) *ComponentBuilder {
+	if b.component.Labels == nil {
+		b.component.Labels = map[string]string{}
+	}
+	b.component.Labels[key] = value
+	return b
+}
+
+// Build returns a copy of the built Component without validating it.
+// A public image repository is requested unless an output image or a visibility is set.
+func (b *ComponentBuilder) Build() *appservice.Component {
+	component := b.component.DeepCopy()
+	if component.Spec.ContainerImage == "" && component.Annotations[constants.ImageRepositoryGenerateAnnotation] == "" {
+		component.Annotations[constants.ImageRepositoryGenerateAnnotation] = constants.ImageControllerAnnotationRequestPublicRepo[constants.ImageRepositoryGenerateAnnotation]
+	}
+	return component
+}
+
+// BuildAndValidate returns a copy of the built Component, or an error if it is not valid.
+func (b *ComponentBuilder) BuildAndValidate() (*appservice.Component, error) {
+	component := b.Build()
+	if err := ValidateComponent(component); err != nil {
+		return nil, err
+	}
+	return component, nil
+}
+
+// ValidateComponent checks the Component would be accepted by the cluster and understood by build-service and image-controller.
+func ValidateComponent(component *appservice.Component) error {
+	var errs []error
+	name := component.Spec.ComponentName
+	if len(name) > 63 || !dns1123LabelRegexp.MatchString(name) {
+		errs = append(errs, fmt.Errorf("component name %q is not a valid DNS-1123 label", name))
+	}
+	if component.Name != name {
+		errs = append(errs, fmt.Errorf("component name %q does not match the name %q in the spec", component.Name, name))
+	}
+	if component.Spec.Application == "" {
+		errs = append(errs, fmt.Errorf("component %s does not belong to any application", name))
+	}
+
+	gitSource := component.Spec.Source.GitSource
+	if gitSource == nil || gitSource.URL == "" {
+		if component.Spec.ContainerImage == "" {
+			errs = append(errs, fmt.Errorf("component %s has neither a git source nor a container image", name))
+		}
+	} else if u, err := url.Parse(gitSource.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("git source URL %q of component %s is not a valid http(s) URL", gitSource.URL, name))
+	}
+
+	annotations := component.Annotations
+	if request, ok := annotations[constants.BuildRequestAnnotation]; ok && request != constants.BuildRequestConfigurePaC && request != constants.BuildRequestTriggerSimpleBuild {
+		errs = append(errs, fmt.Errorf("unknown build request %q of component %s", request, name))
+	}
+	if selector, ok := annotations[constants.BuildPipelineAnnotation]; ok {
+		pipeline := struct {
+			Name   string `json:"name"`
+			Bundle string `json:"bundle"`
+		}{}
+		if err := json.Unmarshal([]byte(selector), &pipeline); err != nil || pipeline.Name == "" || pipeline.Bundle == "" {
+			errs = append(errs, fmt.Errorf("build pipeline annotation %q of component %s has to contain a name and a bundle", selector, name))
+		}
+	}
+	if generate, ok := annotations[constants.ImageRepositoryGenerateAnnotation]; ok {
+		if component.Spec.ContainerImage != "" {
+			errs = append(errs, fmt.Errorf("component %s requests an image repository but already has the output image %s", name, component.Spec.ContainerImage))
+		}
+		repo := struct {
+			Visibility string `json:"visibility"`
+		}{}
+		if err := json.Unmarshal([]byte(generate), &repo); err != nil || (repo.Visibility != "public" && repo.Visibility != "private") {
+			errs = append(errs, fmt.Errorf("image repository annotation %q of component %s has to set the visibility to public or private", generate, name))
+		}
+	}
+	for _, nudged := range component.Spec.BuildNudgesRef {
+		if nudged == name {
+			errs = append(errs, fmt.Errorf("component %s cannot nudge itself", name))
+		} else if !dns1123LabelRegexp.MatchString(nudged) {
+			errs = append(errs, fmt.Errorf("nudged component name %q of component %s is not valid", nudged, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *ComponentBuilder) gitSource() *appservice.GitSource {
+	if b.component.Spec.Source.GitSource == nil {
+		b.component.Spec.Source.GitSource = &appservice.GitSource{}
+	}
+	return b.component.Spec.Source.GitSource
+}
@@ -0,0 +1,93 @@
+package has
+
+import (
+	"testing"
+
+	appservice "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentBuilder(t *testing.T) {
+	component, err := NewComponentBuilder("my-comp", "my-app", "ns").
+		WithGitSource("https://github.com/org/repo", "main").
+		WithContext("app").
+		WithDockerfile("Dockerfile").
+		WithBuildPipeline(constants.DockerBuildOciTA, "").
+		WithPaCBuild().
+		WithPrivateImageRepository().
+		WithMintmakerDisabled().
+		WithNudges("other-comp").
+		WithNudgeFiles(".*Dockerfile.*", ".*.yaml").
+		BuildAndValidate()
+	assert.NoError(t, err)
+	assert.Equal(t, "my-app", component.Spec.Application)
+	assert.Equal(t, &appservice.GitSource{URL: "https://github.com/org/repo", Revision: "main", Context: "app", DockerfileURL: "Dockerfile"}, component.Spec.Source.GitSource)
+	assert.Equal(t, []string{"other-comp"}, component.Spec.BuildNudgesRef)
+	assert.Equal(t, map[string]string{
+		constants.SkipInitialChecksAnnotation:       "false",
+		constants.BuildPipelineAnnotation:           `{"name": "docker-build-oci-ta", "bundle": "latest"}`,
+		constants.BuildRequestAnnotation:            constants.BuildRequestConfigurePaC,
+		constants.ImageRepositoryGenerateAnnotation: `{"visibility": "private"}`,
+		constants.MintmakerDisabledAnnotation:       "true",
+		constants.BuildNudgeFilesAnnotation:         ".*Dockerfile.*,.*.yaml",
+	}, component.Annotations)
+}
+
+func TestComponentBuilderDefaults(t *testing.T) {
+	builder := NewComponentBuilder("my-comp", "my-app", "ns").WithGitSource("https://github.com/org/repo", "")
+	component := builder.Build()
+	assert.Equal(t, `{"visibility": "public"}`, component.Annotations[constants.ImageRepositoryGenerateAnnotation])
+	assert.Equal(t, 8081, component.Spec.TargetPort)
+
+	// the built Component is a copy
+	component.Annotations["foo"] = "bar"
+	assert.NotContains(t, builder.Build().Annotations, "foo")
+
+	assert.Nil(t, component.Spec.Replicas)
+
+	component = builder.WithContainerImage("quay.io/org/repo:tag").WithReplicas(1).Build()
+	assert.NotContains(t, component.Annotations, constants.ImageRepositoryGenerateAnnotation)
+	assert.Equal(t, 1, *component.Spec.Replicas)
+
+	component = NewComponentBuilder("ignored", "my-app", "ns").
+		FromSpec(appservice.ComponentSpec{ComponentName: "other", TargetPort: 9090}).
+		Build()
+	assert.Equal(t, "ignored", component.Spec.ComponentName)
+	assert.Equal(t, "my-app", component.Spec.Application)
+	assert.Equal(t, 9090, component.Spec.TargetPort)
+}
+
+func TestValidateComponent(t *testing.T) {
+	_, err := NewComponentBuilder("My_Comp", "", "ns").
+		WithGitSource("git@github.com:org/repo.git", "").
+		WithAnnotations(map[string]string{constants.BuildRequestAnnotation: "unknown", constants.BuildPipelineAnnotation: "docker-build"}).
+		WithContainerImage("quay.io/org/repo").
+		WithPublicImageRepository().
+		WithNudges("My_Comp").
+		BuildAndValidate()
+	assert.ErrorContains(t, err, `component name "My_Comp" is not a valid DNS-1123 label`)
+	assert.ErrorContains(t, err, "does not belong to any application")
+	assert.ErrorContains(t, err, "is not a valid http(s) URL")
+	assert.ErrorContains(t, err, `unknown build request "unknown"`)
+	assert.ErrorContains(t, err, "has to contain a name and a bundle")
+	assert.ErrorContains(t, err, "already has the output image")
+	assert.ErrorContains(t, err, "cannot nudge itself")
+
+	_, err = NewComponentBuilder("my-comp", "my-app", "ns").BuildAndValidate()
+	assert.ErrorContains(t, err, "has neither a git source nor a container image")
+}
+
+func TestGetComponentBuildStatus(t *testing.T) {
+	component := &appservice.Component{}
+	status, err := GetComponentBuildStatus(component)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	component.Annotations = map[string]string{constants.BuildStatusAnnotation: `{"pac":{"state":"enabled","merge-url":"https://github.com/org/repo/pull/1","configuration-time":"Thu, 23 May 2024 07:06:43 UTC"},"message":"done"}`}
+	status, err = GetComponentBuildStatus(component)
+	assert.NoError(t, err)
+	assert.Equal(t, "enabled", status.PaC.State)
+	assert.Equal(t, "https://github.com/org/repo/pull/1", status.PaC.MergeURL)
+	assert.Equal(t, "done", status.Message)
+}
@@ -2,17 +2,20 @@ package has
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/devfile/library/v2/pkg/util"
 	appservice "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/tekton"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/logs"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	tektonutils "github.com/konflux-ci/e2e-tests/pkg/utils/tekton"
 	imagecontroller "github.com/konflux-ci/image-controller/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -22,7 +25,6 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
-	pointer "k8s.io/utils/ptr"
 	"knative.dev/pkg/apis"
 	rclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
@@ -58,7 +60,7 @@ func (h *HasController) GetComponentByApplicationName(applicationName string, na
 		}
 	}
 
-	return &appservice.Component{}, fmt.Errorf("no component found %s", utils.GetAdditionalInfo(applicationName, namespace))
+	return &appservice.Component{}, clienterrors.NotFound("Component", namespace, applicationName, "no component found %s", utils.GetAdditionalInfo(applicationName, namespace))
 }
 
 // GetComponentPipeline returns first pipeline run for a given component labels
@@ -104,7 +106,7 @@ func (h *HasController) GetComponentPipelineRunsWithType(componentName string, a
 		return &list.Items, nil
 	}
 
-	return nil, fmt.Errorf("no pipelinerun found for component %s", componentName)
+	return nil, clienterrors.NotFound("PipelineRun", namespace, componentName, "no pipelinerun found for component %s", componentName)
 }
 
 // GetAllPipelineRunsForApplication returns the pipelineruns for a given application in the namespace
@@ -122,7 +124,7 @@ func (h *HasController) GetAllPipelineRunsForApplication(applicationName, namesp
 		return list, nil
 	}
 
-	return nil, fmt.Errorf("no pipelinerun found for application %s", applicationName)
+	return nil, clienterrors.NotFound("PipelineRun", namespace, applicationName, "no pipelinerun found for application %s", applicationName)
 }
 
 // GetAllGroupSnapshotsForApplication returns the groupSnapshots for a given application in the namespace
@@ -140,7 +142,7 @@ func (h *HasController) GetAllGroupSnapshotsForApplication(applicationName, name
 		return list, nil
 	}
 
-	return nil, fmt.Errorf("no snapshot found for application %s", applicationName)
+	return nil, clienterrors.NotFound("Snapshot", namespace, applicationName, "no snapshot found for application %s", applicationName)
 }
 
 // GetAllComponentSnapshotsForApplicationAndComponent returns the component Snapshots for a given application and component in the namespace
@@ -158,7 +160,7 @@ func (h *HasController) GetAllComponentSnapshotsForApplicationAndComponent(appli
 		return &list.Items, nil
 	}
 
-	return nil, fmt.Errorf("no snapshot found for application %s and component %s", applicationName, componentName)
+	return nil, clienterrors.NotFound("Snapshot", namespace, componentName, "no snapshot found for application %s and component %s", applicationName, componentName)
 }
 
 // Set of options to retrigger pipelineRuns in CI to fight against flakynes
@@ -185,15 +187,20 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 	pr := &pipeline.PipelineRun{}
 
 	for {
+		var lastErr error
+		var lastState string
 		err := wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 30*time.Minute, true, func(ctx context.Context) (done bool, err error) {
 			pr, err = h.GetComponentPipelineRun(component.GetName(), app, component.GetNamespace(), sha)
 
 			if err != nil {
+				lastErr = err
 				GinkgoWriter.Printf("PipelineRun has not been created yet for the Component %s/%s\n", component.GetNamespace(), component.GetName())
 				return false, nil
 			}
 
-			GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pr.Name, pr.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason())
+			lastErr = nil
+			lastState = fmt.Sprintf("PipelineRun %s reason: %s", pr.Name, pr.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason())
+			GinkgoWriter.Printf("%s\n", lastState)
 
 			if !pr.IsDone() {
 				return false, nil
@@ -210,12 +217,15 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 			if prLogs, err = t.GetPipelineRunLogs(component.GetName(), pr.Name, pr.Namespace); err != nil {
 				GinkgoWriter.Printf("failed to get logs for PipelineRun %s:%s: %s\n", pr.GetNamespace(), pr.GetName(), err.Error())
 			}
-			return false, fmt.Errorf("%s", prLogs)
+			failure := tektonutils.NewPipelineFailedError(h.KubeRest(), h.KubeInterface(), pr, false)
+			failure.Logs = prLogs
+			return false, failure
 		})
+		err = clienterrors.WrapTimeout(err, fmt.Sprintf("PipelineRun of Component %s/%s to finish", component.GetNamespace(), component.GetName()), 30*time.Minute, lastState, lastErr)
 
 		if err != nil {
 			if pr == nil {
-				return fmt.Errorf("PipelineRun cannot be created for the Component %s/%s", component.GetNamespace(), component.GetName())
+				return fmt.Errorf("PipelineRun cannot be created for the Component %s/%s: %w", component.GetNamespace(), component.GetName(), err)
 			}
 			GinkgoWriter.Printf("attempt %d/%d: PipelineRun %q failed: %+v", attempts, r.Retries+1, pr.GetName(), err)
 			// CouldntGetTask: Retry the PipelineRun only in case we hit the known issue https://issues.redhat.com/browse/SRVKP-2749
@@ -248,76 +258,125 @@ func (h *HasController) WaitForComponentPipelineToBeFinished(component *appservi
 
 // Universal method to create a component in the kubernetes clusters.
 func (h *HasController) CreateComponent(componentSpec appservice.ComponentSpec, namespace string, outputContainerImage string, secret string, applicationName string, skipInitialChecks bool, annotations map[string]string) (*appservice.Component, error) {
-	componentObject := &appservice.Component{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      componentSpec.ComponentName,
-			Namespace: namespace,
-			Annotations: map[string]string{
-				"skip-initial-checks": strconv.FormatBool(skipInitialChecks),
-			},
-		},
-		Spec: componentSpec,
-	}
-	componentObject.Spec.Secret = secret
-	componentObject.Spec.Application = applicationName
-
-	if len(annotations) > 0 {
-		componentObject.Annotations = utils.MergeMaps(componentObject.Annotations, annotations)
-
+	builder := NewComponentBuilder(componentSpec.ComponentName, applicationName, namespace).
+		FromSpec(componentSpec).
+		WithSecret(secret).
+		SkipInitialChecks(skipInitialChecks).
+		WithAnnotations(annotations)
+	if outputContainerImage != "" {
+		builder.WithContainerImage(outputContainerImage)
 	}
+	return h.createComponent(builder.Build())
+}
 
-	if componentObject.Spec.TargetPort == 0 {
-		componentObject.Spec.TargetPort = 8081
-	}
-	if outputContainerImage != "" {
-		componentObject.Spec.ContainerImage = outputContainerImage
-	} else if componentObject.Annotations["image.redhat.com/generate"] == "" {
-		// Generate default public image repo since nothing is mentioned specifically
-		componentObject.Annotations = utils.MergeMaps(componentObject.Annotations, constants.ImageControllerAnnotationRequestPublicRepo)
+// CreateComponentFromBuilder validates the Component built by the builder and creates it.
+// When image-controller is requested to create the image repository, it waits until the repository is ready.
+func (h *HasController) CreateComponentFromBuilder(builder *ComponentBuilder) (*appservice.Component, error) {
+	component, err := builder.BuildAndValidate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid component: %w", err)
 	}
+	return h.createComponent(component)
+}
 
+func (h *HasController) createComponent(componentObject *appservice.Component) (*appservice.Component, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*1)
 	defer cancel()
 	if err := h.KubeRest().Create(ctx, componentObject); err != nil {
 		return nil, err
 	}
+	if componentObject.Annotations[constants.ImageRepositoryGenerateAnnotation] == "" {
+		return componentObject, nil
+	}
 	// Decrease the timeout to 5 mins, when the issue https://issues.redhat.com/browse/STONEBLD-3552 is fixed
-	if utils.WaitUntil(h.CheckImageRepositoryExists(namespace, componentSpec.ComponentName), time.Minute*15) != nil {
-		return nil, fmt.Errorf("timed out when waiting for image-controller annotations to be updated on component %s in namespace %s. component: %s", componentSpec.ComponentName, namespace, utils.ToPrettyJSONString(componentObject))
+	if err := utils.WaitUntil(h.CheckImageRepositoryExists(componentObject.Namespace, componentObject.Name), time.Minute*15); err != nil {
+		operation := fmt.Sprintf("image-controller annotations to be updated on component %s in namespace %s", componentObject.Name, componentObject.Namespace)
+		return nil, clienterrors.Timeout(operation, time.Minute*15, "component: "+utils.ToPrettyJSONString(componentObject), err)
 	}
 	return componentObject, nil
 }
 
-// CreateComponentWithDockerSource creates a component based on container image source.
-func (h *HasController) CreateComponentWithDockerSource(applicationName, componentName, namespace, gitSourceURL, containerImageSource, outputContainerImage, secret string) (*appservice.Component, error) {
-	component := &appservice.Component{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      componentName,
-			Namespace: namespace,
-		},
-		Spec: appservice.ComponentSpec{
-			ComponentName: componentName,
-			Application:   applicationName,
-			Source: appservice.ComponentSource{
-				ComponentSourceUnion: appservice.ComponentSourceUnion{
-					GitSource: &appservice.GitSource{
-						URL:           gitSourceURL,
-						DockerfileURL: containerImageSource,
-					},
-				},
-			},
-			Secret:         secret,
-			ContainerImage: outputContainerImage,
-			Replicas:       pointer.To[int](1),
-			TargetPort:     8081,
-			Route:          "",
-		},
+// ComponentBuildStatus is the content of the build status annotation set by build-service.
+type ComponentBuildStatus struct {
+	PaC *struct {
+		State             string `json:"state"`
+		MergeURL          string `json:"merge-url,omitempty"`
+		ConfigurationTime string `json:"configuration-time,omitempty"`
+		ErrID             int    `json:"error-id,omitempty"`
+		ErrMessage        string `json:"error-message,omitempty"`
+	} `json:"pac,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetComponentBuildStatus returns the build status of the Component, nil if build-service did not set it yet.
+func GetComponentBuildStatus(component *appservice.Component) (*ComponentBuildStatus, error) {
+	value, ok := component.Annotations[constants.BuildStatusAnnotation]
+	if !ok {
+		return nil, nil
 	}
-	err := h.KubeRest().Create(context.Background(), component)
+	status := &ComponentBuildStatus{}
+	if err := json.Unmarshal([]byte(value), status); err != nil {
+		return nil, fmt.Errorf("cannot parse build status %q of component %s: %+v", value, component.Name, err)
+	}
+	return status, nil
+}
+
+// WaitForComponentReady waits until the image repository of the Component is ready and, when the Component was created
+// with a PaC build request, until build-service opened the PaC PR. The build status of the Component is returned.
+func (h *HasController) WaitForComponentReady(component *appservice.Component, timeout time.Duration) (*ComponentBuildStatus, error) {
+	var status *ComponentBuildStatus
+	var lastState string
+	waitForPaC := component.Annotations[constants.BuildRequestAnnotation] == constants.BuildRequestConfigurePaC
+	waitForImageRepository := component.Spec.ContainerImage == ""
+
+	err := utils.WaitUntilWithInterval(func() (done bool, err error) {
+		if waitForImageRepository {
+			if ready, err := h.CheckImageRepositoryExists(component.Namespace, component.Name)(); err != nil || !ready {
+				lastState = "image repository not ready"
+				GinkgoWriter.Printf("image repository of component %s/%s is not ready yet\n", component.Namespace, component.Name)
+				return false, nil
+			}
+		}
+		current, err := h.GetComponent(component.Name, component.Namespace)
+		if err != nil {
+			GinkgoWriter.Printf("failed to get component %s/%s: %+v\n", component.Namespace, component.Name, err)
+			return false, nil
+		}
+		if status, err = GetComponentBuildStatus(current); err != nil {
+			return false, err
+		}
+		if !waitForPaC {
+			return true, nil
+		}
+		if status == nil || status.PaC == nil {
+			lastState = "PaC not configured"
+			GinkgoWriter.Printf("PaC is not configured for component %s/%s yet\n", component.Namespace, component.Name)
+			return false, nil
+		}
+		if status.PaC.State == "error" {
+			return false, fmt.Errorf("build-service failed to configure PaC for component %s/%s: %s", component.Namespace, component.Name, status.PaC.ErrMessage)
+		}
+		lastState = fmt.Sprintf("PaC state %q, merge URL %q", status.PaC.State, status.PaC.MergeURL)
+		return status.PaC.State == "enabled" && status.PaC.MergeURL != "", nil
+	}, time.Second*10, timeout)
+	err = clienterrors.WrapTimeout(err, "Component to be ready", timeout, lastState, nil)
 	if err != nil {
-		return nil, err
+		return status, fmt.Errorf("component %s/%s is not ready: %w", component.Namespace, component.Name, err)
 	}
-	return component, nil
+	return status, nil
+}
+
+// CreateComponentWithDockerSource creates a component based on container image source.
+func (h *HasController) CreateComponentWithDockerSource(applicationName, componentName, namespace, gitSourceURL, containerImageSource, outputContainerImage, secret string) (*appservice.Component, error) {
+	builder := NewComponentBuilder(componentName, applicationName, namespace).
+		WithGitSource(gitSourceURL, "").
+		WithDockerfile(containerImageSource).
+		WithSecret(secret).
+		WithReplicas(1)
+	if outputContainerImage != "" {
+		builder.WithContainerImage(outputContainerImage)
+	}
+	return h.CreateComponentFromBuilder(builder)
 }
 
 // ScaleDeploymentReplicas scales the replicas of a given deployment
@@ -365,18 +424,7 @@ func (h *HasController) DeleteAllComponentsInASpecificNamespace(namespace string
 	start := time.Now()
 	GinkgoWriter.Printf("Start to delete all components in namespace '%s' at %s\n", namespace, start.String())
 
-	if err := h.KubeRest().DeleteAllOf(context.Background(), &appservice.Component{}, rclient.InNamespace(namespace)); err != nil {
-		return fmt.Errorf("error deleting components from the namespace %s: %+v", namespace, err)
-	}
-
-	componentList := &appservice.ComponentList{}
-
-	err := utils.WaitUntil(func() (done bool, err error) {
-		if err := h.KubeRest().List(context.Background(), componentList, &rclient.ListOptions{Namespace: namespace}); err != nil {
-			return false, nil
-		}
-		return len(componentList.Items) == 0, nil
-	}, timeout)
+	err := common.DeleteAllResources[appservice.Component](h.KubeRest(), namespace, common.DeleteAllOptions{Timeout: timeout})
 
 	// temporary logs
 	deletionTime := time.Since(start).Minutes()
@@ -421,10 +469,10 @@ func (h *HasController) RetriggerComponentPipelineRun(component *appservice.Comp
 		targetBranchAnnotationName := "build.appstudio.redhat.com/target_branch"
 
 		if repoName, ok = prLabels[pacRepoNameLabelName]; !ok {
-			return "", fmt.Errorf(RequiredLabelNotFound, pacRepoNameLabelName)
+			return "", clienterrors.Precondition(RequiredLabelNotFound, pacRepoNameLabelName)
 		}
 		if eventType, ok = prLabels[pacEventTypeLabelName]; !ok {
-			return "", fmt.Errorf(RequiredLabelNotFound, pacEventTypeLabelName)
+			return "", clienterrors.Precondition(RequiredLabelNotFound, pacEventTypeLabelName)
 		}
 		// since not all build PipelineRuns contains this annotation
 		gitProvider = prAnnotations[gitProviderLabelName]
@@ -432,13 +480,13 @@ func (h *HasController) RetriggerComponentPipelineRun(component *appservice.Comp
 		// PipelineRun is triggered from a pull request, need to update the PaC PR source branch
 		if eventType == "pull_request" || eventType == "Merge_Request" {
 			if len(prLabels[componentLabelName]) < 1 {
-				return "", fmt.Errorf(RequiredLabelNotFound, componentLabelName)
+				return "", clienterrors.Precondition(RequiredLabelNotFound, componentLabelName)
 			}
 			branchName = constants.PaCPullRequestBranchPrefix + prLabels[componentLabelName]
 		} else {
 			// No straightforward way to get a target branch from PR labels -> using annotation
 			if branchName, ok = pr.GetAnnotations()[targetBranchAnnotationName]; !ok {
-				return "", fmt.Errorf("cannot retrigger PipelineRun - required annotation %q not found", targetBranchAnnotationName)
+				return "", clienterrors.Precondition("cannot retrigger PipelineRun - required annotation %q not found", targetBranchAnnotationName)
 			}
 		}
 
@@ -492,7 +540,7 @@ func (h *HasController) RetriggerComponentPipelineRun(component *appservice.Comp
 	for {
 		select {
 		case <-time.After(5 * time.Minute):
-			return "", fmt.Errorf("timed out waiting for new PipelineRun to appear after retriggering it for component %s:%s", component.GetNamespace(), component.GetName())
+			return "", clienterrors.Timeout(fmt.Sprintf("new PipelineRun to appear after retriggering it for component %s:%s", component.GetNamespace(), component.GetName()), 5*time.Minute, "", nil)
 		case event := <-watch.ResultChan():
 			if event.Object == nil {
 				continue
@@ -575,17 +623,7 @@ func (h *HasController) StoreComponent(component *appservice.Component) error {
 
 // StoreAllComponents stores all Components in a given namespace.
 func (h *HasController) StoreAllComponents(namespace string) error {
-	componentList := &appservice.ComponentList{}
-	if err := h.KubeRest().List(context.Background(), componentList, &rclient.ListOptions{Namespace: namespace}); err != nil {
-		return err
-	}
-
-	for _, component := range componentList.Items {
-		if err := h.StoreComponent(&component); err != nil {
-			return err
-		}
-	}
-	return nil
+	return common.StoreAllResources[appservice.Component](h.KubeRest(), namespace, h.StoreComponent)
 }
 
 // UpdateComponent updates a component
@@ -1,15 +1,26 @@
 package imagecontroller
 
 import (
+	"net/http"
+
 	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/registry"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	"github.com/konflux-ci/image-controller/pkg/quay"
 )
 
 type ImageController struct {
 	*kubeCl.CustomClient
+	// QuayClient is used to verify the Quay repositories and robot accounts managed by image-controller
+	QuayClient *quay.QuayClient
+	QuayOrg    string
 }
 
 func NewSuiteController(kube *kubeCl.CustomClient) (*ImageController, error) {
 	return &ImageController{
-		kube,
+		CustomClient: kube,
+		QuayClient:   quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, utils.GetEnv(constants.DEFAULT_QUAY_ORG_TOKEN_ENV, ""), registry.QuayAPIURL),
+		QuayOrg:      utils.GetEnv(constants.DEFAULT_QUAY_ORG_ENV, "redhat-appstudio-qe"),
 	}, nil
 }
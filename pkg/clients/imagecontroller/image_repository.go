@@ -2,14 +2,27 @@ package imagecontroller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	"github.com/konflux-ci/image-controller/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 	rclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ComponentServiceAccountPrefix is the prefix of the service account build PipelineRuns of a Component run with
+const ComponentServiceAccountPrefix = "build-pipeline-"
+
 // CreateImageRepositoryCR creates new ImageRepository
 func (i *ImageController) CreateImageRepositoryCR(name, namespace, applicationName, componentName string) (*v1alpha1.ImageRepository, error) {
 	imageRepository := &v1alpha1.ImageRepository{
@@ -46,54 +59,252 @@ func (i *ImageController) GetImageRepositoryCR(name, namespace string) (*v1alpha
 	return &imageRepository, nil
 }
 
-// ChangeVisibilityToPrivate changes ImageRepository visibility to private
-func (i *ImageController) ChangeVisibilityToPrivate(namespace, applicationName, componentName string) (*v1alpha1.ImageRepository, error) {
+// GetComponentImageRepository returns the ImageRepository of the component
+func (i *ImageController) GetComponentImageRepository(namespace, componentName string) (*v1alpha1.ImageRepository, error) {
 	imageRepositoryList := &v1alpha1.ImageRepositoryList{}
 	imageRepoLabels := map[string]string{"appstudio.redhat.com/component": componentName}
 	err := i.KubeRest().List(context.Background(), imageRepositoryList, &rclient.ListOptions{LabelSelector: labels.SelectorFromSet(imageRepoLabels), Namespace: namespace})
 	if err != nil {
 		return nil, err
 	}
-	imageRepository := &imageRepositoryList.Items[0]
-	// update visibility to private
-	imageRepository.Spec.Image.Visibility = "private"
+	if len(imageRepositoryList.Items) == 0 {
+		return nil, clienterrors.NotFound("ImageRepository", namespace, componentName, "no ImageRepository found for component %s in %s namespace", componentName, namespace)
+	}
+	return &imageRepositoryList.Items[0], nil
+}
+
+// DeleteImageRepositoryCR deletes the ImageRepository. image-controller then deletes the Quay repository and its robot accounts.
+func (i *ImageController) DeleteImageRepositoryCR(imageRepository *v1alpha1.ImageRepository) error {
+	return rclient.IgnoreNotFound(i.KubeRest().Delete(context.Background(), imageRepository))
+}
+
+// ChangeVisibilityToPrivate changes ImageRepository visibility to private
+func (i *ImageController) ChangeVisibilityToPrivate(namespace, componentName string) (*v1alpha1.ImageRepository, error) {
+	return i.ChangeVisibility(namespace, componentName, v1alpha1.ImageVisibilityPrivate)
+}
+
+// ChangeVisibilityToPublic changes ImageRepository visibility to public
+func (i *ImageController) ChangeVisibilityToPublic(namespace, componentName string) (*v1alpha1.ImageRepository, error) {
+	return i.ChangeVisibility(namespace, componentName, v1alpha1.ImageVisibilityPublic)
+}
+
+// ChangeVisibility requests the given visibility of the ImageRepository of the component.
+// Use WaitForVisibility to wait until image-controller changes the visibility of the Quay repository.
+func (i *ImageController) ChangeVisibility(namespace, componentName string, visibility v1alpha1.ImageVisibility) (*v1alpha1.ImageRepository, error) {
+	return i.updateComponentImageRepository(namespace, componentName, func(imageRepository *v1alpha1.ImageRepository) {
+		imageRepository.Spec.Image.Visibility = visibility
+	})
+}
+
+// WaitForVisibility waits until the status of the ImageRepository of the component has the given visibility.
+func (i *ImageController) WaitForVisibility(namespace, componentName string, visibility v1alpha1.ImageVisibility, timeout time.Duration) (*v1alpha1.ImageRepository, error) {
+	return i.waitForComponentImageRepository(namespace, componentName, fmt.Sprintf("visibility %s", visibility), timeout, func(imageRepository *v1alpha1.ImageRepository) bool {
+		return imageRepository.Status.Image.Visibility == visibility
+	})
+}
 
-	err = i.KubeRest().Update(context.Background(), imageRepository)
+// RegenerateToken requests image-controller to regenerate the push and pull robot account tokens of the component.
+// The returned ImageRepository can be passed to WaitForTokenRegenerated.
+func (i *ImageController) RegenerateToken(namespace, componentName string) (*v1alpha1.ImageRepository, error) {
+	return i.updateComponentImageRepository(namespace, componentName, func(imageRepository *v1alpha1.ImageRepository) {
+		if imageRepository.Spec.Credentials == nil {
+			imageRepository.Spec.Credentials = &v1alpha1.ImageCredentials{}
+		}
+		imageRepository.Spec.Credentials.RegenerateToken = ptr.To(true)
+	})
+}
+
+// WaitForTokenRegenerated waits until image-controller cleared the regenerate-token request of the ImageRepository
+// and generated the credentials again after they were generated for the given ImageRepository.
+func (i *ImageController) WaitForTokenRegenerated(imageRepository *v1alpha1.ImageRepository, timeout time.Duration) (*v1alpha1.ImageRepository, error) {
+	previous := imageRepository.Status.Credentials.GenerationTimestamp
+	componentName := imageRepository.Labels["appstudio.redhat.com/component"]
+	return i.waitForComponentImageRepository(imageRepository.Namespace, componentName, "regenerated credentials", timeout, func(current *v1alpha1.ImageRepository) bool {
+		return IsTokenRegenerated(current, previous)
+	})
+}
+
+// IsTokenRegenerated returns true if the regenerate-token request of the ImageRepository was handled
+// and the credentials were generated after previous.
+func IsTokenRegenerated(imageRepository *v1alpha1.ImageRepository, previous *metav1.Time) bool {
+	if c := imageRepository.Spec.Credentials; c != nil && c.RegenerateToken != nil && *c.RegenerateToken {
+		return false
+	}
+	generated := imageRepository.Status.Credentials.GenerationTimestamp
+	return generated != nil && (previous == nil || generated.After(previous.Time))
+}
+
+// AddNotification adds the notification to the ImageRepository of the component.
+// Use WaitForNotification to wait until image-controller creates it in Quay.
+func (i *ImageController) AddNotification(namespace, componentName string, notification v1alpha1.Notifications) (*v1alpha1.ImageRepository, error) {
+	return i.updateComponentImageRepository(namespace, componentName, func(imageRepository *v1alpha1.ImageRepository) {
+		imageRepository.Spec.Notifications = append(imageRepository.Spec.Notifications, notification)
+	})
+}
+
+// WaitForNotification waits until the notification with the given title is created in Quay and returns its UUID.
+func (i *ImageController) WaitForNotification(namespace, componentName, title string, timeout time.Duration) (string, error) {
+	var uuid string
+	_, err := i.waitForComponentImageRepository(namespace, componentName, fmt.Sprintf("notification %q", title), timeout, func(imageRepository *v1alpha1.ImageRepository) bool {
+		for _, n := range imageRepository.Status.Notifications {
+			if n.Title == title && n.UUID != "" {
+				uuid = n.UUID
+				return true
+			}
+		}
+		return false
+	})
+	return uuid, err
+}
+
+// VerifySecretsLinkedToServiceAccount checks the push and pull secrets of the component exist and are linked to
+// the service account the build PipelineRuns of the component run with.
+func (i *ImageController) VerifySecretsLinkedToServiceAccount(namespace, componentName string) error {
+	imageRepository, err := i.GetComponentImageRepository(namespace, componentName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return imageRepository, nil
+	credentials := imageRepository.Status.Credentials
+	secretNames := []string{credentials.PushSecretName, credentials.PullSecretName}
+	for _, name := range secretNames {
+		if name == "" {
+			return clienterrors.Precondition("ImageRepository %s/%s has no push or pull secret yet", namespace, imageRepository.Name)
+		}
+		if _, err := i.KubeInterface().CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("failed to get secret %s/%s of ImageRepository %s: %+v", namespace, name, imageRepository.Name, err)
+		}
+	}
+	serviceAccountName := ComponentServiceAccountPrefix + componentName
+	serviceAccount, err := i.KubeInterface().CoreV1().ServiceAccounts(namespace).Get(context.Background(), serviceAccountName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get service account %s/%s: %+v", namespace, serviceAccountName, err)
+	}
+	return CheckSecretsLinked(serviceAccount, secretNames...)
+}
+
+// CheckSecretsLinked returns an error if any of the secrets is neither a secret nor an image pull secret of the service account.
+func CheckSecretsLinked(serviceAccount *corev1.ServiceAccount, secretNames ...string) error {
+	var missing []string
+	for _, name := range secretNames {
+		linked := slices.ContainsFunc(serviceAccount.Secrets, func(s corev1.ObjectReference) bool { return s.Name == name }) ||
+			slices.ContainsFunc(serviceAccount.ImagePullSecrets, func(s corev1.LocalObjectReference) bool { return s.Name == name })
+		if !linked {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("secrets %v are not linked to service account %s/%s", missing, serviceAccount.Namespace, serviceAccount.Name)
+	}
+	return nil
+}
+
+// WaitForImageRepositoryCleanup waits until the deleted ImageRepository is gone together with its Quay repository and robot accounts.
+func (i *ImageController) WaitForImageRepositoryCleanup(imageRepository *v1alpha1.ImageRepository, timeout time.Duration) error {
+	var lastState string
+	operation := fmt.Sprintf("ImageRepository %s/%s to be cleaned up", imageRepository.Namespace, imageRepository.Name)
+	err := utils.WaitUntilWithInterval(func() (done bool, err error) {
+		if _, err := i.GetImageRepositoryCR(imageRepository.Name, imageRepository.Namespace); !k8sErrors.IsNotFound(err) {
+			lastState = "ImageRepository still exists"
+			return false, nil
+		}
+		if remaining, err := i.remainingQuayResources(imageRepository); err != nil || len(remaining) > 0 {
+			lastState = fmt.Sprintf("remaining Quay resources: %v, error: %v", remaining, err)
+			GinkgoWriter.Printf("%s: %s\n", operation, lastState)
+			return false, nil
+		}
+		return true, nil
+	}, time.Second*5, timeout)
+	return clienterrors.WrapTimeout(err, operation, timeout, lastState, nil)
+}
+
+// remainingQuayResources returns the Quay repository and robot accounts of the ImageRepository which still exist.
+func (i *ImageController) remainingQuayResources(imageRepository *v1alpha1.ImageRepository) ([]string, error) {
+	var remaining []string
+	if name := imageRepository.Spec.Image.Name; name != "" {
+		exists, err := DoesQuayRepositoryExist(i.QuayClient, i.QuayOrg, name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			remaining = append(remaining, "repository "+name)
+		}
+	}
+	credentials := imageRepository.Status.Credentials
+	for _, robot := range []string{credentials.PushRobotAccountName, credentials.PullRobotAccountName} {
+		if robot == "" {
+			continue
+		}
+		exists, err := DoesQuayRobotAccountExist(i.QuayClient, i.QuayOrg, robot)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			remaining = append(remaining, "robot account "+robot)
+		}
+	}
+	return remaining, nil
 }
 
 // GetImageName returns the image repo name for the component
 func (i *ImageController) GetImageName(namespace, componentName string) (string, error) {
-	imageRepositoryList := &v1alpha1.ImageRepositoryList{}
-	imageRepoLabels := map[string]string{"appstudio.redhat.com/component": componentName}
-	err := i.KubeRest().List(context.Background(), imageRepositoryList, &rclient.ListOptions{LabelSelector: labels.SelectorFromSet(imageRepoLabels), Namespace: namespace})
+	imageRepository, err := i.GetComponentImageRepository(namespace, componentName)
 	if err != nil {
 		return "", err
 	}
-	return imageRepositoryList.Items[0].Spec.Image.Name, err
+	return imageRepository.Spec.Image.Name, nil
 }
 
 // GetRobotAccounts returns the pull and push robot accounts for the component
 func (i *ImageController) GetRobotAccounts(namespace, componentName string) (string, string, error) {
-	imageRepositoryList := &v1alpha1.ImageRepositoryList{}
-	imageRepoLabels := map[string]string{"appstudio.redhat.com/component": componentName}
-	err := i.KubeRest().List(context.Background(), imageRepositoryList, &rclient.ListOptions{LabelSelector: labels.SelectorFromSet(imageRepoLabels), Namespace: namespace})
+	imageRepository, err := i.GetComponentImageRepository(namespace, componentName)
 	if err != nil {
 		return "", "", err
 	}
-	return imageRepositoryList.Items[0].Status.Credentials.PullRobotAccountName, imageRepositoryList.Items[0].Status.Credentials.PushRobotAccountName, nil
+	return imageRepository.Status.Credentials.PullRobotAccountName, imageRepository.Status.Credentials.PushRobotAccountName, nil
 }
 
 // IsVisibilityPublic returns true if imageRepository CR has spec.image.visibility == "public", otherwise false
 func (i *ImageController) IsVisibilityPublic(namespace, componentName string) (bool, error) {
-	imageRepositoryList := &v1alpha1.ImageRepositoryList{}
-	imageRepoLabels := map[string]string{"appstudio.redhat.com/component": componentName}
-	err := i.KubeRest().List(context.Background(), imageRepositoryList, &rclient.ListOptions{LabelSelector: labels.SelectorFromSet(imageRepoLabels), Namespace: namespace})
+	imageRepository, err := i.GetComponentImageRepository(namespace, componentName)
 	if err != nil {
 		return false, err
 	}
-	return imageRepositoryList.Items[0].Spec.Image.Visibility == "public", nil
+	return imageRepository.Spec.Image.Visibility == v1alpha1.ImageVisibilityPublic, nil
+}
+
+func (i *ImageController) updateComponentImageRepository(namespace, componentName string, mutate func(imageRepository *v1alpha1.ImageRepository)) (*v1alpha1.ImageRepository, error) {
+	var imageRepository *v1alpha1.ImageRepository
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		if imageRepository, err = i.GetComponentImageRepository(namespace, componentName); err != nil {
+			return err
+		}
+		mutate(imageRepository)
+		return i.KubeRest().Update(context.Background(), imageRepository)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return imageRepository, nil
+}
+
+// waitForComponentImageRepository waits until the ImageRepository of the component satisfies the condition.
+// It fails as soon as image-controller reports the ImageRepository failed.
+func (i *ImageController) waitForComponentImageRepository(namespace, componentName, what string, timeout time.Duration, condition func(imageRepository *v1alpha1.ImageRepository) bool) (*v1alpha1.ImageRepository, error) {
+	var imageRepository *v1alpha1.ImageRepository
+	var lastErr error
+	var lastState string
+	err := utils.WaitUntilWithInterval(func() (done bool, err error) {
+		imageRepository, lastErr = i.GetComponentImageRepository(namespace, componentName)
+		if lastErr != nil {
+			return false, nil
+		}
+		status := imageRepository.Status
+		if status.State == v1alpha1.ImageRepositoryStateFailed {
+			return false, fmt.Errorf("ImageRepository %s/%s failed: %s", namespace, imageRepository.Name, status.Message)
+		}
+		lastState = fmt.Sprintf("state %q, visibility %q, message %q", status.State, status.Image.Visibility, status.Message)
+		return condition(imageRepository), nil
+	}, time.Second*2, timeout)
+	operation := fmt.Sprintf("%s of ImageRepository of component %s/%s", what, namespace, componentName)
+	return imageRepository, clienterrors.WrapTimeout(err, operation, timeout, lastState, lastErr)
 }
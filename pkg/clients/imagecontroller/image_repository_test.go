@@ -0,0 +1,67 @@
+package imagecontroller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/image-controller/api/v1alpha1"
+	"github.com/konflux-ci/image-controller/pkg/quay"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestCheckSecretsLinked(t *testing.T) {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: "build-pipeline-comp", Namespace: "ns"},
+		Secrets:          []corev1.ObjectReference{{Name: "imagerepository-for-app-comp-image-push"}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "imagerepository-for-app-comp-image-pull"}},
+	}
+	assert.NoError(t, CheckSecretsLinked(sa, "imagerepository-for-app-comp-image-push", "imagerepository-for-app-comp-image-pull"))
+	assert.EqualError(t, CheckSecretsLinked(sa, "imagerepository-for-app-comp-image-push", "other"), "secrets [other] are not linked to service account ns/build-pipeline-comp")
+}
+
+func TestIsTokenRegenerated(t *testing.T) {
+	previous := metav1.NewTime(time.Now().Add(-time.Minute))
+	imageRepository := &v1alpha1.ImageRepository{}
+	imageRepository.Spec.Credentials = &v1alpha1.ImageCredentials{RegenerateToken: ptr.To(true)}
+	imageRepository.Status.Credentials.GenerationTimestamp = &previous
+	assert.False(t, IsTokenRegenerated(imageRepository, &previous))
+
+	imageRepository.Spec.Credentials.RegenerateToken = nil
+	assert.False(t, IsTokenRegenerated(imageRepository, &previous))
+
+	now := metav1.Now()
+	imageRepository.Status.Credentials.GenerationTimestamp = &now
+	assert.True(t, IsTokenRegenerated(imageRepository, &previous))
+}
+
+func TestRemainingQuayResources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repository/org/ns/comp":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_message": "Not Found"}`))
+		case "/organization/org/robots/ns_comp_push":
+			_, _ = w.Write([]byte(`{"name": "org+ns_comp_push", "token": "secret"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "Could not find robot with specified username"}`))
+		}
+	}))
+	defer server.Close()
+
+	i := &ImageController{QuayClient: quay.NewQuayClient(server.Client(), "token", server.URL), QuayOrg: "org"}
+	imageRepository := &v1alpha1.ImageRepository{}
+	imageRepository.Spec.Image.Name = "ns/comp"
+	imageRepository.Status.Credentials.PushRobotAccountName = "org+ns_comp_push"
+	imageRepository.Status.Credentials.PullRobotAccountName = "org+ns_comp_pull"
+
+	remaining, err := i.remainingQuayResources(imageRepository)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"robot account org+ns_comp_push"}, remaining)
+}
@@ -0,0 +1,29 @@
+package imagecontroller
+
+import (
+	"strings"
+
+	"github.com/konflux-ci/image-controller/pkg/quay"
+)
+
+// DoesQuayRepositoryExist returns false when the repository doesn't exist in the Quay organization.
+func DoesQuayRepositoryExist(client *quay.QuayClient, org, repository string) (bool, error) {
+	exists, err := client.DoesRepositoryExist(org, repository)
+	if err != nil && strings.Contains(err.Error(), "does not exist") {
+		return false, nil
+	}
+	return exists, err
+}
+
+// DoesQuayRobotAccountExist returns false when the robot account doesn't exist in the Quay organization.
+// The robot account name can be the full name with the organization prefix, as in the ImageRepository status.
+func DoesQuayRobotAccountExist(client *quay.QuayClient, org, robotAccountName string) (bool, error) {
+	_, err := client.GetRobotAccount(org, strings.TrimPrefix(robotAccountName, org+"+"))
+	if err != nil {
+		if err.Error() == "Could not find robot with specified username" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	"github.com/konflux-ci/e2e-tests/pkg/utils/tekton"
@@ -120,16 +121,18 @@ func (i *IntegrationController) GetIntegrationPipelineRun(integrationTestScenari
 		return &list.Items[0], nil
 	}
 
-	return &tektonv1.PipelineRun{}, fmt.Errorf("no pipelinerun found for integrationTestScenario %s (snapshot: %s, namespace: %s)", integrationTestScenarioName, snapshotName, namespace)
+	return &tektonv1.PipelineRun{}, clienterrors.NotFound("PipelineRun", namespace, integrationTestScenarioName, "no pipelinerun found for integrationTestScenario %s (snapshot: %s, namespace: %s)", integrationTestScenarioName, snapshotName, namespace)
 }
 
 // WaitForIntegrationPipelineToGetStarted wait for given integration pipeline to get started.
 // In case of failure, this function retries till it gets timed out.
 func (i *IntegrationController) WaitForIntegrationPipelineToGetStarted(testScenarioName, snapshotName, appNamespace string) (*tektonv1.PipelineRun, error) {
 	var testPipelinerun *tektonv1.PipelineRun
+	var lastErr error
 
 	err := wait.PollUntilContextTimeout(context.Background(), time.Second*2, time.Minute*5, true, func(ctx context.Context) (done bool, err error) {
 		testPipelinerun, err = i.GetIntegrationPipelineRun(testScenarioName, snapshotName, appNamespace)
+		lastErr = err
 		if err != nil {
 			GinkgoWriter.Println("PipelineRun has not been created yet for test scenario %s and snapshot %s/%s", testScenarioName, appNamespace, snapshotName)
 			return false, nil
@@ -140,20 +143,25 @@ func (i *IntegrationController) WaitForIntegrationPipelineToGetStarted(testScena
 		}
 		return true, nil
 	})
+	operation := fmt.Sprintf("integration PipelineRun of scenario %s and snapshot %s/%s to start", testScenarioName, appNamespace, snapshotName)
 
-	return testPipelinerun, err
+	return testPipelinerun, clienterrors.WrapTimeout(err, operation, time.Minute*5, "", lastErr)
 }
 
 // WaitForIntegrationPipelineToBeFinished wait for given integration pipeline to finish.
 // In case of failure, this function retries till it gets timed out.
 func (i *IntegrationController) WaitForIntegrationPipelineToBeFinished(testScenario *integrationv1beta2.IntegrationTestScenario, snapshot *appstudioApi.Snapshot, appNamespace string) error {
-	return wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 20*time.Minute, true, func(ctx context.Context) (done bool, err error) {
+	var lastErr error
+	var lastState string
+	err := wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 20*time.Minute, true, func(ctx context.Context) (done bool, err error) {
 		pipelineRun, err := i.GetIntegrationPipelineRun(testScenario.Name, snapshot.Name, appNamespace)
+		lastErr = err
 		if err != nil {
 			GinkgoWriter.Println("PipelineRun has not been created yet for test scenario %s and snapshot %s/%s", testScenario.GetName(), snapshot.GetNamespace(), snapshot.GetName())
 			return false, nil
 		}
-		GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pipelineRun.Name, pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason())
+		lastState = fmt.Sprintf("PipelineRun %s reason: %s", pipelineRun.Name, pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).GetReason())
+		GinkgoWriter.Printf("%s\n", lastState)
 
 		if !pipelineRun.IsDone() {
 			return false, nil
@@ -162,12 +170,11 @@ func (i *IntegrationController) WaitForIntegrationPipelineToBeFinished(testScena
 		if pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
 			return true, nil
 		}
-		var prLogs string
-		if prLogs, err = tekton.GetFailedPipelineRunLogs(i.KubeRest(), i.KubeInterface(), pipelineRun); err != nil {
-			return false, fmt.Errorf("failed to get PLR logs: %+v", err)
-		}
-		return false, fmt.Errorf("%s", prLogs)
+		return false, tekton.NewPipelineFailedError(i.KubeRest(), i.KubeInterface(), pipelineRun, true)
 	})
+	operation := fmt.Sprintf("integration PipelineRun of scenario %s and snapshot %s/%s to finish", testScenario.GetName(), snapshot.GetNamespace(), snapshot.GetName())
+
+	return clienterrors.WrapTimeout(err, operation, 20*time.Minute, lastState, lastErr)
 }
 
 func (i *IntegrationController) isScenarioInExpectedScenarios(testScenario *integrationv1beta2.IntegrationTestScenario, expectedTestScenarios []string) bool {
@@ -192,7 +199,7 @@ func (i *IntegrationController) WaitForAllIntegrationPipelinesToBeFinished(testN
 			GinkgoWriter.Printf("Integration test scenario %s is found\n", testScenario.Name)
 			err = i.WaitForIntegrationPipelineToBeFinished(&testScenario, snapshot, testNamespace)
 			if err != nil {
-				return fmt.Errorf("error occurred while waiting for Integration PLR (associated with IntegrationTestScenario: %s) to get finished in %s namespace. Error: %v", testScenario.Name, testNamespace, err)
+				return fmt.Errorf("error occurred while waiting for Integration PLR (associated with IntegrationTestScenario: %s) to get finished in %s namespace. Error: %w", testScenario.Name, testNamespace, err)
 			}
 		}
 	}
@@ -271,7 +278,7 @@ func (i *IntegrationController) WaitForBuildPipelineRunToGetAnnotated(testNamesp
 // WaitForBuildPipelineToBeFinished wait for given build pipeline to finish.
 // It exposes the error message from the failed task to the end user when the pipelineRun failed.
 func (i *IntegrationController) WaitForBuildPipelineToBeFinished(testNamespace, applicationName, componentName, sha string) error {
-	return wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 30*time.Minute, true, func(ctx context.Context) (done bool, err error) {
+	err := wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 30*time.Minute, true, func(ctx context.Context) (done bool, err error) {
 		pipelineRun, err := i.GetBuildPipelineRun(componentName, applicationName, testNamespace, false, sha)
 		if err != nil {
 			GinkgoWriter.Println("Build pipelineRun has not been created yet for app %s/%s, and component %s", testNamespace, applicationName, componentName)
@@ -287,12 +294,14 @@ func (i *IntegrationController) WaitForBuildPipelineToBeFinished(testNamespace,
 			if pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
 				return true, nil
 			} else {
-				logs, _ := tekton.GetFailedPipelineRunLogs(i.KubeRest(), i.KubeInterface(), pipelineRun)
-				return false, fmt.Errorf("%s", logs)
+				return false, tekton.NewPipelineFailedError(i.KubeRest(), i.KubeInterface(), pipelineRun, true)
 			}
 		}
 		return false, nil
 	})
+	operation := fmt.Sprintf("build PipelineRun of component %s/%s to finish", testNamespace, componentName)
+
+	return clienterrors.WrapTimeout(err, operation, 30*time.Minute, "", nil)
 }
 
 func (i *IntegrationController) IsIntegrationPipelinerunCancelled(integrationTestScenarioName string, snapshot *appstudioApi.Snapshot) (bool, error) {
@@ -0,0 +1,323 @@
+package integration
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/devfile/library/v2/pkg/util"
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// SnapshotTypeLabel contains the type of the Snapshot (component, override, group, manual)
+	SnapshotTypeLabel = "test.appstudio.openshift.io/type"
+	// SnapshotComponentLabel contains the name of the Component the Snapshot was created for
+	SnapshotComponentLabel = "appstudio.openshift.io/component"
+	// SnapshotEventTypeLabel contains the PaC event type (push, pull_request) which triggered the Snapshot
+	SnapshotEventTypeLabel = "pac.test.appstudio.openshift.io/event-type"
+	// SnapshotPRGroupAnnotation contains the name of the PR group of a group Snapshot
+	SnapshotPRGroupAnnotation = "test.appstudio.openshift.io/pr-group"
+	// SnapshotPRGroupShaLabel contains the sha of the PR group name of a group Snapshot
+	SnapshotPRGroupShaLabel = "test.appstudio.openshift.io/pr-group-sha"
+
+	// SnapshotTypeComponent is the type of Snapshot created for a single Component build
+	SnapshotTypeComponent = "component"
+	// SnapshotTypeOverride is the type of Snapshot used to override the Global Candidate List
+	SnapshotTypeOverride = "override"
+	// SnapshotTypeGroup is the type of Snapshot created for a PR group
+	SnapshotTypeGroup = "group"
+	// SnapshotTypeManual is the type of Snapshot created manually by the user
+	SnapshotTypeManual = "manual"
+)
+
+var (
+	dns1123LabelRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	digestRegexp       = regexp.MustCompile(`@sha256:[a-f0-9]{64}$`)
+)
+
+// SnapshotBuilder builds Snapshot objects in a fluent way.
+// Use NewSnapshotBuilder to get a builder with the defaults used by e2e tests.
+type SnapshotBuilder struct {
+	snapshot *appstudioApi.Snapshot
+}
+
+// NewSnapshotBuilder returns a SnapshotBuilder for a push "component" Snapshot of the given application.
+func NewSnapshotBuilder(applicationName, namespace string) *SnapshotBuilder {
+	return &SnapshotBuilder{
+		snapshot: &appstudioApi.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "snapshot-sample-" + util.GenerateRandomString(4),
+				Namespace:   namespace,
+				Labels:      map[string]string{SnapshotTypeLabel: SnapshotTypeComponent, SnapshotEventTypeLabel: "push"},
+				Annotations: map[string]string{},
+			},
+			Spec: appstudioApi.SnapshotSpec{
+				Application: applicationName,
+			},
+		},
+	}
+}
+
+// WithName sets the name of the Snapshot.
+func (b *SnapshotBuilder) WithName(name string) *SnapshotBuilder {
+	b.snapshot.Name = name
+	return b
+}
+
+// ForComponent sets the component label of the Snapshot.
+func (b *SnapshotBuilder) ForComponent(componentName string) *SnapshotBuilder {
+	b.snapshot.Labels[SnapshotComponentLabel] = componentName
+	return b
+}
+
+// WithComponents appends the given components to the Snapshot.
+func (b *SnapshotBuilder) WithComponents(components ...appstudioApi.SnapshotComponent) *SnapshotBuilder {
+	b.snapshot.Spec.Components = append(b.snapshot.Spec.Components, components...)
+	return b
+}
+
+// WithImage appends a component with the given container image to the Snapshot.
+func (b *SnapshotBuilder) WithImage(componentName, containerImage string) *SnapshotBuilder {
+	return b.WithComponents(appstudioApi.SnapshotComponent{Name: componentName, ContainerImage: containerImage})
+}
+
+// WithGitSource sets the git source of an already added component.
+// If the component was not added yet, it is added without a container image.
+func (b *SnapshotBuilder) WithGitSource(componentName, url, revision string) *SnapshotBuilder {
+	source := appstudioApi.ComponentSource{
+		ComponentSourceUnion: appstudioApi.ComponentSourceUnion{
+			GitSource: &appstudioApi.GitSource{URL: url, Revision: revision},
+		},
+	}
+	for i := range b.snapshot.Spec.Components {
+		if b.snapshot.Spec.Components[i].Name == componentName {
+			b.snapshot.Spec.Components[i].Source = source
+			return b
+		}
+	}
+	return b.WithComponents(appstudioApi.SnapshotComponent{Name: componentName, Source: source})
+}
+
+// AsPullRequest marks the Snapshot as created by a pull_request event.
+func (b *SnapshotBuilder) AsPullRequest() *SnapshotBuilder {
+	b.snapshot.Labels[SnapshotEventTypeLabel] = "pull_request"
+	return b
+}
+
+// AsOverride marks the Snapshot as an override Snapshot.
+func (b *SnapshotBuilder) AsOverride() *SnapshotBuilder {
+	b.snapshot.Labels[SnapshotTypeLabel] = SnapshotTypeOverride
+	delete(b.snapshot.Labels, SnapshotEventTypeLabel)
+	return b
+}
+
+// InPRGroup marks the Snapshot as a group Snapshot of the given PR group.
+func (b *SnapshotBuilder) InPRGroup(prGroup, prGroupSha string) *SnapshotBuilder {
+	b.snapshot.Labels[SnapshotTypeLabel] = SnapshotTypeGroup
+	b.snapshot.Labels[SnapshotEventTypeLabel] = "pull_request"
+	b.snapshot.Annotations[SnapshotPRGroupAnnotation] = prGroup
+	if prGroupSha != "" {
+		b.snapshot.Labels[SnapshotPRGroupShaLabel] = prGroupSha
+	}
+	return b
+}
+
+// WithLabel sets a label on the Snapshot.
+func (b *SnapshotBuilder) WithLabel(key, value string) *SnapshotBuilder {
+	b.snapshot.Labels[key] = value
+	return b
+}
+
+// WithAnnotation sets an annotation on the Snapshot.
+func (b *SnapshotBuilder) WithAnnotation(key, value string) *SnapshotBuilder {
+	b.snapshot.Annotations[key] = value
+	return b
+}
+
+// Build returns a copy of the built Snapshot without validating it.
+func (b *SnapshotBuilder) Build() *appstudioApi.Snapshot {
+	return b.snapshot.DeepCopy()
+}
+
+// BuildAndValidate returns a copy of the built Snapshot or an error if it doesn't pass ValidateSnapshot.
+func (b *SnapshotBuilder) BuildAndValidate() (*appstudioApi.Snapshot, error) {
+	snapshot := b.Build()
+	if err := ValidateSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// ValidateSnapshot checks the Snapshot against the rules enforced by integration-service on admission:
+// application and components are required, component names must be unique DNS-1123 labels,
+// container images must be pinned by digest and git sources need a url and a revision.
+func ValidateSnapshot(snapshot *appstudioApi.Snapshot) error {
+	var errs []string
+
+	if snapshot.Spec.Application == "" {
+		errs = append(errs, "spec.application is required")
+	}
+	if len(snapshot.Spec.Components) == 0 {
+		errs = append(errs, "spec.components must contain at least one component")
+	}
+	seen := map[string]bool{}
+	for i, component := range snapshot.Spec.Components {
+		path := fmt.Sprintf("spec.components[%d]", i)
+		if !dns1123LabelRegexp.MatchString(component.Name) || len(component.Name) > 63 {
+			errs = append(errs, fmt.Sprintf("%s.name '%s' is not a valid DNS-1123 label", path, component.Name))
+		}
+		if seen[component.Name] {
+			errs = append(errs, fmt.Sprintf("%s.name '%s' is duplicated", path, component.Name))
+		}
+		seen[component.Name] = true
+		if !digestRegexp.MatchString(component.ContainerImage) {
+			errs = append(errs, fmt.Sprintf("%s.containerImage '%s' is not pinned by a sha256 digest", path, component.ContainerImage))
+		}
+		if git := component.Source.GitSource; git != nil && (git.URL == "" || git.Revision == "") {
+			errs = append(errs, fmt.Sprintf("%s.source.git requires both url and revision", path))
+		}
+	}
+
+	switch snapshotType := snapshot.Labels[SnapshotTypeLabel]; snapshotType {
+	case "", SnapshotTypeComponent, SnapshotTypeOverride, SnapshotTypeManual:
+	case SnapshotTypeGroup:
+		if snapshot.Annotations[SnapshotPRGroupAnnotation] == "" {
+			errs = append(errs, fmt.Sprintf("group snapshot requires the '%s' annotation", SnapshotPRGroupAnnotation))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("unknown snapshot type '%s'", snapshotType))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("snapshot '%s' is invalid: %s", snapshot.Name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// SnapshotDiff describes the structural differences between two Snapshots.
+type SnapshotDiff struct {
+	Application       [2]string
+	AddedComponents   []string
+	RemovedComponents []string
+	// ChangedComponents maps a component name to the list of its changed fields
+	ChangedComponents  map[string][]string
+	ChangedLabels      []string
+	ChangedAnnotations []string
+}
+
+// IsEmpty returns true if there are no differences.
+func (d *SnapshotDiff) IsEmpty() bool {
+	return d.Application[0] == d.Application[1] && len(d.AddedComponents) == 0 && len(d.RemovedComponents) == 0 &&
+		len(d.ChangedComponents) == 0 && len(d.ChangedLabels) == 0 && len(d.ChangedAnnotations) == 0
+}
+
+// String returns a human readable report of the differences, suitable for assertion messages and failure artifacts.
+func (d *SnapshotDiff) String() string {
+	if d.IsEmpty() {
+		return "snapshots are equal"
+	}
+	var sb strings.Builder
+	if d.Application[0] != d.Application[1] {
+		fmt.Fprintf(&sb, "application: '%s' -> '%s'\n", d.Application[0], d.Application[1])
+	}
+	for _, name := range d.AddedComponents {
+		fmt.Fprintf(&sb, "+ component %s\n", name)
+	}
+	for _, name := range d.RemovedComponents {
+		fmt.Fprintf(&sb, "- component %s\n", name)
+	}
+	names := make([]string, 0, len(d.ChangedComponents))
+	for name := range d.ChangedComponents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "~ component %s: %s\n", name, strings.Join(d.ChangedComponents[name], ", "))
+	}
+	for _, l := range d.ChangedLabels {
+		fmt.Fprintf(&sb, "~ label %s\n", l)
+	}
+	for _, a := range d.ChangedAnnotations {
+		fmt.Fprintf(&sb, "~ annotation %s\n", a)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// DiffSnapshots returns the structural differences between the oldSnapshot and the newSnapshot.
+// Components are matched by name, so their order doesn't matter.
+func DiffSnapshots(oldSnapshot, newSnapshot *appstudioApi.Snapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		Application:       [2]string{oldSnapshot.Spec.Application, newSnapshot.Spec.Application},
+		ChangedComponents: map[string][]string{},
+	}
+
+	oldComponents := map[string]appstudioApi.SnapshotComponent{}
+	for _, c := range oldSnapshot.Spec.Components {
+		oldComponents[c.Name] = c
+	}
+	newComponents := map[string]appstudioApi.SnapshotComponent{}
+	for _, c := range newSnapshot.Spec.Components {
+		newComponents[c.Name] = c
+		oc, ok := oldComponents[c.Name]
+		if !ok {
+			diff.AddedComponents = append(diff.AddedComponents, c.Name)
+			continue
+		}
+		if changes := diffSnapshotComponents(oc, c); len(changes) > 0 {
+			diff.ChangedComponents[c.Name] = changes
+		}
+	}
+	for _, c := range oldSnapshot.Spec.Components {
+		if _, ok := newComponents[c.Name]; !ok {
+			diff.RemovedComponents = append(diff.RemovedComponents, c.Name)
+		}
+	}
+	sort.Strings(diff.AddedComponents)
+	sort.Strings(diff.RemovedComponents)
+
+	diff.ChangedLabels = diffStringMaps(oldSnapshot.Labels, newSnapshot.Labels)
+	diff.ChangedAnnotations = diffStringMaps(oldSnapshot.Annotations, newSnapshot.Annotations)
+	return diff
+}
+
+func diffSnapshotComponents(old, new appstudioApi.SnapshotComponent) []string {
+	var changes []string
+	if old.ContainerImage != new.ContainerImage {
+		changes = append(changes, fmt.Sprintf("containerImage '%s' -> '%s'", old.ContainerImage, new.ContainerImage))
+	}
+	var oldGit, newGit appstudioApi.GitSource
+	if old.Source.GitSource != nil {
+		oldGit = *old.Source.GitSource
+	}
+	if new.Source.GitSource != nil {
+		newGit = *new.Source.GitSource
+	}
+	if oldGit.URL != newGit.URL {
+		changes = append(changes, fmt.Sprintf("git.url '%s' -> '%s'", oldGit.URL, newGit.URL))
+	}
+	if oldGit.Revision != newGit.Revision {
+		changes = append(changes, fmt.Sprintf("git.revision '%s' -> '%s'", oldGit.Revision, newGit.Revision))
+	}
+	return changes
+}
+
+func diffStringMaps(old, new map[string]string) []string {
+	var changes []string
+	for k, v := range new {
+		if ov, ok := old[k]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: added '%s'", k, v))
+		} else if ov != v {
+			changes = append(changes, fmt.Sprintf("%s: '%s' -> '%s'", k, ov, v))
+		}
+	}
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: removed", k))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
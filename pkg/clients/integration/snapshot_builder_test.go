@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testImage = "quay.io/redhat-appstudio/sample-image@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestSnapshotBuilderDefaults(t *testing.T) {
+	snapshot := NewSnapshotBuilder("app", "ns").WithImage("comp", testImage).ForComponent("comp").Build()
+
+	assert.Equal(t, "ns", snapshot.Namespace)
+	assert.Equal(t, "app", snapshot.Spec.Application)
+	assert.Equal(t, SnapshotTypeComponent, snapshot.Labels[SnapshotTypeLabel])
+	assert.Equal(t, "push", snapshot.Labels[SnapshotEventTypeLabel])
+	assert.Equal(t, "comp", snapshot.Labels[SnapshotComponentLabel])
+	assert.True(t, strings.HasPrefix(snapshot.Name, "snapshot-sample-"))
+	assert.NoError(t, ValidateSnapshot(snapshot))
+}
+
+func TestSnapshotBuilderGitSourceAndGroup(t *testing.T) {
+	snapshot, err := NewSnapshotBuilder("app", "ns").
+		WithImage("comp-a", testImage).
+		WithGitSource("comp-a", "https://github.com/org/repo", "abc").
+		InPRGroup("feature", "sha").
+		BuildAndValidate()
+
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Spec.Components, 1)
+	assert.Equal(t, "abc", snapshot.Spec.Components[0].Source.GitSource.Revision)
+	assert.Equal(t, SnapshotTypeGroup, snapshot.Labels[SnapshotTypeLabel])
+	assert.Equal(t, "feature", snapshot.Annotations[SnapshotPRGroupAnnotation])
+}
+
+func TestValidateSnapshot(t *testing.T) {
+	_, err := NewSnapshotBuilder("", "ns").BuildAndValidate()
+	assert.ErrorContains(t, err, "spec.application is required")
+	assert.ErrorContains(t, err, "at least one component")
+
+	_, err = NewSnapshotBuilder("app", "ns").
+		WithImage("Comp", "quay.io/org/repo:latest").
+		WithImage("Comp", testImage).
+		WithGitSource("Comp", "", "").
+		BuildAndValidate()
+	assert.ErrorContains(t, err, "not a valid DNS-1123 label")
+	assert.ErrorContains(t, err, "is duplicated")
+	assert.ErrorContains(t, err, "not pinned by a sha256 digest")
+	assert.ErrorContains(t, err, "requires both url and revision")
+
+	_, err = NewSnapshotBuilder("app", "ns").WithImage("comp", testImage).WithLabel(SnapshotTypeLabel, SnapshotTypeGroup).BuildAndValidate()
+	assert.ErrorContains(t, err, SnapshotPRGroupAnnotation)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	base := NewSnapshotBuilder("app", "ns").WithImage("a", testImage).WithImage("b", testImage)
+	old := base.Build()
+	assert.True(t, DiffSnapshots(old, base.Build()).IsEmpty())
+
+	updated := NewSnapshotBuilder("app", "ns").
+		WithImage("c", testImage).
+		WithImage("a", "quay.io/org/a@sha256:new").
+		AsOverride().
+		Build()
+
+	diff := DiffSnapshots(old, updated)
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, []string{"c"}, diff.AddedComponents)
+	assert.Equal(t, []string{"b"}, diff.RemovedComponents)
+	assert.Contains(t, diff.ChangedComponents, "a")
+	assert.Contains(t, diff.ChangedLabels, SnapshotEventTypeLabel+": removed")
+	assert.Contains(t, diff.String(), "~ component a: containerImage")
+}
+
+func TestSortSnapshotsNewestFirst(t *testing.T) {
+	withStartTime := func(name, startTime string, created int64) appstudioApi.Snapshot {
+		snapshot := NewSnapshotBuilder("app", "ns").WithName(name).Build()
+		snapshot.CreationTimestamp = metav1.Unix(created, 0)
+		if startTime != "" {
+			snapshot.Annotations[BuildPipelineRunStartTime] = startTime
+		}
+		return *snapshot
+	}
+	// "group" has no build start time, so its creation time is used
+	snapshots := []appstudioApi.Snapshot{
+		withStartTime("old", "100", 500),
+		withStartTime("group", "", 300),
+		withStartTime("new-b", "200", 50),
+		withStartTime("new-a", "200", 60),
+	}
+
+	i := &IntegrationController{}
+	assert.Equal(t, "group", i.LatestSnapshot(snapshots).Name)
+	assert.Equal(t, "old", snapshots[0].Name, "LatestSnapshot must not reorder the snapshots")
+	assert.Nil(t, i.LatestSnapshot(nil))
+
+	names := []string{}
+	for _, snapshot := range i.SortSnapshots(snapshots) {
+		names = append(names, snapshot.Name)
+	}
+	assert.Equal(t, []string{"group", "new-a", "new-b", "old"}, names)
+
+	// Same snapshots listed in a different order end up in the same order
+	reversed := []appstudioApi.Snapshot{snapshots[3], snapshots[2], snapshots[1], snapshots[0]}
+	assert.Equal(t, snapshots, SortSnapshotsNewestFirst(reversed))
+}
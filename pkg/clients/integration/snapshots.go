@@ -9,11 +9,12 @@ import (
 
 	"github.com/devfile/library/v2/pkg/util"
 	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
-	"github.com/konflux-ci/e2e-tests/pkg/logs"
-	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	intgteststat "github.com/konflux-ci/integration-service/pkg/integrationteststatus"
 	. "github.com/onsi/ginkgo/v2"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -47,20 +48,19 @@ var (
 
 // CreateSnapshotWithComponents creates a Snapshot using the given parameters.
 func (i *IntegrationController) CreateSnapshotWithComponents(snapshotName, componentName, applicationName, namespace string, snapshotComponents []appstudioApi.SnapshotComponent) (*appstudioApi.Snapshot, error) {
-	snapshot := &appstudioApi.Snapshot{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      snapshotName,
-			Namespace: namespace,
-			Labels: map[string]string{
-				"test.appstudio.openshift.io/type":           "component",
-				"appstudio.openshift.io/component":           componentName,
-				"pac.test.appstudio.openshift.io/event-type": "push",
-			},
-		},
-		Spec: appstudioApi.SnapshotSpec{
-			Application: applicationName,
-			Components:  snapshotComponents,
-		},
+	snapshot := NewSnapshotBuilder(applicationName, namespace).
+		WithName(snapshotName).
+		ForComponent(componentName).
+		WithComponents(snapshotComponents...).
+		Build()
+	return snapshot, i.KubeRest().Create(context.Background(), snapshot)
+}
+
+// CreateSnapshotFromBuilder validates the Snapshot built by the given builder and creates it.
+func (i *IntegrationController) CreateSnapshotFromBuilder(builder *SnapshotBuilder) (*appstudioApi.Snapshot, error) {
+	snapshot, err := builder.BuildAndValidate()
+	if err != nil {
+		return nil, err
 	}
 	return snapshot, i.KubeRest().Create(context.Background(), snapshot)
 }
@@ -105,7 +105,10 @@ func (i *IntegrationController) GetSnapshot(snapshotName, pipelineRunName, compo
 	if len(snapshotName) > 0 {
 		snapshot := &appstudioApi.Snapshot{}
 		if err := i.KubeRest().Get(ctx, types.NamespacedName{Name: snapshotName, Namespace: namespace}, snapshot); err != nil {
-			return nil, fmt.Errorf("couldn't find Snapshot with name '%s' in '%s' namespace", snapshotName, namespace)
+			if k8sErrors.IsNotFound(err) {
+				return nil, clienterrors.NotFound("Snapshot", namespace, snapshotName, "couldn't find Snapshot with name '%s' in '%s' namespace", snapshotName, namespace)
+			}
+			return nil, fmt.Errorf("couldn't get Snapshot with name '%s' in '%s' namespace: %+v", snapshotName, namespace, err)
 		}
 		return snapshot, nil
 	}
@@ -133,7 +136,7 @@ func (i *IntegrationController) GetSnapshot(snapshotName, pipelineRunName, compo
 
 		}
 	}
-	return nil, fmt.Errorf("no snapshot found for component '%s', pipelineRun '%s' in '%s' namespace", componentName, pipelineRunName, namespace)
+	return nil, clienterrors.NotFound("Snapshot", namespace, componentName, "no snapshot found for component '%s', pipelineRun '%s' in '%s' namespace", componentName, pipelineRunName, namespace)
 }
 
 // DeleteSnapshot removes given snapshot from specified namespace.
@@ -151,17 +154,7 @@ func (i *IntegrationController) PatchSnapshot(oldSnapshot *appstudioApi.Snapshot
 
 // DeleteAllSnapshotsInASpecificNamespace removes all snapshots from a specific namespace. Useful when creating a lot of resources and want to remove all of them
 func (i *IntegrationController) DeleteAllSnapshotsInASpecificNamespace(namespace string, timeout time.Duration) error {
-	if err := i.KubeRest().DeleteAllOf(context.Background(), &appstudioApi.Snapshot{}, client.InNamespace(namespace)); err != nil {
-		return fmt.Errorf("error deleting snapshots from the namespace %s: %+v", namespace, err)
-	}
-
-	return utils.WaitUntil(func() (done bool, err error) {
-		snapshotList, err := i.ListAllSnapshots(namespace)
-		if err != nil {
-			return false, nil
-		}
-		return len(snapshotList.Items) == 0, nil
-	}, timeout)
+	return common.DeleteAllResources[appstudioApi.Snapshot](i.KubeRest(), namespace, common.DeleteAllOptions{Timeout: timeout})
 }
 
 // WaitForSnapshotToGetCreated wait for the Snapshot to get created successfully.
@@ -183,30 +176,19 @@ func (i *IntegrationController) WaitForSnapshotToGetCreated(snapshotName, pipeli
 
 // ListAllSnapshots returns a list of all Snapshots in a given namespace.
 func (i *IntegrationController) ListAllSnapshots(namespace string) (*appstudioApi.SnapshotList, error) {
-	snapshotList := &appstudioApi.SnapshotList{}
-	err := i.KubeRest().List(context.Background(), snapshotList, &client.ListOptions{Namespace: namespace})
+	snapshots, err := common.ListResources[appstudioApi.Snapshot](i.KubeRest(), namespace, nil)
 
-	return snapshotList, err
+	return &appstudioApi.SnapshotList{Items: snapshots}, err
 }
 
 // StoreSnapshot stores a given Snapshot as an artifact.
 func (i *IntegrationController) StoreSnapshot(snapshot *appstudioApi.Snapshot) error {
-	return logs.StoreResourceYaml(snapshot, "snapshot-"+snapshot.Name)
+	return common.StoreResource(i.KubeRest(), snapshot)
 }
 
 // StoreAllSnapshots stores all Snapshots in a given namespace.
 func (i *IntegrationController) StoreAllSnapshots(namespace string) error {
-	snapshotList, err := i.ListAllSnapshots(namespace)
-	if err != nil {
-		return err
-	}
-
-	for _, snapshot := range snapshotList.Items {
-		if err := i.StoreSnapshot(&snapshot); err != nil {
-			return err
-		}
-	}
-	return nil
+	return common.StoreAllResources[appstudioApi.Snapshot](i.KubeRest(), namespace, nil)
 }
 
 // GetIntegrationTestStatusDetailFromSnapshot parses snapshot annotation and returns integration test status detail
@@ -226,7 +208,7 @@ func (i *IntegrationController) GetIntegrationTestStatusDetailFromSnapshot(snaps
 	}
 	statusDetail, ok := statuses.GetScenarioStatus(scenarioName)
 	if !ok {
-		return nil, fmt.Errorf("status detail for scenario %s not found", scenarioName)
+		return nil, clienterrors.NotFound("IntegrationTestScenario status", snapshot.GetNamespace(), scenarioName, "status detail for scenario %s not found", scenarioName)
 	}
 	return statusDetail, nil
 }
@@ -254,25 +236,47 @@ func (i *IntegrationController) IsSnapshotStatusConditionSet(snapshot *appstudio
 	return true
 }
 
-// SortSnapshots sorts the snapshots according to the snapshot annotation BuildPipelineRunStartTime
-func (i *IntegrationController) SortSnapshots(snapshots []appstudioApi.Snapshot) []appstudioApi.Snapshot {
-	sort.Slice(snapshots, func(i, j int) bool {
-		// sorting snapshots according to the annotation BuildPipelineRunStartTime which
-		// represents the start time of build PLR
-		// when BuildPipelineRunStartTime is not set, we use its creation time
-		var time_i, time_j int
-		if metadata.HasAnnotation(&snapshots[i], BuildPipelineRunStartTime) && metadata.HasAnnotation(&snapshots[j], BuildPipelineRunStartTime) {
-			time_i, _ = strconv.Atoi(snapshots[i].Annotations[BuildPipelineRunStartTime])
-			time_j, _ = strconv.Atoi(snapshots[j].Annotations[BuildPipelineRunStartTime])
-		} else {
-			time_i = int(snapshots[i].CreationTimestamp.Time.Unix())
-			time_j = int(snapshots[j].CreationTimestamp.Time.Unix())
+// snapshotBuildStartTime returns the start time of the build PipelineRun stored in the BuildPipelineRunStartTime
+// annotation, or the creation time of the snapshot when the annotation is not set (e.g. for group snapshots)
+func snapshotBuildStartTime(snapshot *appstudioApi.Snapshot) int64 {
+	if metadata.HasAnnotation(snapshot, BuildPipelineRunStartTime) {
+		if startTime, err := strconv.ParseInt(snapshot.Annotations[BuildPipelineRunStartTime], 10, 64); err == nil {
+			return startTime
+		}
+	}
+	return snapshot.CreationTimestamp.Time.Unix()
+}
+
+// SortSnapshotsNewestFirst sorts the snapshots from the newest to the oldest according to the start time of their
+// build PipelineRun (see snapshotBuildStartTime). Snapshots started at the same second are ordered by name,
+// so the order does not depend on the order the snapshots were listed in.
+func SortSnapshotsNewestFirst(snapshots []appstudioApi.Snapshot) []appstudioApi.Snapshot {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		time_i, time_j := snapshotBuildStartTime(&snapshots[i]), snapshotBuildStartTime(&snapshots[j])
+		if time_i != time_j {
+			return time_i > time_j
 		}
-		return time_i > time_j
+		return snapshots[i].Name < snapshots[j].Name
 	})
 	return snapshots
 }
 
+// SortSnapshots sorts the snapshots according to the snapshot annotation BuildPipelineRunStartTime,
+// newest first, see SortSnapshotsNewestFirst
+func (i *IntegrationController) SortSnapshots(snapshots []appstudioApi.Snapshot) []appstudioApi.Snapshot {
+	return SortSnapshotsNewestFirst(snapshots)
+}
+
+// LatestSnapshot returns the newest of the snapshots (see SortSnapshotsNewestFirst) without reordering them,
+// nil when there is no snapshot
+func (i *IntegrationController) LatestSnapshot(snapshots []appstudioApi.Snapshot) *appstudioApi.Snapshot {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	sorted := SortSnapshotsNewestFirst(append([]appstudioApi.Snapshot{}, snapshots...))
+	return &sorted[0]
+}
+
 func (i *IntegrationController) IsOlderSnapshotAndIntegrationPlrCancelled(snapshots []appstudioApi.Snapshot, integrationTestScenarioName string) (bool, error) {
 	snapshots = i.SortSnapshots(snapshots)
 	if len(snapshots) < 2 {
@@ -53,6 +53,7 @@ type CustomClient struct {
 type K8SClient struct {
 	AsKubeAdmin       *CustomClient
 	AsKubeDeveloper   *CustomClient
+	Clusters          *Clusters
 	ProxyUrl          string
 	SandboxController *sandbox.SandboxController
 	UserName          string
@@ -80,6 +81,11 @@ func init() {
 	utilruntime.Must(pacv1alpha1.AddToScheme(scheme))
 }
 
+// Scheme returns the scheme with all the Konflux and Kubernetes types used by the clients.
+func Scheme() *runtime.Scheme {
+	return scheme
+}
+
 // Kube returns the clientset for Kubernetes upstream.
 func (c *CustomClient) KubeInterface() kubernetes.Interface {
 	return c.kubeClient
@@ -109,15 +115,19 @@ func (c *CustomClient) DynamicClient() dynamic.Interface {
 }
 
 // Creates Kubernetes clients:
-// 1. Will create a kubernetes client from default kubeconfig as kubeadmin
+// 1. Will take the admin client of the member cluster from clusters, the sandbox user is registered with the admin client of the host cluster
 // 2. Will create a sandbox user and will generate a client using user token a new client to create resources in RHTAP like a normal user
-func NewDevSandboxProxyClient(userName string, isStage, isSA bool, options utils.Options) (*K8SClient, error) {
+func NewDevSandboxProxyClient(userName string, isStage, isSA bool, options utils.Options, clusters *Clusters) (*K8SClient, error) {
 	var err error
 	var asAdminClient *CustomClient = nil
 	var sandboxController *sandbox.SandboxController
 	var proxyAuthInfo *sandbox.SandboxUserAuthInfo
 	var sandboxProxyClient *CustomClient
 
+	if clusters == nil {
+		clusters = NewClusters()
+	}
+
 	if isStage {
 		sandboxController, err := sandbox.NewDevSandboxStageController()
 		if err != nil {
@@ -129,12 +139,16 @@ func NewDevSandboxProxyClient(userName string, isStage, isSA bool, options utils
 		}
 
 	} else {
-		asAdminClient, err = NewAdminKubernetesClient()
+		asAdminClient, err = clusters.Client(MemberCluster)
+		if err != nil {
+			return nil, err
+		}
+		hostClient, err := clusters.Client(HostCluster)
 		if err != nil {
 			return nil, err
 		}
 
-		sandboxController, err = sandbox.NewDevSandboxController(asAdminClient.KubeInterface(), asAdminClient.KubeRest())
+		sandboxController, err = sandbox.NewDevSandboxController(hostClient.KubeInterface(), hostClient.KubeRest())
 		if err != nil {
 			return nil, err
 		}
@@ -153,6 +167,7 @@ func NewDevSandboxProxyClient(userName string, isStage, isSA bool, options utils
 	return &K8SClient{
 		AsKubeAdmin:       asAdminClient,
 		AsKubeDeveloper:   sandboxProxyClient,
+		Clusters:          clusters,
 		ProxyUrl:          proxyAuthInfo.ProxyUrl,
 		SandboxController: sandboxController,
 		UserName:          proxyAuthInfo.UserName,
@@ -168,27 +183,7 @@ func NewAdminKubernetesClient() (*CustomClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	clientSets, err := createClientSetsFromConfig(adminKubeconfig)
-	if err != nil {
-		return nil, err
-	}
-
-	crClient, err := crclient.New(adminKubeconfig, crclient.Options{
-		Scheme: scheme,
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &CustomClient{
-		kubeClient:            clientSets.kubeClient,
-		pipelineClient:        clientSets.pipelineClient,
-		dynamicClient:         clientSets.dynamicClient,
-		jvmbuildserviceClient: clientSets.jvmbuildserviceClient,
-		routeClient:           clientSets.routeClient,
-		crClient:              crClient,
-	}, nil
+	return newClientFromConfig(adminKubeconfig)
 }
 
 // CreateAPIProxyClient creates a client to the RHTAP api proxy using the given user token
@@ -201,6 +196,7 @@ func CreateAPIProxyClient(usertoken, proxyURL string) (*CustomClient, error) {
 		BearerToken: usertoken,
 		Transport:   noTimeoutDefaultTransport(),
 	}
+	wrapTransport(proxyKubeConfig)
 
 	// Getting the proxy client can fail from time to time if the proxy's informer cache has not been
 	// updated yet and we try to create the client to quickly so retry to reduce flakiness.
@@ -238,6 +234,16 @@ func noTimeoutDefaultTransport() *http.Transport {
 	return transport
 }
 
+// WrapTransport, when set, wraps HTTP transport of every client created afterwards,
+// e.g. to inject latency into Kubernetes API requests during load tests
+var WrapTransport func(http.RoundTripper) http.RoundTripper
+
+func wrapTransport(cfg *rest.Config) {
+	if WrapTransport != nil {
+		cfg.Wrap(WrapTransport)
+	}
+}
+
 var noTimeoutDialerProxy = func(ctx context.Context, network, addr string) (net.Conn, error) {
 	dialer := &net.Dialer{
 		Timeout:   0,
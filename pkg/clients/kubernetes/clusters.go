@@ -0,0 +1,205 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Names of the clusters of a Konflux deployment
+const (
+	// HostCluster runs the toolchain host operator (registration service, Spaces, SpaceBindings, MasterUserRecords)
+	HostCluster = "host"
+	// MemberCluster runs the toolchain member operator and the tenant namespaces with the Konflux workloads
+	MemberCluster = "member"
+	// ManagedCluster runs the managed namespaces the release pipelines are executed in
+	ManagedCluster = "managed"
+)
+
+// ClusterConfig describes how to connect to a named cluster.
+type ClusterConfig struct {
+	Name string
+	// Kubeconfig is the path of the kubeconfig file, the default one (KUBECONFIG env or $HOME/.kube/config) is used if empty
+	Kubeconfig string
+	// Context is the kubeconfig context, the current context is used if empty
+	Context string
+}
+
+func (c ClusterConfig) String() string {
+	context := c.Context
+	if context == "" {
+		context = "current context"
+	}
+	if c.Kubeconfig == "" {
+		return context
+	}
+	return fmt.Sprintf("%s of %s", context, c.Kubeconfig)
+}
+
+// Clusters manages admin clients of named clusters. The clients are created on first use and shared by the clusters
+// with the same kubeconfig and context. Clusters without a configuration use the current context of the default kubeconfig,
+// so single cluster deployments, where host, member and managed clusters are the same, don't need any configuration.
+// The remote hosts of the multi-platform controller are VMs reached over SSH, not clusters, so they are not covered here.
+type Clusters struct {
+	configs   map[string]ClusterConfig
+	clients   map[ClusterConfig]*CustomClient
+	mu        sync.Mutex
+	newClient func(kubeconfig, context string) (*CustomClient, error)
+}
+
+// NewClusters returns the clusters with the given configurations.
+func NewClusters(configs ...ClusterConfig) *Clusters {
+	clusters := &Clusters{
+		configs:   map[string]ClusterConfig{},
+		clients:   map[ClusterConfig]*CustomClient{},
+		newClient: NewKubernetesClientForContext,
+	}
+	for _, c := range configs {
+		clusters.configs[c.Name] = c
+	}
+	return clusters
+}
+
+// NewClustersFromEnv returns the clusters configured by CLUSTER_CONTEXTS and CLUSTER_KUBECONFIGS env vars.
+func NewClustersFromEnv() (*Clusters, error) {
+	configs, err := ParseClusterConfigs(os.Getenv(constants.CLUSTER_CONTEXTS_ENV), os.Getenv(constants.CLUSTER_KUBECONFIGS_ENV))
+	if err != nil {
+		return nil, err
+	}
+	return NewClusters(configs...), nil
+}
+
+// ParseClusterConfigs parses comma separated <cluster>=<context> and <cluster>=<kubeconfig path> pairs into cluster configurations sorted by name.
+func ParseClusterConfigs(contexts, kubeconfigs string) ([]ClusterConfig, error) {
+	configs := map[string]*ClusterConfig{}
+	get := func(name string) *ClusterConfig {
+		if configs[name] == nil {
+			configs[name] = &ClusterConfig{Name: name}
+		}
+		return configs[name]
+	}
+
+	contextPairs, err := parsePairs(contexts, constants.CLUSTER_CONTEXTS_ENV)
+	if err != nil {
+		return nil, err
+	}
+	for name, context := range contextPairs {
+		get(name).Context = context
+	}
+	kubeconfigPairs, err := parsePairs(kubeconfigs, constants.CLUSTER_KUBECONFIGS_ENV)
+	if err != nil {
+		return nil, err
+	}
+	for name, kubeconfig := range kubeconfigPairs {
+		get(name).Kubeconfig = kubeconfig
+	}
+
+	result := make([]ClusterConfig, 0, len(configs))
+	for _, c := range configs {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// Names returns the names of the configured clusters.
+func (c *Clusters) Names() []string {
+	names := make([]string, 0, len(c.configs))
+	for name := range c.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsConfigured returns true if the cluster has its own configuration, i.e. it doesn't use the current context of the default kubeconfig.
+func (c *Clusters) IsConfigured(name string) bool {
+	_, ok := c.configs[name]
+	return ok
+}
+
+// Config returns the configuration of the cluster, the current context of the default kubeconfig if the cluster isn't configured.
+func (c *Clusters) Config(name string) ClusterConfig {
+	if config, ok := c.configs[name]; ok {
+		return config
+	}
+	return ClusterConfig{Name: name}
+}
+
+// Client returns the admin client of the cluster.
+func (c *Clusters) Client(name string) (*CustomClient, error) {
+	config := c.Config(name)
+	key := ClusterConfig{Kubeconfig: config.Kubeconfig, Context: config.Context}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+	client, err := c.newClient(config.Kubeconfig, config.Context)
+	if err != nil {
+		return nil, fmt.Errorf("error creating client of the %s cluster (%s): %+v", name, config, err)
+	}
+	c.clients[key] = client
+	return client, nil
+}
+
+// NewKubernetesClientForContext creates a kubernetes client from the context of the kubeconfig. The default kubeconfig
+// (KUBECONFIG env or $HOME/.kube/config) is used if kubeconfig is empty and its current context if context is empty.
+func NewKubernetesClientForContext(kubeconfig, context string) (*CustomClient, error) {
+	if kubeconfig == "" && context == "" {
+		return NewAdminKubernetesClient()
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loadingRules.ExplicitPath = kubeconfig
+	}
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: context}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return newClientFromConfig(cfg)
+}
+
+func newClientFromConfig(cfg *rest.Config) (*CustomClient, error) {
+	wrapTransport(cfg)
+	clientSets, err := createClientSetsFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	crClient, err := crclient.New(cfg, crclient.Options{
+		Scheme: scheme,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clientSets.crClient = crClient
+	return clientSets, nil
+}
+
+func parsePairs(value, envName string) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, v, ok := strings.Cut(pair, "=")
+		name, v = strings.TrimSpace(name), strings.TrimSpace(v)
+		if !ok || name == "" || v == "" {
+			return nil, fmt.Errorf("invalid %s value %q: expected <cluster>=<value>", envName, pair)
+		}
+		pairs[name] = v
+	}
+	return pairs, nil
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClusterConfigs(t *testing.T) {
+	configs, err := ParseClusterConfigs("host=host-admin, member=member-admin,managed=default/api-managed:6443/kube:admin", "managed=/tmp/managed.kubeconfig")
+	assert.NoError(t, err)
+	assert.Equal(t, []ClusterConfig{
+		{Name: "host", Context: "host-admin"},
+		{Name: "managed", Context: "default/api-managed:6443/kube:admin", Kubeconfig: "/tmp/managed.kubeconfig"},
+		{Name: "member", Context: "member-admin"},
+	}, configs)
+
+	configs, err = ParseClusterConfigs("", "")
+	assert.NoError(t, err)
+	assert.Empty(t, configs)
+
+	_, err = ParseClusterConfigs("host", "")
+	assert.EqualError(t, err, `invalid CLUSTER_CONTEXTS value "host": expected <cluster>=<value>`)
+}
+
+func TestClustersClient(t *testing.T) {
+	var created []string
+	clusters := NewClusters(ClusterConfig{Name: HostCluster, Context: "host-admin"}, ClusterConfig{Name: "other", Context: "host-admin"})
+	clusters.newClient = func(kubeconfig, context string) (*CustomClient, error) {
+		created = append(created, kubeconfig+"|"+context)
+		if context == "broken" {
+			return nil, fmt.Errorf("context %q does not exist", context)
+		}
+		return &CustomClient{}, nil
+	}
+
+	host, err := clusters.Client(HostCluster)
+	assert.NoError(t, err)
+	other, err := clusters.Client("other")
+	assert.NoError(t, err)
+	assert.Same(t, host, other)
+
+	member, err := clusters.Client(MemberCluster)
+	assert.NoError(t, err)
+	managed, err := clusters.Client(ManagedCluster)
+	assert.NoError(t, err)
+	assert.Same(t, member, managed)
+	assert.NotSame(t, host, member)
+
+	assert.Equal(t, []string{"|host-admin", "|"}, created)
+	assert.True(t, clusters.IsConfigured(HostCluster))
+	assert.False(t, clusters.IsConfigured(MemberCluster))
+	assert.Equal(t, []string{"host", "other"}, clusters.Names())
+
+	clusters.configs["broken"] = ClusterConfig{Name: "broken", Context: "broken"}
+	_, err = clusters.Client("broken")
+	assert.EqualError(t, err, `error creating client of the broken cluster (broken): context "broken" does not exist`)
+}
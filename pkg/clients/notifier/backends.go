@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// SlackNotifier posts messages to a Slack channel as the bot user.
+type SlackNotifier struct {
+	api       *slack.Client
+	channelID string
+	// Template is the text/template used to render messages, DefaultTemplate if empty
+	Template string
+}
+
+// NewSlackNotifier returns a SlackNotifier using the given bot token and channel ID.
+func NewSlackNotifier(token, channelID string) *SlackNotifier {
+	return &SlackNotifier{api: slack.New(token), channelID: channelID}
+}
+
+func (s *SlackNotifier) Notify(msg Message) error {
+	text, err := Render(s.Template, msg)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.api.PostMessage(
+		s.channelID,
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionAsUser(true),
+	)
+	return err
+}
+
+// WebhookNotifier posts messages as JSON to a generic webhook.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// webhookPayload is the JSON body posted by the WebhookNotifier.
+type webhookPayload struct {
+	Title     string    `json:"title"`
+	Text      string    `json:"text"`
+	Severity  Severity  `json:"severity"`
+	JobURL    string    `json:"jobUrl,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewWebhookNotifier returns a WebhookNotifier posting to the given URL.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (w *WebhookNotifier) Notify(msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		Title:     msg.Title,
+		Text:      msg.Text,
+		Severity:  msg.Severity,
+		JobURL:    msg.JobURL,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %+v", err)
+	}
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post message to webhook: %+v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook responded with status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// WriterNotifier writes rendered messages to an io.Writer, e.g. os.Stdout.
+type WriterNotifier struct {
+	w  io.Writer
+	mu sync.Mutex
+	// Template is the text/template used to render messages, DefaultTemplate if empty
+	Template string
+}
+
+// NewWriterNotifier returns a WriterNotifier writing to w.
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{w: w}
+}
+
+func (n *WriterNotifier) Notify(msg Message) error {
+	text, err := Render(n.Template, msg)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = fmt.Fprintf(n.w, "[%s] %s\n", time.Now().UTC().Format(time.RFC3339), text)
+	return err
+}
+
+// FileNotifier appends rendered messages to a file.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+	// Template is the text/template used to render messages, DefaultTemplate if empty
+	Template string
+}
+
+// NewFileNotifier returns a FileNotifier appending to the file at path.
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (f *FileNotifier) Notify(msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// #nosec G304 -- the path is provided by the user running the tests
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open notifier file %s: %+v", f.path, err)
+	}
+	defer file.Close()
+	return (&WriterNotifier{w: file, Template: f.Template}).Notify(msg)
+}
+
+// FakeNotifier records the messages it receives, to be used in tests.
+type FakeNotifier struct {
+	Messages []Message
+	// Err is returned by Notify when set
+	Err error
+	mu  sync.Mutex
+}
+
+func (f *FakeNotifier) Notify(msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Messages = append(f.Messages, msg)
+	return nil
+}
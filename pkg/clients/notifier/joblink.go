@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	v1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// JobURL returns the link to the logs of the CI job running the tests:
+// the Prow job when PROW_JOB_ID is set, the Konflux PipelineRun when running in Konflux CI.
+// It returns an empty string when the job can't be determined.
+func JobURL() string {
+	if jobID := os.Getenv("PROW_JOB_ID"); jobID != "" {
+		return getProwJobURL(jobID)
+	}
+	if os.Getenv("KONFLUX_CI") == "true" {
+		return getKonfluxPipelineRunURL(os.Getenv(constants.KONFLUX_UI_URL_ENV), os.Getenv(constants.KONFLUX_PIPELINERUN_NAMESPACE_ENV), os.Getenv("JOB_NAME"))
+	}
+	return ""
+}
+
+func getKonfluxPipelineRunURL(uiURL, namespace, pipelineRunName string) string {
+	if uiURL == "" || namespace == "" || pipelineRunName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/ns/%s/pipelinerun/%s", strings.TrimSuffix(uiURL, "/"), namespace, pipelineRunName)
+}
+
+func getProwJobURL(jobID string) string {
+	r, err := http.Get(fmt.Sprintf("https://prow.ci.openshift.org/prowjob?prowjob=%s", jobID))
+	errTemplate := "failed to get prow job URL:"
+	if err != nil {
+		return fmt.Sprintf("%s %s", errTemplate, err)
+	}
+	defer r.Body.Close()
+	if r.StatusCode > 299 {
+		return fmt.Sprintf("%s got response status code %v", errTemplate, r.StatusCode)
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Sprintf("%s %s", errTemplate, err)
+	}
+	var pj v1.ProwJob
+	err = yaml.Unmarshal(body, &pj)
+	if err != nil {
+		return fmt.Sprintf("%s %s", errTemplate, err)
+	}
+	return pj.Status.URL
+}
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// LimitedNotifier wraps a Notifier, dropping messages which were already sent during the run
+// and messages exceeding the rate limit.
+type LimitedNotifier struct {
+	inner       Notifier
+	maxMessages int
+	interval    time.Duration
+	sent        map[string]bool
+	sentAt      []time.Time
+	// Dropped counts the messages which were not delivered because of deduplication or rate limiting
+	Dropped int
+	// RateLimited counts the messages which were not delivered because of rate limiting
+	RateLimited int
+	now         func() time.Time
+	mu          sync.Mutex
+}
+
+// NewLimitedNotifier returns a LimitedNotifier sending at most maxMessages messages per interval through inner.
+// A maxMessages <= 0 disables rate limiting, keeping only the deduplication.
+func NewLimitedNotifier(inner Notifier, maxMessages int, interval time.Duration) *LimitedNotifier {
+	return &LimitedNotifier{
+		inner:       inner,
+		maxMessages: maxMessages,
+		interval:    interval,
+		sent:        map[string]bool{},
+		now:         time.Now,
+	}
+}
+
+func (l *LimitedNotifier) Notify(msg Message) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := string(msg.Severity) + "\x00" + msg.Title + "\x00" + msg.Text
+	if l.sent[key] {
+		l.Dropped++
+		klog.V(1).Infof("notifier: dropping duplicate %s message %q", msg.Severity, msg.Title)
+		return nil
+	}
+
+	now := l.now()
+	if l.maxMessages > 0 {
+		recent := l.sentAt[:0]
+		for _, t := range l.sentAt {
+			if now.Sub(t) < l.interval {
+				recent = append(recent, t)
+			}
+		}
+		l.sentAt = recent
+		if len(l.sentAt) >= l.maxMessages {
+			l.Dropped++
+			l.RateLimited++
+			klog.Warningf("notifier: dropping %s message %q, more than %d messages per %s (%d dropped so far): %s",
+				msg.Severity, msg.Title, l.maxMessages, l.interval, l.RateLimited, msg.Text)
+			return nil
+		}
+	}
+
+	if err := l.inner.Notify(msg); err != nil {
+		return err
+	}
+	l.sent[key] = true
+	l.sentAt = append(l.sentAt, now)
+	return nil
+}
@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+)
+
+type Severity string
+
+const (
+	SeverityInfo    Severity = "Info"
+	SeverityWarning Severity = "Warning"
+	SeverityError   Severity = "Error"
+	SeverityFatal   Severity = "Fatal"
+)
+
+var severityEmoji = map[Severity]string{
+	SeverityInfo:    ":information_source:",
+	SeverityWarning: ":warning:",
+	SeverityError:   ":alert-siren:",
+	SeverityFatal:   ":panic:",
+}
+
+// Message is an alert delivered by a Notifier.
+type Message struct {
+	Title    string
+	Text     string
+	Severity Severity
+	// JobURL links the alert to the CI job logs, see JobURL
+	JobURL string
+}
+
+// Notifier delivers alerts to a backend, e.g. Slack, a webhook or a file.
+type Notifier interface {
+	Notify(msg Message) error
+}
+
+// DefaultTemplate is the text/template used by the Slack, file and stdout backends to render a Message.
+const DefaultTemplate = `{{ emoji .Severity }} *{{ .Title }}* {{ emoji .Severity }}
+Error message: ` + "```\n{{ .Text }}\n```" + `{{ if .JobURL }}
+<{{ .JobURL }}|*View logs*>{{ end }}`
+
+var templateFuncs = template.FuncMap{
+	"emoji": func(s Severity) string { return severityEmoji[s] },
+}
+
+// Render renders the Message with the given text/template. An empty tmpl renders DefaultTemplate.
+func Render(tmpl string, msg Message) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultTemplate
+	}
+	t, err := template.New("message").Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse message template: %+v", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, msg); err != nil {
+		return "", fmt.Errorf("failed to render message template: %+v", err)
+	}
+	return buf.String(), nil
+}
+
+// NewMessage returns a Message with the default "E2E job alert" title and the link to the current CI job.
+func NewMessage(text string, severity Severity) Message {
+	return Message{
+		Title:    "E2E job alert",
+		Text:     text,
+		Severity: severity,
+		JobURL:   JobURL(),
+	}
+}
+
+// SlackChannelID returns the Slack channel set by the NOTIFIER_SLACK_CHANNEL_ID env var, the CI reports channel by default.
+func SlackChannelID() string {
+	if channelID := os.Getenv(constants.NOTIFIER_SLACK_CHANNEL_ID_ENV); channelID != "" {
+		return channelID
+	}
+	return constants.SlackCIReportsChannelID
+}
+
+// FromEnv returns a Notifier for the backend selected by the NOTIFIER_BACKEND env var.
+func FromEnv() (Notifier, error) {
+	backend := os.Getenv(constants.NOTIFIER_BACKEND_ENV)
+	if backend == "" {
+		backend = "stdout"
+		if os.Getenv(constants.SLACK_BOT_TOKEN_ENV) != "" {
+			backend = "slack"
+		}
+	}
+
+	switch backend {
+	case "slack":
+		return NewSlackNotifier(os.Getenv(constants.SLACK_BOT_TOKEN_ENV), SlackChannelID()), nil
+	case "webhook":
+		url := os.Getenv(constants.NOTIFIER_WEBHOOK_URL_ENV)
+		if url == "" {
+			return nil, fmt.Errorf("%s env var has to be set for the webhook notifier", constants.NOTIFIER_WEBHOOK_URL_ENV)
+		}
+		return NewWebhookNotifier(url), nil
+	case "file":
+		path := os.Getenv(constants.NOTIFIER_FILE_PATH_ENV)
+		if path == "" {
+			return nil, fmt.Errorf("%s env var has to be set for the file notifier", constants.NOTIFIER_FILE_PATH_ENV)
+		}
+		return NewFileNotifier(path), nil
+	case "stdout":
+		return NewWriterNotifier(os.Stdout), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier backend '%s'", backend)
+	}
+}
+
+var (
+	defaultNotifier Notifier
+	defaultErr      error
+	defaultOnce     sync.Once
+)
+
+// Default returns the Notifier shared by the whole run: the backend selected by FromEnv,
+// deduplicating repeated alerts and sending at most 10 alerts per minute.
+func Default() (Notifier, error) {
+	defaultOnce.Do(func() {
+		var n Notifier
+		n, defaultErr = FromEnv()
+		if defaultErr == nil {
+			defaultNotifier = NewLimitedNotifier(n, 10, time.Minute)
+		}
+	})
+	return defaultNotifier, defaultErr
+}
+
+// Notify sends the given text with the given severity through the Default notifier.
+func Notify(text string, severity Severity) error {
+	n, err := Default()
+	if err != nil {
+		return err
+	}
+	return n.Notify(NewMessage(text, severity))
+}
@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderDefaultTemplate(t *testing.T) {
+	text, err := Render("", Message{Title: "E2E job alert", Text: "boom", Severity: SeverityError, JobURL: "https://job"})
+	assert.NoError(t, err)
+	assert.Equal(t, ":alert-siren: *E2E job alert* :alert-siren:\nError message: ```\nboom\n```\n<https://job|*View logs*>", text)
+
+	text, err = Render("{{ .Severity }}: {{ .Text }}", Message{Text: "boom", Severity: SeverityInfo})
+	assert.NoError(t, err)
+	assert.Equal(t, "Info: boom", text)
+}
+
+func TestLimitedNotifier(t *testing.T) {
+	fake := &FakeNotifier{}
+	now := time.Now()
+	limited := NewLimitedNotifier(fake, 2, time.Minute)
+	limited.now = func() time.Time { return now }
+
+	assert.NoError(t, limited.Notify(Message{Text: "a", Severity: SeverityError}))
+	assert.NoError(t, limited.Notify(Message{Text: "a", Severity: SeverityError}))
+	assert.NoError(t, limited.Notify(Message{Text: "b", Severity: SeverityError}))
+	assert.NoError(t, limited.Notify(Message{Text: "c", Severity: SeverityError}))
+	assert.Len(t, fake.Messages, 2)
+	assert.Equal(t, 2, limited.Dropped)
+	assert.Equal(t, 1, limited.RateLimited)
+
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, limited.Notify(Message{Text: "c", Severity: SeverityError}))
+	assert.Len(t, fake.Messages, 3)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	defer server.Close()
+
+	assert.NoError(t, NewWebhookNotifier(server.URL).Notify(Message{Title: "title", Text: "boom", Severity: SeverityFatal}))
+	assert.Equal(t, "boom", payload.Text)
+	assert.Equal(t, SeverityFatal, payload.Severity)
+}
+
+func TestWriterAndFileNotifiers(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, (&WriterNotifier{w: &buf, Template: "{{ .Text }}"}).Notify(Message{Text: "boom"}))
+	assert.Contains(t, buf.String(), "] boom\n")
+
+	path := filepath.Join(t.TempDir(), "alerts.log")
+	fileNotifier := NewFileNotifier(path)
+	assert.NoError(t, fileNotifier.Notify(Message{Text: "first"}))
+	assert.NoError(t, fileNotifier.Notify(Message{Text: "second"}))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "first")
+	assert.Contains(t, string(content), "second")
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(constants.SLACK_BOT_TOKEN_ENV, "")
+	t.Setenv(constants.NOTIFIER_BACKEND_ENV, "")
+	n, err := FromEnv()
+	assert.NoError(t, err)
+	assert.IsType(t, &WriterNotifier{}, n)
+
+	t.Setenv(constants.NOTIFIER_SLACK_CHANNEL_ID_ENV, "")
+	assert.Equal(t, constants.SlackCIReportsChannelID, SlackChannelID())
+	t.Setenv(constants.NOTIFIER_SLACK_CHANNEL_ID_ENV, "C0123")
+	assert.Equal(t, "C0123", SlackChannelID())
+
+	t.Setenv(constants.NOTIFIER_BACKEND_ENV, "webhook")
+	_, err = FromEnv()
+	assert.ErrorContains(t, err, constants.NOTIFIER_WEBHOOK_URL_ENV)
+
+	t.Setenv(constants.NOTIFIER_BACKEND_ENV, "unknown")
+	_, err = FromEnv()
+	assert.ErrorContains(t, err, "unknown notifier backend")
+}
+
+func TestKonfluxPipelineRunURL(t *testing.T) {
+	assert.Equal(t, "https://konflux.example.com/ns/ci/pipelinerun/e2e-abc", getKonfluxPipelineRunURL("https://konflux.example.com/", "ci", "e2e-abc"))
+	assert.Empty(t, getKonfluxPipelineRunURL("", "ci", "e2e-abc"))
+}
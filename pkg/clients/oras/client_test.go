@@ -0,0 +1,196 @@
+package oras
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	oras "oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func newTestRegistry(t *testing.T) (*Client, string) {
+	server := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(server.Close)
+	client := &Client{Credentials: func(context.Context, string) (auth.Credential, error) { return auth.EmptyCredential, nil }, PlainHTTP: true}
+	return client, strings.TrimPrefix(server.URL, "http://") + "/org/repo"
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestPushAndSelectivePull(t *testing.T) {
+	client, repo := newTestRegistry(t)
+	ctx := context.Background()
+	srcDir := t.TempDir()
+
+	manifest, err := client.Push(ctx, repo+":reports", []PushFile{
+		{Path: writeTestFile(t, srcDir, "junit.xml", "<testsuites/>"), MediaType: "application/xml"},
+		{Path: writeTestFile(t, srcDir, "results.csv", "a,b")},
+	}, PushOptions{ArtifactType: ArtifactTypeJUnitReport})
+	assert.NoError(t, err)
+	assert.Equal(t, ArtifactTypeJUnitReport, manifest.ArtifactType)
+
+	dstDir := t.TempDir()
+	pulled, err := client.Pull(ctx, repo+":reports", dstDir, PullOptions{MediaTypes: []string{"application/xml"}})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 1)
+	assert.FileExists(t, filepath.Join(dstDir, "junit.xml"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "results.csv"))
+
+	// pulling by digest checks the resolved manifest digest
+	dstDir = t.TempDir()
+	pulled, err = client.Pull(ctx, repo+"@"+manifest.Digest.String(), dstDir, PullOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 2)
+}
+
+func TestPullIndex(t *testing.T) {
+	client, repo := newTestRegistry(t)
+	ctx := context.Background()
+	srcDir := t.TempDir()
+
+	junit, err := client.Push(ctx, repo+":junit", []PushFile{{Path: writeTestFile(t, srcDir, "junit.xml", "<testsuites/>")}},
+		PushOptions{ArtifactType: ArtifactTypeJUnitReport})
+	assert.NoError(t, err)
+	csv, err := client.Push(ctx, repo+":csv", []PushFile{
+		{Path: writeTestFile(t, srcDir, "timings.csv", "a,b")},
+		{Path: writeTestFile(t, srcDir, "errors.csv", "c,d")},
+	}, PushOptions{ArtifactType: ArtifactTypeLoadTestCSV})
+	assert.NoError(t, err)
+
+	index, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{junit, csv},
+	})
+	assert.NoError(t, err)
+	remoteRepo, _, err := client.repository(repo + ":index")
+	assert.NoError(t, err)
+	_, err = oras.TagBytes(ctx, remoteRepo, ocispec.MediaTypeImageIndex, index, "index")
+	assert.NoError(t, err)
+
+	dstDir := t.TempDir()
+	pulled, err := client.Pull(ctx, repo+":index", dstDir, PullOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 3)
+	assert.FileExists(t, filepath.Join(dstDir, "junit.xml"))
+	assert.FileExists(t, filepath.Join(dstDir, "timings.csv"))
+	assert.FileExists(t, filepath.Join(dstDir, "errors.csv"))
+
+	// only the manifests of the selected artifact types are pulled
+	dstDir = t.TempDir()
+	pulled, err = client.Pull(ctx, repo+":index", dstDir, PullOptions{ArtifactTypes: []string{ArtifactTypeLoadTestCSV}})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 2)
+	assert.FileExists(t, filepath.Join(dstDir, "timings.csv"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "junit.xml"))
+}
+
+func TestReferrers(t *testing.T) {
+	client, repo := newTestRegistry(t)
+	ctx := context.Background()
+	srcDir := t.TempDir()
+
+	_, err := client.Push(ctx, repo+":image", []PushFile{{Path: writeTestFile(t, srcDir, "layer", "image")}}, PushOptions{})
+	assert.NoError(t, err)
+	_, err = client.Push(ctx, repo, []PushFile{{Path: writeTestFile(t, srcDir, "sbom.json", "{}")}},
+		PushOptions{ArtifactType: "application/vnd.cyclonedx+json", Subject: repo + ":image"})
+	assert.NoError(t, err)
+	_, err = client.Push(ctx, repo, []PushFile{{Path: writeTestFile(t, srcDir, "junit.xml", "<testsuites/>")}},
+		PushOptions{ArtifactType: ArtifactTypeJUnitReport, Subject: repo + ":image"})
+	assert.NoError(t, err)
+
+	referrers, err := client.Referrers(ctx, repo+":image", "")
+	assert.NoError(t, err)
+	assert.Len(t, referrers, 2)
+
+	dstDir := t.TempDir()
+	pulled, err := client.PullReferrers(ctx, repo+":image", "application/vnd.cyclonedx+json", dstDir, PullOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 1)
+	assert.FileExists(t, filepath.Join(dstDir, "sbom.json"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "layer"))
+
+	image, err := client.Resolve(ctx, repo+":image")
+	assert.NoError(t, err)
+	dstDir = t.TempDir()
+	pulled, err = client.PullReferrers(ctx, repo+"@"+image.Digest.String(), ArtifactTypeJUnitReport, dstDir, PullOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 1)
+	assert.FileExists(t, filepath.Join(dstDir, "junit.xml"))
+}
+
+func TestDefaultCredentialsPreferQuayToken(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config.json", `{"auths": {"quay.io": {"auth": "`+base64.StdEncoding.EncodeToString([]byte("user:pass"))+`"}}}`)
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("REGISTRY_AUTH_FILE", "")
+
+	t.Setenv("QUAY_TOKEN", "token")
+	cred, err := DefaultCredentials()(context.Background(), "quay.io")
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Credential{AccessToken: "token"}, cred)
+
+	t.Setenv("QUAY_TOKEN", "")
+	cred, err = DefaultCredentials()(context.Background(), "quay.io")
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Credential{Username: "user", Password: "pass"}, cred)
+}
+
+func TestPushRequiresTagWithoutSubject(t *testing.T) {
+	client, repo := newTestRegistry(t)
+	_, err := client.Push(context.Background(), repo, nil, PushOptions{})
+	assert.ErrorContains(t, err, "has to contain a tag")
+}
+
+func TestDockerConfigCredentials(t *testing.T) {
+	config := `{"auths": {
+		"quay.io": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("user:pass")) + `"},
+		"quay.io/org/repo": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("robot:token")) + `"},
+		"https://index.docker.io/v1/": {"identitytoken": "refresh"}
+	}}`
+	path := writeTestFile(t, t.TempDir(), "config.json", config)
+	creds := DockerConfigFileCredentials(filepath.Join(t.TempDir(), "missing.json"), path)
+
+	cred, err := creds(context.Background(), "quay.io")
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Credential{Username: "user", Password: "pass"}, cred)
+
+	cred, err = creds(context.Background(), "docker.io")
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh", cred.RefreshToken)
+
+	// the registry is only present under keys scoped to repositories, the shortest one is used
+	scoped := `{"auths": {
+		"quay.io/org/repo-b": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("robot-b:token")) + `"},
+		"quay.io/org/repo-a": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("robot-a:token")) + `"},
+		"quay.io/org": {"auth": "` + base64.StdEncoding.EncodeToString([]byte("org:token")) + `"}
+	}}`
+	scopedCreds, err := DockerConfigJSONCredentials([]byte(scoped))
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		cred, err = scopedCreds(context.Background(), "quay.io")
+		assert.NoError(t, err)
+		assert.Equal(t, "org", cred.Username)
+	}
+
+	cred, err = ChainCredentials(creds, StaticTokenCredentials("token"))(context.Background(), "registry.redhat.io")
+	assert.NoError(t, err)
+	assert.Equal(t, "token", cred.AccessToken)
+
+	_, err = DockerConfigJSONCredentials([]byte("not json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package oras
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// CredentialFunc resolves the credential for the given registry host.
+// Returning auth.EmptyCredential means anonymous access.
+type CredentialFunc func(ctx context.Context, registry string) (auth.Credential, error)
+
+// dockerConfig is the subset of a docker config file (~/.docker/config.json, auth.json) holding registry credentials.
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+	RegistryToken string `json:"registrytoken"`
+}
+
+// StaticTokenCredentials returns a CredentialFunc using the given access token for every registry.
+func StaticTokenCredentials(token string) CredentialFunc {
+	return func(_ context.Context, _ string) (auth.Credential, error) {
+		return auth.Credential{AccessToken: token}, nil
+	}
+}
+
+// DockerConfigJSONCredentials returns a CredentialFunc looking up the registry in the given docker config JSON content.
+func DockerConfigJSONCredentials(configJSON []byte) (CredentialFunc, error) {
+	cfg := dockerConfig{}
+	if err := json.Unmarshal(configJSON, &cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse docker config: %w", err)
+	}
+	return func(_ context.Context, registry string) (auth.Credential, error) {
+		return cfg.credential(registry)
+	}, nil
+}
+
+// DockerConfigFileCredentials returns a CredentialFunc looking up the registry in the given docker config files,
+// in order. Files which don't exist are skipped.
+func DockerConfigFileCredentials(paths ...string) CredentialFunc {
+	return func(ctx context.Context, registry string) (auth.Credential, error) {
+		for _, path := range paths {
+			// #nosec G304 -- the paths point to the docker config files of the user running the tests
+			content, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return auth.EmptyCredential, fmt.Errorf("cannot read docker config %s: %w", path, err)
+			}
+			fn, err := DockerConfigJSONCredentials(content)
+			if err != nil {
+				return auth.EmptyCredential, fmt.Errorf("%s: %w", path, err)
+			}
+			cred, err := fn(ctx, registry)
+			if err != nil || cred != auth.EmptyCredential {
+				return cred, err
+			}
+		}
+		return auth.EmptyCredential, nil
+	}
+}
+
+// ChainCredentials returns a CredentialFunc returning the first non-empty credential of the given funcs.
+func ChainCredentials(funcs ...CredentialFunc) CredentialFunc {
+	return func(ctx context.Context, registry string) (auth.Credential, error) {
+		for _, fn := range funcs {
+			cred, err := fn(ctx, registry)
+			if err != nil {
+				return auth.EmptyCredential, err
+			}
+			if cred != auth.EmptyCredential {
+				return cred, nil
+			}
+		}
+		return auth.EmptyCredential, nil
+	}
+}
+
+// DefaultDockerConfigPaths returns the docker config files used by docker and podman, in order of precedence:
+// $REGISTRY_AUTH_FILE, $DOCKER_CONFIG/config.json, ~/.docker/config.json and $XDG_RUNTIME_DIR/containers/auth.json.
+func DefaultDockerConfigPaths() []string {
+	var paths []string
+	if p := os.Getenv("REGISTRY_AUTH_FILE"); p != "" {
+		paths = append(paths, p)
+	}
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		paths = append(paths, filepath.Join(dir, "config.json"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".docker", "config.json"))
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		paths = append(paths, filepath.Join(dir, "containers", "auth.json"))
+	}
+	return paths
+}
+
+// DefaultCredentials looks up the credentials in QUAY_TOKEN, which is either a base64 encoded docker config
+// or a plain access token, then in the default docker config files. A plain access token in QUAY_TOKEN is used
+// for every registry, as before the docker config files were supported.
+func DefaultCredentials() CredentialFunc {
+	var funcs []CredentialFunc
+	if token := os.Getenv("QUAY_TOKEN"); token != "" {
+		if decoded, err := base64.StdEncoding.DecodeString(token); err == nil {
+			if fn, err := DockerConfigJSONCredentials(decoded); err == nil {
+				funcs = append(funcs, fn)
+			}
+		}
+		funcs = append(funcs, StaticTokenCredentials(token))
+	}
+	funcs = append(funcs, DockerConfigFileCredentials(DefaultDockerConfigPaths()...))
+	return ChainCredentials(funcs...)
+}
+
+func (c dockerConfig) credential(registry string) (auth.Credential, error) {
+	// prefer the exact registry key over keys scoped to a repository of the registry
+	if a, ok := c.Auths[registry]; ok {
+		return a.credential(registry)
+	}
+	// then the shortest matching key, e.g. "https://quay.io" over "quay.io/org/repo", in a fixed order
+	var hosts []string
+	for host := range c.Auths {
+		if normalizeRegistryHost(host) == normalizeRegistryHost(registry) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return auth.EmptyCredential, nil
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if len(hosts[i]) != len(hosts[j]) {
+			return len(hosts[i]) < len(hosts[j])
+		}
+		return hosts[i] < hosts[j]
+	})
+	return c.Auths[hosts[0]].credential(hosts[0])
+}
+
+func (a dockerAuth) credential(host string) (auth.Credential, error) {
+	cred := auth.Credential{
+		Username:     a.Username,
+		Password:     a.Password,
+		RefreshToken: a.IdentityToken,
+		AccessToken:  a.RegistryToken,
+	}
+	if a.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+		if err != nil {
+			return auth.EmptyCredential, fmt.Errorf("cannot decode auth of registry %s: %w", host, err)
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return auth.EmptyCredential, fmt.Errorf("auth of registry %s is not in the 'username:password' format", host)
+		}
+		cred.Username, cred.Password = username, password
+	}
+	return cred, nil
+}
+
+// normalizeRegistryHost strips the scheme and path from docker config keys like "https://index.docker.io/v1/".
+func normalizeRegistryHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	if host == "index.docker.io" {
+		return "docker.io"
+	}
+	return host
+}
@@ -0,0 +1,86 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/oras"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// OCIRegistry is a Registry talking the OCI Distribution API, e.g. registry:2, Zot or quay.io itself.
+type OCIRegistry struct {
+	host   string
+	client *oras.Client
+}
+
+// NewOCIRegistry returns an OCIRegistry for the given host, authenticating with the given credentials.
+// Hosts listed in INSECURE_REGISTRIES, localhost and 127.0.0.1 are reached over plain HTTP.
+func NewOCIRegistry(host string, credentials oras.CredentialFunc) *OCIRegistry {
+	return &OCIRegistry{host: host, client: &oras.Client{Credentials: credentials, PlainHTTP: isInsecure(host)}}
+}
+
+func (r *OCIRegistry) Host() string {
+	return r.host
+}
+
+func (r *OCIRegistry) ListTags(ctx context.Context, repository string) ([]string, error) {
+	return r.client.Tags(ctx, r.ref(repository, ""))
+}
+
+func (r *OCIRegistry) GetTag(ctx context.Context, repository, tag string) (Tag, error) {
+	desc, err := r.client.Resolve(ctx, r.ref(repository, ":"+tag))
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return Tag{}, fmt.Errorf("%w: %s", ErrTagNotFound, r.ref(repository, ":"+tag))
+		}
+		return Tag{}, fmt.Errorf("cannot resolve %s: %+v", r.ref(repository, ":"+tag), err)
+	}
+	return Tag{Name: tag, Digest: desc.Digest.String()}, nil
+}
+
+func (r *OCIRegistry) GetManifest(ctx context.Context, repository, ref string) (ocispec.Manifest, error) {
+	manifest, _, err := r.client.FetchManifest(ctx, r.ref(repository, refSuffix(ref)))
+	return manifest, err
+}
+
+func (r *OCIRegistry) Referrers(ctx context.Context, repository, digest, artifactType string) ([]ocispec.Descriptor, error) {
+	return r.client.Referrers(ctx, r.ref(repository, "@"+digest), artifactType)
+}
+
+// IsRepositoryPublic lists the tags of the repository anonymously: the repository is private
+// when the registry asks for authentication.
+func (r *OCIRegistry) IsRepositoryPublic(ctx context.Context, repository string) (bool, error) {
+	anonymous := &oras.Client{
+		Credentials: func(context.Context, string) (auth.Credential, error) { return auth.EmptyCredential, nil },
+		PlainHTTP:   r.client.PlainHTTP,
+	}
+	_, err := anonymous.Tags(ctx, r.ref(repository, ""))
+	if err == nil {
+		return true, nil
+	}
+	var errResp *errcode.ErrorResponse
+	if errors.As(err, &errResp) && slices.Contains([]int{http.StatusUnauthorized, http.StatusForbidden}, errResp.StatusCode) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (r *OCIRegistry) ref(repository, suffix string) string {
+	return r.host + "/" + repository + suffix
+}
+
+// refSuffix returns the suffix of an image reference for a tag or a digest. Tags cannot contain a colon.
+func refSuffix(ref string) string {
+	if strings.Contains(ref, ":") {
+		return "@" + ref
+	}
+	return ":" + ref
+}
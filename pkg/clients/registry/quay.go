@@ -0,0 +1,183 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/oras"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const QuayAPIURL = "https://quay.io/api/v1"
+
+// QuayTag is a tag returned by the Quay API.
+type QuayTag struct {
+	Name   string `json:"name,omitempty"`
+	Digest string `json:"manifest_digest"`
+}
+
+// QuayTagsResponse is the response of the Quay API listing the tags of a repository.
+type QuayTagsResponse struct {
+	Tags          []QuayTag `json:"tags"`
+	HasAdditional bool      `json:"has_additional,omitempty"`
+}
+
+// QuayManifestResponse is the response of the Quay API describing a manifest.
+// Layers describe the layers of the image, ManifestData is the raw manifest.
+type QuayManifestResponse struct {
+	Digest       string `json:"digest,omitempty"`
+	ManifestData string `json:"manifest_data,omitempty"`
+	Layers       []any  `json:"layers"`
+}
+
+type quayRepositoryResponse struct {
+	IsPublic bool `json:"is_public"`
+}
+
+// QuayRegistry is a Registry using the Quay API for tags, manifests and the repository visibility,
+// and the OCI Distribution API of quay.io for the referrers.
+type QuayRegistry struct {
+	*OCIRegistry
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+// NewQuayRegistry returns a QuayRegistry for quay.io. The token is optional: without it only public repositories can be queried.
+func NewQuayRegistry(token string) *QuayRegistry {
+	return NewQuayRegistryWithAPI(QuayHost, QuayAPIURL, token)
+}
+
+// NewQuayRegistryWithAPI returns a QuayRegistry for a Quay instance other than quay.io.
+func NewQuayRegistryWithAPI(host, apiURL, token string) *QuayRegistry {
+	return &QuayRegistry{
+		OCIRegistry: NewOCIRegistry(host, oras.DefaultCredentials()),
+		apiURL:      apiURL,
+		token:       token,
+		httpClient:  &http.Client{},
+	}
+}
+
+func (q *QuayRegistry) ListTags(ctx context.Context, repository string) ([]string, error) {
+	var tags []string
+	for page := 1; ; page++ {
+		res := QuayTagsResponse{}
+		query := url.Values{"page": {fmt.Sprint(page)}, "limit": {"100"}, "onlyActiveTags": {"true"}}
+		if err := q.get(ctx, fmt.Sprintf("/repository/%s/tag/", repository), query, &res); err != nil {
+			return nil, err
+		}
+		for _, tag := range res.Tags {
+			tags = append(tags, tag.Name)
+		}
+		if !res.HasAdditional {
+			return tags, nil
+		}
+	}
+}
+
+func (q *QuayRegistry) GetTag(ctx context.Context, repository, tag string) (Tag, error) {
+	res := QuayTagsResponse{}
+	query := url.Values{"specificTag": {tag}, "onlyActiveTags": {"true"}}
+	if err := q.get(ctx, fmt.Sprintf("/repository/%s/tag/", repository), query, &res); err != nil {
+		return Tag{}, err
+	}
+	if len(res.Tags) < 1 {
+		return Tag{}, fmt.Errorf("%w: %s/%s:%s", ErrTagNotFound, q.Host(), repository, tag)
+	}
+	return Tag{Name: tag, Digest: res.Tags[0].Digest}, nil
+}
+
+func (q *QuayRegistry) GetManifest(ctx context.Context, repository, ref string) (ocispec.Manifest, error) {
+	manifestDigest := ref
+	if !strings.Contains(ref, ":") {
+		tag, err := q.GetTag(ctx, repository, ref)
+		if err != nil {
+			return ocispec.Manifest{}, err
+		}
+		manifestDigest = tag.Digest
+	}
+
+	res := QuayManifestResponse{}
+	if err := q.get(ctx, fmt.Sprintf("/repository/%s/manifest/%s", repository, manifestDigest), nil, &res); err != nil {
+		return ocispec.Manifest{}, err
+	}
+	manifest := ocispec.Manifest{}
+	if res.ManifestData != "" {
+		if err := json.Unmarshal([]byte(res.ManifestData), &manifest); err != nil {
+			return manifest, fmt.Errorf("failed to unmarshal manifest of %s/%s@%s: %+v", q.Host(), repository, manifestDigest, err)
+		}
+		return manifest, nil
+	}
+	// without the manifest data, only the digests of the layers are known
+	for _, layer := range res.Layers {
+		desc := ocispec.Descriptor{}
+		if l, ok := layer.(map[string]any); ok {
+			if blobDigest, ok := l["blob_digest"].(string); ok {
+				desc.Digest = digest.Digest(blobDigest)
+			}
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+	return manifest, nil
+}
+
+// IsRepositoryPublic returns the visibility of the repository. Without a token, private repositories cannot be accessed.
+func (q *QuayRegistry) IsRepositoryPublic(ctx context.Context, repository string) (bool, error) {
+	res := quayRepositoryResponse{}
+	if err := q.get(ctx, fmt.Sprintf("/repository/%s", repository), nil, &res); err != nil {
+		var statusErr *quayStatusError
+		if q.token == "" && errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden) {
+			return false, nil
+		}
+		return false, err
+	}
+	return res.IsPublic, nil
+}
+
+func (q *QuayRegistry) get(ctx context.Context, path string, query url.Values, result any) error {
+	reqURL := q.apiURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	if q.token != "" {
+		req.Header.Set("Authorization", "Bearer "+q.token)
+	}
+	res, err := q.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot get %s from quay: %+v", reqURL, err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read body of a response from quay regarding %s: %+v", reqURL, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return &quayStatusError{URL: reqURL, StatusCode: res.StatusCode, Body: string(body)}
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response from quay regarding %s: %+v", reqURL, err)
+	}
+	return nil
+}
+
+// quayStatusError is returned when the Quay API doesn't respond with 200 OK.
+type quayStatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *quayStatusError) Error() string {
+	return fmt.Sprintf("unexpected response from quay regarding %s, status code: %d, response body: %s", e.URL, e.StatusCode, e.Body)
+}
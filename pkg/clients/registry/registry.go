@@ -0,0 +1,98 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/oras"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/openshift/library-go/pkg/image/reference"
+)
+
+const QuayHost = "quay.io"
+
+// ErrTagNotFound is returned when the requested tag doesn't exist in the repository
+var ErrTagNotFound = errors.New("tag not found")
+
+// Tag is a tag of a repository and the digest of the manifest it points to.
+type Tag struct {
+	Name   string
+	Digest string
+}
+
+// Registry queries a container registry, so that the same assertions can run against
+// quay.io and a plain OCI Distribution registry like registry:2 or Zot.
+// The repository arguments don't contain the registry host, e.g. "org/repo".
+type Registry interface {
+	// Host returns the host of the registry, e.g. "quay.io" or "localhost:5000"
+	Host() string
+	// ListTags returns all the tags of the repository
+	ListTags(ctx context.Context, repository string) ([]string, error)
+	// GetTag returns the tag of the repository, ErrTagNotFound if it doesn't exist
+	GetTag(ctx context.Context, repository, tag string) (Tag, error)
+	// GetManifest returns the image manifest referenced by a tag or a digest
+	GetManifest(ctx context.Context, repository, ref string) (ocispec.Manifest, error)
+	// Referrers returns the artifacts attached to the manifest with the given digest, optionally filtered by artifactType
+	Referrers(ctx context.Context, repository, digest, artifactType string) ([]ocispec.Descriptor, error)
+	// IsRepositoryPublic reports whether the repository can be pulled anonymously
+	IsRepositoryPublic(ctx context.Context, repository string) (bool, error)
+}
+
+// ForImage returns the Registry hosting the given image and the repository of the image, e.g. "org/repo".
+// quay.io images are served by a QuayRegistry, any other image by an OCIRegistry.
+func ForImage(image string) (Registry, string, error) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot parse image %s: %+v", image, err)
+	}
+	if ref.Name == "" {
+		return nil, "", fmt.Errorf("image %s does not have a repository", image)
+	}
+	ref = ref.DockerClientDefaults()
+	return ForHost(ref.Registry), ref.RepositoryName(), nil
+}
+
+// ForHost returns the Registry for the given host.
+func ForHost(host string) Registry {
+	if host == QuayHost {
+		return NewQuayRegistry(os.Getenv(constants.DEFAULT_QUAY_ORG_TOKEN_ENV))
+	}
+	return NewOCIRegistry(host, oras.DefaultCredentials())
+}
+
+// TagExists reports whether the tag of the image exists in its registry.
+// imageURL format example: quay.io/redhat-appstudio-qe/devfile-go-rhtap-uvv7:build-66d4e-1685533053
+func TagExists(ctx context.Context, imageURL string) (bool, error) {
+	ref, err := reference.Parse(imageURL)
+	if err != nil {
+		return false, err
+	}
+	if ref.Tag == "" {
+		return false, fmt.Errorf("image URL %s does not have tag", imageURL)
+	}
+	registry, repository, err := ForImage(imageURL)
+	if err != nil {
+		return false, err
+	}
+	if _, err := registry.GetTag(ctx, repository, ref.Tag); err != nil {
+		if errors.Is(err, ErrTagNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// isInsecure reports whether the registry host is reached over plain HTTP.
+func isInsecure(host string) bool {
+	hostname, _, _ := strings.Cut(host, ":")
+	if hostname == "localhost" || hostname == "127.0.0.1" {
+		return true
+	}
+	return slices.Contains(strings.Split(os.Getenv(constants.INSECURE_REGISTRIES_ENV), ","), host)
+}
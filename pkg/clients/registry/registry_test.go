@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ggcr "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/oras"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOCIRegistry(t *testing.T) {
+	server := httptest.NewServer(ggcr.New(ggcr.WithReferrersSupport(true)))
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "layer")
+	assert.NoError(t, os.WriteFile(path, []byte("image"), 0600))
+	client := &oras.Client{PlainHTTP: true}
+	image, err := client.Push(ctx, host+"/org/repo:v1", []oras.PushFile{{Path: path}}, oras.PushOptions{})
+	assert.NoError(t, err)
+	_, err = client.Push(ctx, host+"/org/repo", []oras.PushFile{{Path: path}},
+		oras.PushOptions{ArtifactType: "application/vnd.dev.cosign.simplesigning.v1+json", Subject: host + "/org/repo:v1"})
+	assert.NoError(t, err)
+
+	reg, repository, err := ForImage(host + "/org/repo:v1")
+	assert.NoError(t, err)
+	assert.IsType(t, &OCIRegistry{}, reg)
+	assert.Equal(t, "org/repo", repository)
+
+	tags, err := reg.ListTags(ctx, repository)
+	assert.NoError(t, err)
+	assert.Contains(t, tags, "v1")
+
+	tag, err := reg.GetTag(ctx, repository, "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, image.Digest.String(), tag.Digest)
+
+	_, err = reg.GetTag(ctx, repository, "missing")
+	assert.ErrorIs(t, err, ErrTagNotFound)
+
+	exists, err := TagExists(ctx, host+"/org/repo:missing")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	manifest, err := reg.GetManifest(ctx, repository, tag.Digest)
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Layers, 1)
+
+	referrers, err := reg.Referrers(ctx, repository, tag.Digest, "application/vnd.dev.cosign.simplesigning.v1+json")
+	assert.NoError(t, err)
+	assert.Len(t, referrers, 1)
+
+	public, err := reg.IsRepositoryPublic(ctx, repository)
+	assert.NoError(t, err)
+	assert.True(t, public)
+}
+
+func TestQuayRegistry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var res any
+		switch {
+		case r.URL.Path == "/repository/org/private":
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		case r.URL.Path == "/repository/org/repo":
+			res = quayRepositoryResponse{IsPublic: true}
+		case r.URL.Path == "/repository/org/repo/tag/" && r.URL.Query().Get("specificTag") == "v1":
+			res = QuayTagsResponse{Tags: []QuayTag{{Name: "v1", Digest: "sha256:abc"}}}
+		case r.URL.Path == "/repository/org/repo/tag/" && r.URL.Query().Get("page") == "1":
+			res = QuayTagsResponse{Tags: []QuayTag{{Name: "v1"}}, HasAdditional: true}
+		case r.URL.Path == "/repository/org/repo/tag/" && r.URL.Query().Get("page") == "2":
+			res = QuayTagsResponse{Tags: []QuayTag{{Name: "v2"}}}
+		case r.URL.Path == "/repository/org/repo/tag/":
+			res = QuayTagsResponse{}
+		case r.URL.Path == "/repository/org/repo/manifest/sha256:abc":
+			res = QuayManifestResponse{Layers: []any{map[string]any{"blob_digest": "sha256:layer"}}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(server.Close)
+	reg := NewQuayRegistryWithAPI("quay.example.com", server.URL, "")
+	ctx := context.Background()
+
+	tags, err := reg.ListTags(ctx, "org/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2"}, tags)
+
+	tag, err := reg.GetTag(ctx, "org/repo", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, Tag{Name: "v1", Digest: "sha256:abc"}, tag)
+
+	_, err = reg.GetTag(ctx, "org/repo", "missing")
+	assert.ErrorIs(t, err, ErrTagNotFound)
+
+	manifest, err := reg.GetManifest(ctx, "org/repo", "v1")
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Layers, 1)
+	assert.Equal(t, "sha256:layer", manifest.Layers[0].Digest.String())
+
+	public, err := reg.IsRepositoryPublic(ctx, "org/repo")
+	assert.NoError(t, err)
+	assert.True(t, public)
+
+	public, err = reg.IsRepositoryPublic(ctx, "org/private")
+	assert.NoError(t, err)
+	assert.False(t, public)
+}
+
+func TestForImage(t *testing.T) {
+	reg, repository, err := ForImage("quay.io/org/repo:tag")
+	assert.NoError(t, err)
+	assert.IsType(t, &QuayRegistry{}, reg)
+	assert.Equal(t, "org/repo", repository)
+
+	t.Setenv("INSECURE_REGISTRIES", "zot.example.com:5000")
+	reg, _, err = ForImage("zot.example.com:5000/org/repo:tag")
+	assert.NoError(t, err)
+	assert.True(t, reg.(*OCIRegistry).client.PlainHTTP)
+}
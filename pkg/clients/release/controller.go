@@ -1,16 +1,31 @@
 package release
 
-import kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+import (
+	"net/http"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+)
 
 // Factory to initialize the comunication against different API like github or kubernetes.
 type ReleaseController struct {
 	// Generates a kubernetes client to interact with clusters.
 	*kubeCl.CustomClient
+
+	// pyxisURL and pyxisClient redirect Pyxis requests to another server, e.g. FakePyxisServer. Empty means stage Pyxis.
+	pyxisURL    string
+	pyxisClient *http.Client
 }
 
 // Initializes all the clients and return interface to operate with release controller.
 func NewSuiteController(kube *kubeCl.CustomClient) (*ReleaseController, error) {
 	return &ReleaseController{
-		kube,
+		CustomClient: kube,
 	}, nil
 }
+
+// UsePyxisServer makes all Pyxis requests go to the server with the given base URL using the given http client
+// instead of the stage Pyxis API. Passing an empty baseURL switches back to stage Pyxis.
+func (r *ReleaseController) UsePyxisServer(baseURL string, client *http.Client) {
+	r.pyxisURL = baseURL
+	r.pyxisClient = client
+}
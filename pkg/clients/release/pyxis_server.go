@@ -0,0 +1,127 @@
+package release
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// FakePyxisServer is an in-process stand-in for the Pyxis API serving the image, repository and
+// content manifest (SBOM) endpoints used by the ReleaseController.
+// Point a ReleaseController at it with ReleaseController.UsePyxisServer(server.URL(), server.Client()).
+type FakePyxisServer struct {
+	server           *httptest.Server
+	images           map[string]Image
+	repositories     map[string]Repository
+	contentManifests map[string]ContentManifestSbom
+	requests         []string
+	mu               sync.Mutex
+}
+
+// NewFakePyxisServer starts a new FakePyxisServer. Call Close when done.
+func NewFakePyxisServer() *FakePyxisServer {
+	f := &FakePyxisServer{
+		images:           map[string]Image{},
+		repositories:     map[string]Repository{},
+		contentManifests: map[string]ContentManifestSbom{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/images/id/", f.handleImage)
+	mux.HandleFunc("/v1/repositories/registry/", f.handleRepository)
+	mux.HandleFunc("/v1/content-manifests/id/", f.handleContentManifest)
+	f.server = httptest.NewTLSServer(mux)
+	return f
+}
+
+// URL returns the base URL of the server.
+func (f *FakePyxisServer) URL() string {
+	return f.server.URL
+}
+
+// ImagesApiEndpoint returns the images endpoint, equivalent to the stage one passed to GetPyxisImageByImageID.
+func (f *FakePyxisServer) ImagesApiEndpoint() string {
+	return f.server.URL + "/v1/images/id/"
+}
+
+// Client returns an http client which trusts the server certificate.
+func (f *FakePyxisServer) Client() *http.Client {
+	return f.server.Client()
+}
+
+// Close shuts the server down.
+func (f *FakePyxisServer) Close() {
+	f.server.Close()
+}
+
+// AddImage makes the given image available under its ID.
+func (f *FakePyxisServer) AddImage(image Image) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.images[image.ID] = image
+}
+
+// AddRepository makes the given repository available under its registry and repository name.
+func (f *FakePyxisServer) AddRepository(repository Repository) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.repositories[repository.Registry+"/repository/"+repository.Repository] = repository
+}
+
+// AddContentManifest makes the given SBOM content manifest available under its ID.
+func (f *FakePyxisServer) AddContentManifest(contentManifest ContentManifestSbom) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.contentManifests[contentManifest.ID] = contentManifest
+}
+
+// Requests returns the paths of all the requests received by the server.
+func (f *FakePyxisServer) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.requests...)
+}
+
+func (f *FakePyxisServer) handleImage(w http.ResponseWriter, req *http.Request) {
+	f.serve(w, req, "/v1/images/id/", func(key string) (interface{}, bool) {
+		image, ok := f.images[key]
+		return image, ok
+	})
+}
+
+func (f *FakePyxisServer) handleRepository(w http.ResponseWriter, req *http.Request) {
+	f.serve(w, req, "/v1/repositories/registry/", func(key string) (interface{}, bool) {
+		repository, ok := f.repositories[key]
+		return repository, ok
+	})
+}
+
+func (f *FakePyxisServer) handleContentManifest(w http.ResponseWriter, req *http.Request) {
+	f.serve(w, req, "/v1/content-manifests/id/", func(key string) (interface{}, bool) {
+		contentManifest, ok := f.contentManifests[key]
+		return contentManifest, ok
+	})
+}
+
+// serve looks up the object identified by the request path without the given prefix and writes it
+// as JSON, mimicking the Pyxis error response when it doesn't exist.
+func (f *FakePyxisServer) serve(w http.ResponseWriter, req *http.Request, prefix string, lookup func(key string) (interface{}, bool)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req.URL.Path)
+
+	w.Header().Set("Content-Type", "application/json")
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": http.StatusMethodNotAllowed, "title": "Method Not Allowed"})
+		return
+	}
+	obj, ok := lookup(strings.TrimPrefix(req.URL.Path, prefix))
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": http.StatusNotFound, "title": "Not Found", "detail": "The requested URL was not found on the server."})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(obj)
+}
@@ -0,0 +1,83 @@
+package release
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pyxisTestDigest = "sha256:544259be8bcd9e6a2066224b805d854d863064c9b64fa3a87bfcd03f5b0f28e6"
+
+func newFakePyxisReleaseController(t *testing.T) (*ReleaseController, *FakePyxisServer) {
+	server := NewFakePyxisServer()
+	t.Cleanup(server.Close)
+	server.AddImage(Image{ID: "image-1", DockerImageDigest: pyxisTestDigest, ContentManifest: ContentManifest{ID: "cm-1"}})
+	server.AddImage(Image{ID: "image-2", DockerImageDigest: pyxisTestDigest})
+	server.AddRepository(Repository{ID: "repo-1", Registry: "quay.io", Repository: "redhat-pending/dcmetromap", Published: true})
+	server.AddContentManifest(ContentManifestSbom{ID: "cm-1", ImageID: "image-1", Components: []SbomComponent{
+		{BomRef: "pkg:rpm/bash", Type: "library", Name: "bash", Version: "5.1", Purl: "pkg:rpm/rhel/bash@5.1"},
+	}})
+
+	r := &ReleaseController{}
+	r.UsePyxisServer(server.URL(), server.Client())
+	return r, server
+}
+
+func TestGetPyxisImageByImageIDFromFakeServer(t *testing.T) {
+	r, server := newFakePyxisReleaseController(t)
+
+	// the stage endpoint used by the specs is redirected to the fake server
+	body, err := r.GetPyxisImageByImageID(PyxisStageURL+"/v1/images/id/", "image-1", nil, nil)
+	assert.NoError(t, err)
+	image := Image{}
+	assert.NoError(t, json.Unmarshal(body, &image))
+	assert.Equal(t, pyxisTestDigest, image.DockerImageDigest)
+	assert.Equal(t, []string{"/v1/images/id/image-1"}, server.Requests())
+}
+
+func TestGetPyxisImageByImageIDWithServerURL(t *testing.T) {
+	r, server := newFakePyxisReleaseController(t)
+
+	// URLs which are not stage Pyxis ones are not rewritten
+	body, err := r.GetPyxisImageByImageID(server.ImagesApiEndpoint(), "image-2", nil, nil)
+	assert.NoError(t, err)
+	image := Image{}
+	assert.NoError(t, json.Unmarshal(body, &image))
+	assert.Equal(t, "image-2", image.ID)
+	assert.Equal(t, []string{"/v1/images/id/image-2"}, server.Requests())
+}
+
+func TestGetPyxisImageVerification(t *testing.T) {
+	r, _ := newFakePyxisReleaseController(t)
+
+	image, err := r.GetPyxisImage("image-1", pyxisTestDigest, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "cm-1", image.ContentManifest.ID)
+
+	_, err = r.GetPyxisImage("image-1", "sha256:other", nil, nil)
+	assert.ErrorContains(t, err, "expected 'sha256:other'")
+
+	_, err = r.GetPyxisImage("missing", "", nil, nil)
+	assert.ErrorContains(t, err, "failed with status 404")
+}
+
+func TestGetPyxisRepositoryAndSbom(t *testing.T) {
+	r, _ := newFakePyxisReleaseController(t)
+
+	repo, err := r.GetPyxisRepository("quay.io", "redhat-pending/dcmetromap", nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, repo.Published)
+
+	image, err := r.GetPyxisImage("image-1", "", nil, nil)
+	assert.NoError(t, err)
+	sbom, err := r.GetPyxisImageSbom(image, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, sbom.Components, 1)
+	assert.Equal(t, "pkg:rpm/rhel/bash@5.1", sbom.Components[0].Purl)
+
+	image, err = r.GetPyxisImage("image-2", "", nil, nil)
+	assert.NoError(t, err)
+	_, err = r.GetPyxisImageSbom(image, nil, nil)
+	assert.ErrorContains(t, err, "doesn't reference any content manifest")
+}
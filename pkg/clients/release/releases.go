@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/logs"
 	"github.com/konflux-ci/e2e-tests/pkg/utils/tekton"
@@ -14,12 +16,12 @@ import (
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/yaml"
 )
 
 // CreateRelease creates a new Release using the given parameters.
@@ -74,7 +76,10 @@ func (r *ReleaseController) GetRelease(releaseName, snapshotName, namespace stri
 		release := &releaseApi.Release{}
 		err := r.KubeRest().Get(ctx, types.NamespacedName{Name: releaseName, Namespace: namespace}, release)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get Release with name '%s' in '%s' namespace", releaseName, namespace)
+			if k8sErrors.IsNotFound(err) {
+				return nil, clienterrors.NotFound("Release", namespace, releaseName, "failed to get Release with name '%s' in '%s' namespace", releaseName, namespace)
+			}
+			return nil, fmt.Errorf("failed to get Release with name '%s' in '%s' namespace: %+v", releaseName, namespace, err)
 		}
 		return release, nil
 	}
@@ -90,18 +95,13 @@ func (r *ReleaseController) GetRelease(releaseName, snapshotName, namespace stri
 			return &r, nil
 		}
 	}
-	return nil, fmt.Errorf("could not find Release CR based on associated Snapshot '%s' in '%s' namespace", snapshotName, namespace)
+	return nil, clienterrors.NotFound("Release", namespace, snapshotName, "could not find Release CR based on associated Snapshot '%s' in '%s' namespace", snapshotName, namespace)
 }
 
 // GetReleases returns the list of Release CR in the given namespace.
 func (r *ReleaseController) GetReleases(namespace string) (*releaseApi.ReleaseList, error) {
-	releaseList := &releaseApi.ReleaseList{}
-	opts := []client.ListOption{
-		client.InNamespace(namespace),
-	}
-	err := r.KubeRest().List(context.Background(), releaseList, opts...)
-
-	return releaseList, err
+	releases, err := common.ListResources[releaseApi.Release](r.KubeRest(), namespace, nil)
+	return &releaseApi.ReleaseList{Items: releases}, err
 }
 
 // StoreRelease stores a given Release as an artifact.
@@ -110,24 +110,16 @@ func (r *ReleaseController) StoreRelease(release *releaseApi.Release) error {
 		return fmt.Errorf("release CR is nil")
 	}
 
-	artifacts := make(map[string][]byte)
 	releaseConditionStatus, err := r.GetReleaseConditionStatusMessages(release.Name, release.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to get release condition status: %w", err)
 	}
-	artifacts["release-condition-status-"+release.Name+".log"] = []byte(strings.Join(releaseConditionStatus, "\n"))
-
-	releaseYaml, err := yaml.Marshal(release)
-	if err != nil {
-		return fmt.Errorf("failed to marshal release YAML: %w", err)
-	}
-	artifacts["release-"+release.Name+".yaml"] = releaseYaml
-
+	artifacts := map[string][]byte{"release-condition-status-" + release.Name + ".log": []byte(strings.Join(releaseConditionStatus, "\n"))}
 	if err := logs.StoreArtifacts(artifacts); err != nil {
 		return fmt.Errorf("failed to store artifacts: %w", err)
 	}
 
-	return nil
+	return common.StoreResource(r.KubeRest(), release)
 }
 
 // Get the message from the status of a release. Useful for debugging purposes.
@@ -147,9 +139,12 @@ func (r *ReleaseController) GetReleaseConditionStatusMessages(name, namespace st
 func (r *ReleaseController) GetFirstReleaseInNamespace(namespace string) (*releaseApi.Release, error) {
 	releaseList, err := r.GetReleases(namespace)
 
-	if err != nil || len(releaseList.Items) < 1 {
+	if err != nil {
 		return nil, fmt.Errorf("could not find any Releases in namespace %s: %+v", namespace, err)
 	}
+	if len(releaseList.Items) < 1 {
+		return nil, clienterrors.NotFound("Release", namespace, "", "could not find any Releases in namespace %s", namespace)
+	}
 	return &releaseList.Items[0], nil
 }
 
@@ -169,6 +164,9 @@ func (r *ReleaseController) GetPipelineRunInNamespace(namespace, releaseName, re
 	if err == nil && len(pipelineRuns.Items) > 0 {
 		return &pipelineRuns.Items[0], nil
 	}
+	if err == nil {
+		return nil, clienterrors.NotFound("PipelineRun", namespace, releaseName, "couldn't find PipelineRun in managed namespace '%s' for a release '%s' in '%s' namespace", namespace, releaseName, releaseNamespace)
+	}
 
 	return nil, fmt.Errorf("couldn't find PipelineRun in managed namespace '%s' for a release '%s' in '%s' namespace because of err:'%w'", namespace, releaseName, releaseNamespace, err)
 }
@@ -177,9 +175,11 @@ func (r *ReleaseController) GetPipelineRunInNamespace(namespace, releaseName, re
 // In case of failure, this function retries till it gets timed out.
 func (r *ReleaseController) WaitForReleasePipelineToGetStarted(release *releaseApi.Release, managedNamespace string) (*pipeline.PipelineRun, error) {
 	var releasePipelinerun *pipeline.PipelineRun
+	var lastErr error
 
 	err := wait.PollUntilContextTimeout(context.Background(), time.Second*2, time.Minute*5, true, func(ctx context.Context) (done bool, err error) {
 		releasePipelinerun, err = r.GetPipelineRunInNamespace(managedNamespace, release.GetName(), release.GetNamespace())
+		lastErr = err
 		if err != nil {
 			GinkgoWriter.Println("PipelineRun has not been created yet for release %s/%s", release.GetNamespace(), release.GetName())
 			return false, nil
@@ -190,15 +190,18 @@ func (r *ReleaseController) WaitForReleasePipelineToGetStarted(release *releaseA
 		}
 		return true, nil
 	})
+	operation := fmt.Sprintf("release PipelineRun of release %s/%s to start", release.GetNamespace(), release.GetName())
 
-	return releasePipelinerun, err
+	return releasePipelinerun, clienterrors.WrapTimeout(err, operation, time.Minute*5, "", lastErr)
 }
 
 // WaitForReleasePipelineToBeFinished wait for given release pipeline to finish.
 // It exposes the error message from the failed task to the end user when the pipelineRun failed.
 func (r *ReleaseController) WaitForReleasePipelineToBeFinished(release *releaseApi.Release, managedNamespace string) error {
-	return wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 30*time.Minute, true, func(ctx context.Context) (done bool, err error) {
+	var lastErr error
+	err := wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, 30*time.Minute, true, func(ctx context.Context) (done bool, err error) {
 		pipelineRun, err := r.GetPipelineRunInNamespace(managedNamespace, release.GetName(), release.GetNamespace())
+		lastErr = err
 		if err != nil {
 			GinkgoWriter.Println("PipelineRun has not been created yet for release %s/%s", release.GetNamespace(), release.GetName())
 			return false, nil
@@ -213,10 +216,12 @@ func (r *ReleaseController) WaitForReleasePipelineToBeFinished(release *releaseA
 			if pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
 				return true, nil
 			} else {
-				logs, _ := tekton.GetFailedPipelineRunLogs(r.KubeRest(), r.KubeInterface(), pipelineRun)
-				return false, fmt.Errorf("%s", logs)
+				return false, tekton.NewPipelineFailedError(r.KubeRest(), r.KubeInterface(), pipelineRun, true)
 			}
 		}
 		return false, nil
 	})
+	operation := fmt.Sprintf("release PipelineRun of release %s/%s to finish", release.GetNamespace(), release.GetName())
+
+	return clienterrors.WrapTimeout(err, operation, 30*time.Minute, "", lastErr)
 }
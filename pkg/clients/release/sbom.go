@@ -12,12 +12,17 @@ package release
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"strings"
 )
 
+// PyxisStageURL is the base URL of the stage Pyxis API
+const PyxisStageURL = "https://pyxis.preprod.api.redhat.com"
+
 // Defines a struct Links with fields for various types of links including artifacts, requests, RPM manifests,
 // test results, and vulnerabilities. Each field is represented by a corresponding struct type.
 type Links struct {
@@ -94,6 +99,31 @@ type Image struct {
 	ParsedData        ParsedData       `json:"parsed_data"`
 }
 
+// Repository defines the Pyxis container repository fields used to verify where an image was published.
+type Repository struct {
+	ID                string   `json:"_id"`
+	Registry          string   `json:"registry"`
+	Repository        string   `json:"repository"`
+	Published         bool     `json:"published"`
+	ReleaseCategories []string `json:"release_categories"`
+}
+
+// SbomComponent defines a single component of an SBOM uploaded to Pyxis.
+type SbomComponent struct {
+	BomRef  string `json:"bom_ref"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Purl    string `json:"purl"`
+}
+
+// ContentManifestSbom defines a Pyxis content manifest, which holds the SBOM components of an image.
+type ContentManifestSbom struct {
+	ID         string          `json:"_id"`
+	ImageID    string          `json:"image_id"`
+	Components []SbomComponent `json:"components"`
+}
+
 // GetPyxisImageByImageID makes a GET request to stage Pyxis to get an image
 // and returns it.
 func (r *ReleaseController) GetPyxisImageByImageID(pyxisStageImagesApiEndpoint, imageID string,
@@ -101,30 +131,105 @@ func (r *ReleaseController) GetPyxisImageByImageID(pyxisStageImagesApiEndpoint,
 
 	url := fmt.Sprintf("%s%s", pyxisStageImagesApiEndpoint, imageID)
 
-	// Create a TLS configuration with the key and certificate
-	cert, err := tls.X509KeyPair(pyxisCertDecoded, pyxisKeyDecoded)
+	body, _, err := r.pyxisGet(url, pyxisCertDecoded, pyxisKeyDecoded)
+	return body, err
+}
+
+// GetPyxisImage gets the image with the given ID from Pyxis and checks it has the expected digest.
+// An empty expectedDigest skips the digest check.
+func (r *ReleaseController) GetPyxisImage(imageID, expectedDigest string, pyxisCertDecoded, pyxisKeyDecoded []byte) (*Image, error) {
+	image := &Image{}
+	if err := r.getPyxisObject(fmt.Sprintf("%s/v1/images/id/%s", PyxisStageURL, imageID), image, pyxisCertDecoded, pyxisKeyDecoded); err != nil {
+		return nil, err
+	}
+	if image.ID != imageID {
+		return nil, fmt.Errorf("pyxis returned image with id '%s' instead of '%s'", image.ID, imageID)
+	}
+	if expectedDigest != "" && image.DockerImageDigest != expectedDigest {
+		return nil, fmt.Errorf("pyxis image '%s' has digest '%s', expected '%s'", imageID, image.DockerImageDigest, expectedDigest)
+	}
+	return image, nil
+}
+
+// GetPyxisRepository gets the Pyxis repository for the given registry and repository name.
+func (r *ReleaseController) GetPyxisRepository(registry, repository string, pyxisCertDecoded, pyxisKeyDecoded []byte) (*Repository, error) {
+	repo := &Repository{}
+	url := fmt.Sprintf("%s/v1/repositories/registry/%s/repository/%s", PyxisStageURL, registry, repository)
+	if err := r.getPyxisObject(url, repo, pyxisCertDecoded, pyxisKeyDecoded); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetPyxisImageSbom gets the SBOM content manifest referenced by the given Pyxis image.
+func (r *ReleaseController) GetPyxisImageSbom(image *Image, pyxisCertDecoded, pyxisKeyDecoded []byte) (*ContentManifestSbom, error) {
+	if image.ContentManifest.ID == "" {
+		return nil, fmt.Errorf("pyxis image '%s' doesn't reference any content manifest", image.ID)
+	}
+	sbom := &ContentManifestSbom{}
+	url := fmt.Sprintf("%s/v1/content-manifests/id/%s", PyxisStageURL, image.ContentManifest.ID)
+	if err := r.getPyxisObject(url, sbom, pyxisCertDecoded, pyxisKeyDecoded); err != nil {
+		return nil, err
+	}
+	if sbom.ImageID != "" && sbom.ImageID != image.ID {
+		return nil, fmt.Errorf("content manifest '%s' belongs to image '%s' instead of '%s'", sbom.ID, sbom.ImageID, image.ID)
+	}
+	return sbom, nil
+}
+
+// getPyxisObject gets the given Pyxis URL and unmarshals the response into the given object.
+func (r *ReleaseController) getPyxisObject(url string, obj interface{}, pyxisCertDecoded, pyxisKeyDecoded []byte) error {
+	body, statusCode, err := r.pyxisGet(url, pyxisCertDecoded, pyxisKeyDecoded)
 	if err != nil {
-		return nil, fmt.Errorf("error creating TLS certificate and key: %s", err)
+		return err
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("pyxis request to %s failed with status %d: %s", url, statusCode, string(body))
 	}
+	if err := json.Unmarshal(body, obj); err != nil {
+		return fmt.Errorf("error unmarshalling pyxis response from %s: %s", url, err)
+	}
+	return nil
+}
 
-	// Create a client with the custom TLS configuration
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				Certificates: []tls.Certificate{cert},
+// pyxisGet sends a GET request to the given Pyxis URL and returns the response body and status code.
+// When a Pyxis server was set with UsePyxisServer, stage Pyxis URLs are redirected there; other URLs are used as is.
+func (r *ReleaseController) pyxisGet(url string, pyxisCertDecoded, pyxisKeyDecoded []byte) ([]byte, int, error) {
+	var client *http.Client
+	if r.pyxisURL != "" {
+		if strings.HasPrefix(url, PyxisStageURL) {
+			url = r.pyxisURL + strings.TrimPrefix(url, PyxisStageURL)
+		}
+		client = r.pyxisClient
+		if client == nil {
+			client = http.DefaultClient
+		}
+	} else {
+		// Create a TLS configuration with the key and certificate
+		cert, err := tls.X509KeyPair(pyxisCertDecoded, pyxisKeyDecoded)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error creating TLS certificate and key: %s", err)
+		}
+
+		// Create a client with the custom TLS configuration
+		client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					Certificates: []tls.Certificate{cert},
+				},
 			},
-		},
+		}
 	}
 
 	// Send GET request
 	request, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating GET request: %s", err)
+		return nil, 0, fmt.Errorf("error creating GET request: %s", err)
 	}
 
 	response, err := client.Do(request)
 	if err != nil {
-		return nil, fmt.Errorf("error sending GET request: %s", err)
+		return nil, 0, fmt.Errorf("error sending GET request: %s", err)
 	}
 
 	defer response.Body.Close()
@@ -132,9 +237,9 @@ func (r *ReleaseController) GetPyxisImageByImageID(pyxisStageImagesApiEndpoint,
 	// Read the response body
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %s", err)
+		return nil, 0, fmt.Errorf("error reading response body: %s", err)
 	}
-	return body, nil
+	return body, response.StatusCode, nil
 }
 
 // GetPyxisImageIDsFromCreatePyxisImageTaskLogs takes a slice of task logs (as this is what
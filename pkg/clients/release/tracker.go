@@ -0,0 +1,230 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ReleaseStage is a Release condition type tracked by the ReleaseTracker.
+type ReleaseStage string
+
+const (
+	ValidatedStage       ReleaseStage = "Validated"
+	TenantPipelineStage  ReleaseStage = "TenantPipelineProcessed"
+	ManagedPipelineStage ReleaseStage = "ManagedPipelineProcessed"
+	FinalPipelineStage   ReleaseStage = "FinalPipelineProcessed"
+	PostActionsStage     ReleaseStage = "PostActionsExecuted"
+	ReleasedStage        ReleaseStage = "Released"
+)
+
+const releaseTrackerTimeout = 60 * time.Minute
+
+// ReleaseStages is the expected order in which the Release conditions are completed.
+var ReleaseStages = []ReleaseStage{
+	ValidatedStage,
+	TenantPipelineStage,
+	ManagedPipelineStage,
+	FinalPipelineStage,
+	PostActionsStage,
+	ReleasedStage,
+}
+
+// ReleaseTransition is a single observed change of a Release condition.
+type ReleaseTransition struct {
+	Stage   ReleaseStage
+	Status  metav1.ConditionStatus
+	Reason  string
+	Message string
+	// TransitionTime is the LastTransitionTime reported by the release-service
+	TransitionTime time.Time
+	// ObservedAt is the time the tracker noticed the transition
+	ObservedAt time.Time
+}
+
+// IsCompleted returns true if the transition marks the stage as finished, successfully or skipped.
+func (t ReleaseTransition) IsCompleted() bool {
+	return t.Status == metav1.ConditionTrue
+}
+
+// IsFailed returns true if the transition marks the stage as failed.
+func (t ReleaseTransition) IsFailed() bool {
+	return t.Status == metav1.ConditionFalse && t.Reason == releaseApi.FailedReason.String()
+}
+
+// ReleaseTracker follows a Release through its condition transitions and records when and why they happened.
+type ReleaseTracker struct {
+	controller  *ReleaseController
+	name        string
+	namespace   string
+	startedAt   time.Time
+	transitions []ReleaseTransition
+	last        map[ReleaseStage]ReleaseTransition
+	mu          sync.Mutex
+}
+
+// NewReleaseTracker returns a ReleaseTracker for the Release with the given name and namespace.
+func (r *ReleaseController) NewReleaseTracker(name, namespace string) *ReleaseTracker {
+	return &ReleaseTracker{
+		controller: r,
+		name:       name,
+		namespace:  namespace,
+		startedAt:  time.Now(),
+		last:       map[ReleaseStage]ReleaseTransition{},
+	}
+}
+
+// Observe records the condition transitions of the given Release which weren't seen before.
+func (t *ReleaseTracker) Observe(release *releaseApi.Release) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, stage := range ReleaseStages {
+		for _, condition := range release.Status.Conditions {
+			if condition.Type != string(stage) {
+				continue
+			}
+			previous, seen := t.last[stage]
+			if seen && previous.Status == condition.Status && previous.Reason == condition.Reason {
+				continue
+			}
+			transition := ReleaseTransition{
+				Stage:          stage,
+				Status:         condition.Status,
+				Reason:         condition.Reason,
+				Message:        condition.Message,
+				TransitionTime: condition.LastTransitionTime.Time,
+				ObservedAt:     now,
+			}
+			t.last[stage] = transition
+			t.transitions = append(t.transitions, transition)
+		}
+	}
+}
+
+// Transitions returns all the recorded transitions in the order they were observed.
+func (t *ReleaseTracker) Transitions() []ReleaseTransition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ReleaseTransition{}, t.transitions...)
+}
+
+// IsFinished returns true if the Release was released or any of its stages failed.
+func (t *ReleaseTracker) IsFinished() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, transition := range t.last {
+		if transition.IsFailed() {
+			return true
+		}
+	}
+	return t.last[ReleasedStage].IsCompleted()
+}
+
+// Track polls the Release and records its transitions until it finishes or the timeout is reached.
+// It returns an error if the Release failed, didn't finish in time or its stages didn't complete in the expected order.
+func (t *ReleaseTracker) Track(timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = releaseTrackerTimeout
+	}
+	err := wait.PollUntilContextTimeout(context.Background(), constants.PipelineRunPollingInterval, timeout, true, func(ctx context.Context) (done bool, err error) {
+		release, err := t.controller.GetRelease(t.name, "", t.namespace)
+		if err != nil {
+			GinkgoWriter.Printf("failed to get Release %s/%s: %v\n", t.namespace, t.name, err)
+			return false, nil
+		}
+		t.Observe(release)
+		return t.IsFinished(), nil
+	})
+	if err != nil {
+		return clienterrors.Timeout(fmt.Sprintf("release %s/%s to finish", t.namespace, t.name), timeout, "\n"+t.Summary(), err)
+	}
+	if failed := t.FailedStage(); failed != nil {
+		return fmt.Errorf("release %s/%s failed in stage %s: %s\n%s", t.namespace, t.name, failed.Stage, failed.Message, t.Summary())
+	}
+	return t.AssertOrder()
+}
+
+// FailedStage returns the last transition of the first failed stage or nil if no stage failed.
+func (t *ReleaseTracker) FailedStage() *ReleaseTransition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, stage := range ReleaseStages {
+		if transition, ok := t.last[stage]; ok && transition.IsFailed() {
+			return &transition
+		}
+	}
+	return nil
+}
+
+// AssertOrder checks that the stages completed following the expected state machine:
+// a stage can only complete after all the previous observed stages completed, and nothing completes after a failure.
+// Stages which were never reported (e.g. post-actions on older release-service versions) are ignored.
+func (t *ReleaseTracker) AssertOrder() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var previous *ReleaseTransition
+	for _, stage := range ReleaseStages {
+		transition, ok := t.last[stage]
+		if !ok {
+			continue
+		}
+		if previous != nil {
+			if previous.IsFailed() && transition.IsCompleted() && transition.Reason != releaseApi.SkippedReason.String() {
+				return fmt.Errorf("stage %s completed although the previous stage %s failed", stage, previous.Stage)
+			}
+			if transition.IsCompleted() && !previous.IsCompleted() && !previous.IsFailed() {
+				return fmt.Errorf("stage %s completed before the previous stage %s (%s)", stage, previous.Stage, previous.Reason)
+			}
+			if transition.IsCompleted() && previous.IsCompleted() && transition.TransitionTime.Before(previous.TransitionTime) {
+				return fmt.Errorf("stage %s completed at %s, before the previous stage %s completed at %s",
+					stage, transition.TransitionTime.Format(time.RFC3339), previous.Stage, previous.TransitionTime.Format(time.RFC3339))
+			}
+		}
+		previous = &transition
+	}
+	return nil
+}
+
+// Summary returns a table with all the recorded transitions and the time spent since the tracking started.
+func (t *ReleaseTracker) Summary() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Release %s/%s transitions:\n", t.namespace, t.name)
+	previous := t.startedAt
+	for _, transition := range t.transitions {
+		at := transition.TransitionTime
+		if at.IsZero() {
+			at = transition.ObservedAt
+		}
+		fmt.Fprintf(&sb, "  %-26s %-6s %-12s +%-8s (total %s) %s\n", transition.Stage, transition.Status, transition.Reason,
+			at.Sub(previous).Round(time.Second), at.Sub(t.startedAt).Round(time.Second), transition.Message)
+		if at.After(previous) {
+			previous = at
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Report attaches the summary to the Ginkgo report of the current spec and stores it as an artifact.
+func (t *ReleaseTracker) Report() {
+	summary := t.Summary()
+	AddReportEntry("release-tracker-"+t.name, summary)
+	if err := logs.StoreArtifacts(map[string][]byte{"release-tracker-" + t.name + ".log": []byte(summary)}); err != nil {
+		GinkgoWriter.Printf("failed to store release tracker summary: %v\n", err)
+	}
+}
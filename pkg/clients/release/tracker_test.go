@@ -0,0 +1,76 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTrackedRelease(conditions ...metav1.Condition) *releaseApi.Release {
+	return &releaseApi.Release{Status: releaseApi.ReleaseStatus{Conditions: conditions}}
+}
+
+func releaseCondition(stage ReleaseStage, status metav1.ConditionStatus, reason string, at time.Time) metav1.Condition {
+	return metav1.Condition{Type: string(stage), Status: status, Reason: reason, LastTransitionTime: metav1.NewTime(at)}
+}
+
+func TestReleaseTrackerRecordsTransitions(t *testing.T) {
+	tracker := (&ReleaseController{}).NewReleaseTracker("release", "ns")
+	start := time.Now()
+
+	tracker.Observe(newTrackedRelease(
+		releaseCondition(ValidatedStage, metav1.ConditionTrue, "Succeeded", start),
+		releaseCondition(ManagedPipelineStage, metav1.ConditionFalse, "Progressing", start),
+		releaseCondition(ReleasedStage, metav1.ConditionFalse, "Progressing", start),
+	))
+	// observing the same state again doesn't record anything new
+	tracker.Observe(newTrackedRelease(
+		releaseCondition(ValidatedStage, metav1.ConditionTrue, "Succeeded", start),
+	))
+	assert.Len(t, tracker.Transitions(), 3)
+	assert.False(t, tracker.IsFinished())
+
+	tracker.Observe(newTrackedRelease(
+		releaseCondition(ValidatedStage, metav1.ConditionTrue, "Succeeded", start),
+		releaseCondition(TenantPipelineStage, metav1.ConditionTrue, "Skipped", start.Add(time.Second)),
+		releaseCondition(ManagedPipelineStage, metav1.ConditionTrue, "Succeeded", start.Add(time.Minute)),
+		releaseCondition(FinalPipelineStage, metav1.ConditionTrue, "Skipped", start.Add(time.Minute)),
+		releaseCondition(ReleasedStage, metav1.ConditionTrue, "Succeeded", start.Add(2*time.Minute)),
+	))
+	assert.Len(t, tracker.Transitions(), 7)
+	assert.True(t, tracker.IsFinished())
+	assert.Nil(t, tracker.FailedStage())
+	assert.NoError(t, tracker.AssertOrder())
+	assert.Contains(t, tracker.Summary(), "ManagedPipelineProcessed")
+}
+
+func TestReleaseTrackerAssertOrder(t *testing.T) {
+	start := time.Now()
+
+	tracker := (&ReleaseController{}).NewReleaseTracker("release", "ns")
+	tracker.Observe(newTrackedRelease(
+		releaseCondition(ValidatedStage, metav1.ConditionTrue, "Succeeded", start),
+		releaseCondition(ManagedPipelineStage, metav1.ConditionFalse, "Progressing", start),
+		releaseCondition(ReleasedStage, metav1.ConditionTrue, "Succeeded", start),
+	))
+	assert.ErrorContains(t, tracker.AssertOrder(), "completed before the previous stage ManagedPipelineProcessed")
+
+	tracker = (&ReleaseController{}).NewReleaseTracker("release", "ns")
+	tracker.Observe(newTrackedRelease(
+		releaseCondition(ValidatedStage, metav1.ConditionTrue, "Succeeded", start.Add(time.Minute)),
+		releaseCondition(ManagedPipelineStage, metav1.ConditionTrue, "Succeeded", start),
+	))
+	assert.ErrorContains(t, tracker.AssertOrder(), "before the previous stage Validated completed")
+
+	tracker = (&ReleaseController{}).NewReleaseTracker("release", "ns")
+	tracker.Observe(newTrackedRelease(
+		releaseCondition(ManagedPipelineStage, metav1.ConditionFalse, "Failed", start),
+		releaseCondition(ReleasedStage, metav1.ConditionFalse, "Failed", start),
+	))
+	assert.True(t, tracker.IsFinished())
+	assert.Equal(t, ManagedPipelineStage, tracker.FailedStage().Stage)
+	assert.NoError(t, tracker.AssertOrder())
+}
@@ -8,10 +8,3 @@ const (
 	ErrorSeverityLevelError   = "Error"
 	ErrorSeverityLevelFatal   = "Fatal"
 )
-
-var alertEmojiType = map[ErrorSeverityLevel]string{
-	ErrorSeverityLevelInfo:    ":information_source:",
-	ErrorSeverityLevelWarning: ":warning:",
-	ErrorSeverityLevelError:   ":alert-siren:",
-	ErrorSeverityLevelFatal:   ":panic:",
-}
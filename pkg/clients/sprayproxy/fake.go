@@ -0,0 +1,93 @@
+package sprayproxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FakeSprayProxyServer is a local stand-in for SprayProxy, responding to listing requests with the "Backend urls:" text.
+type FakeSprayProxyServer struct {
+	server   *httptest.Server
+	token    string
+	backends map[string]Backend
+	mu       sync.Mutex
+}
+
+// NewFakeSprayProxyServer starts a FakeSprayProxyServer accepting the given bearer token. Call Close when done.
+func NewFakeSprayProxyServer(token string) *FakeSprayProxyServer {
+	f := &FakeSprayProxyServer{token: token, backends: map[string]Backend{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+	mux.HandleFunc("/backends", f.handleBackends)
+	f.server = httptest.NewTLSServer(mux)
+	return f
+}
+
+// URL returns the base URL of the server.
+func (f *FakeSprayProxyServer) URL() string {
+	return f.server.URL
+}
+
+// Close shuts the server down.
+func (f *FakeSprayProxyServer) Close() {
+	f.server.Close()
+}
+
+// Backends returns the currently registered backends sorted by URL.
+func (f *FakeSprayProxyServer) Backends() []Backend {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	backends := make([]Backend, 0, len(f.backends))
+	for _, b := range f.backends {
+		backends = append(backends, b)
+	}
+	sort.Slice(backends, func(i, j int) bool { return backends[i].URL < backends[j].URL })
+	return backends
+}
+
+func (f *FakeSprayProxyServer) handleBackends(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("Authorization") != "Bearer "+f.token {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if req.Method == http.MethodGet {
+		backends := f.Backends()
+		urls := make([]string, 0, len(backends))
+		for _, b := range backends {
+			urls = append(urls, b.URL)
+		}
+		fmt.Fprintf(w, "Backend urls:%s", strings.Join(urls, ","))
+		return
+	}
+
+	backend := Backend{}
+	if err := json.NewDecoder(req.Body).Decode(&backend); err != nil || backend.URL == "" {
+		http.Error(w, "request body must contain the backend url", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch req.Method {
+	case http.MethodPost:
+		f.backends[backend.URL] = backend
+		fmt.Fprintf(w, "registered backend %s", backend.URL)
+	case http.MethodDelete:
+		if _, ok := f.backends[backend.URL]; !ok {
+			http.Error(w, fmt.Sprintf("backend %s not found", backend.URL), http.StatusNotFound)
+			return
+		}
+		delete(f.backends, backend.URL)
+		fmt.Fprintf(w, "unregistered backend %s", backend.URL)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
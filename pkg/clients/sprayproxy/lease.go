@@ -0,0 +1,213 @@
+package sprayproxy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Lease is a registration of a PaC server in SprayProxy owned by a CI job for a limited time.
+// SprayProxy only stores the URLs of the registered backends, so the owner, job ID and expiry are kept
+// in the LeaseStore of the SprayProxyConfig. Release it on exit with ReleaseLeases and on interrupt
+// with ReleaseOnInterrupt; Reconcile removes the backends whose lease expired, e.g. after a job crashed.
+type Lease struct {
+	Backend   Backend
+	Owner     string
+	JobID     string
+	ExpiresAt time.Time
+
+	config   *SprayProxyConfig
+	released bool
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	mu       sync.Mutex
+}
+
+var (
+	activeLeases   []*Lease
+	activeLeasesMu sync.Mutex
+)
+
+// AcquireLease registers the PaC server in SprayProxy with a lease owned by the given owner and job ID, valid for ttl.
+// The lease is recorded in the LeaseStore of the config, if there is one.
+func (s *SprayProxyConfig) AcquireLease(pacHost, owner, jobID string, ttl time.Duration) (*Lease, error) {
+	backend := Backend{URL: pacHost}
+	if _, err := s.registerBackend(backend); err != nil {
+		return nil, fmt.Errorf("failed to register PaC server %s with a lease: %+v", pacHost, err)
+	}
+	lease := &Lease{Backend: backend, Owner: owner, JobID: jobID, ExpiresAt: time.Now().Add(ttl).UTC(), config: s}
+	if s.Leases != nil {
+		if err := s.Leases.Save(lease.record()); err != nil {
+			// don't leave a backend registered without a lease which would let Reconcile remove it
+			if _, unregisterErr := s.UnregisterServer(pacHost); unregisterErr != nil {
+				klog.Errorf("failed to unregister PaC server %s from SprayProxy: %+v", pacHost, unregisterErr)
+			}
+			return nil, fmt.Errorf("failed to record the lease of PaC server %s: %+v", pacHost, err)
+		}
+	}
+	activeLeasesMu.Lock()
+	activeLeases = append(activeLeases, lease)
+	activeLeasesMu.Unlock()
+	return lease, nil
+}
+
+// Release unregisters the PaC server from SprayProxy and deletes its lease record. Releasing a lease more than once
+// is a no-op, releasing it again after a failed release retries unregistering the PaC server.
+func (l *Lease) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return nil
+	}
+	if err := l.config.unregisterLeased(l.Backend.URL); err != nil {
+		return err
+	}
+	l.released = true
+	l.stopOnce.Do(func() {
+		if l.stopCh != nil {
+			close(l.stopCh)
+		}
+	})
+
+	activeLeasesMu.Lock()
+	defer activeLeasesMu.Unlock()
+	for i, lease := range activeLeases {
+		if lease == l {
+			activeLeases = append(activeLeases[:i], activeLeases[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// ReleaseLeases releases all the leases acquired by the process which were not released yet.
+// Call it before the process exits, e.g. with defer in the mage target registering the PaC server.
+func ReleaseLeases() error {
+	activeLeasesMu.Lock()
+	leases := append([]*Lease{}, activeLeases...)
+	activeLeasesMu.Unlock()
+
+	var errs []error
+	for _, lease := range leases {
+		if err := lease.Release(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unregister PaC server %s (job %s) from SprayProxy: %+v", lease.Backend.URL, lease.JobID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReleaseOnInterrupt releases the lease when the process receives SIGINT or SIGTERM,
+// then lets the signal terminate the process as it would without the handler.
+func (l *Lease) ReleaseOnInterrupt() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopCh != nil || l.released {
+		return
+	}
+	l.stopCh = make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	go func(stopCh chan struct{}) {
+		defer signal.Stop(sigCh)
+		select {
+		case sig := <-sigCh:
+			klog.Infof("received %s, unregistering PaC server %s from SprayProxy", sig, l.Backend.URL)
+			if err := l.Release(); err != nil {
+				klog.Errorf("failed to unregister PaC server %s from SprayProxy: %+v", l.Backend.URL, err)
+			}
+			signal.Stop(sigCh)
+			if p, err := os.FindProcess(os.Getpid()); err == nil {
+				_ = p.Signal(sig)
+			}
+		case <-stopCh:
+		}
+	}(l.stopCh)
+}
+
+// Reconcile removes the backends whose lease in the LeaseStore expired and the backends for which isReachable
+// returns false, e.g. the ones left behind by crashed CI jobs. Expired leases of backends which are not registered
+// anymore are deleted too. A nil isReachable defaults to IsBackendReachable. It returns the URLs of the removed backends.
+func (s *SprayProxyConfig) Reconcile(isReachable func(url string) bool) ([]string, error) {
+	if isReachable == nil {
+		isReachable = IsBackendReachable
+	}
+	backends, err := s.ListBackends()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registered PaC servers from SprayProxy: %+v", err)
+	}
+	expired := map[string]LeaseRecord{}
+	if s.Leases != nil {
+		records, err := s.Leases.List()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the leases of PaC servers registered in SprayProxy: %+v", err)
+		}
+		now := time.Now()
+		for _, record := range records {
+			if record.IsExpired(now) {
+				expired[record.URL] = record
+			}
+		}
+	}
+
+	var removed []string
+	for _, backend := range backends {
+		reason := ""
+		if record, ok := expired[backend.URL]; ok {
+			reason = fmt.Sprintf("lease of %s (job %s) expired at %s", record.Owner, record.JobID, record.ExpiresAt.Format(time.RFC3339))
+			delete(expired, backend.URL)
+		} else if !isReachable(backend.URL) {
+			reason = "backend is unreachable"
+		}
+		if reason == "" {
+			continue
+		}
+		if err := s.unregisterLeased(backend.URL); err != nil {
+			return removed, fmt.Errorf("error when unregistering PaC server %s from SprayProxy server %s: %+v", backend.URL, s.BaseURL, err)
+		}
+		klog.Infof("Removed PaC server %s from SprayProxy: %s", backend.URL, reason)
+		removed = append(removed, backend.URL)
+	}
+	for url := range expired {
+		if err := s.Leases.Delete(url); err != nil {
+			return removed, fmt.Errorf("failed to delete the expired lease of PaC server %s: %+v", url, err)
+		}
+	}
+	return removed, nil
+}
+
+// unregisterLeased unregisters the backend from SprayProxy, if it is still registered, and deletes its lease record.
+func (s *SprayProxyConfig) unregisterLeased(url string) error {
+	if res, err := s.UnregisterServer(url); err != nil && (res == nil || res.StatusCode != http.StatusNotFound) {
+		return err
+	}
+	if s.Leases != nil {
+		return s.Leases.Delete(url)
+	}
+	return nil
+}
+
+func (l *Lease) record() LeaseRecord {
+	return LeaseRecord{URL: l.Backend.URL, Owner: l.Owner, JobID: l.JobID, ExpiresAt: l.ExpiresAt}
+}
+
+// DefaultLeaseTTL is the validity of a lease acquired by CI jobs, longer than the longest e2e job.
+const DefaultLeaseTTL = 4 * time.Hour
+
+// LeaseOwner is the owner recorded in the leases acquired by e2e-tests CI jobs.
+const LeaseOwner = "konflux-e2e-tests"
+
+// JobIDFromEnv returns the ID of the CI job: the Prow job ID or the name of the Konflux PipelineRun.
+func JobIDFromEnv() string {
+	if jobID := os.Getenv("PROW_JOB_ID"); jobID != "" {
+		return jobID
+	}
+	return os.Getenv("JOB_NAME")
+}
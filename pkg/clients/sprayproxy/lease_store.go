@@ -0,0 +1,149 @@
+package sprayproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// leasesConfigMapName is the ConfigMap storing the leases of the backends registered in SprayProxy.
+const leasesConfigMapName = "sprayproxy-leases"
+
+// LeaseRecord is the owner, job ID and expiry of the lease of a backend registered in SprayProxy.
+type LeaseRecord struct {
+	URL       string    `json:"url"`
+	Owner     string    `json:"owner"`
+	JobID     string    `json:"jobId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// IsExpired returns true if the lease expired before now.
+func (r LeaseRecord) IsExpired(now time.Time) bool {
+	return r.ExpiresAt.Before(now)
+}
+
+// LeaseStore keeps the lease records where the other CI jobs and Reconcile can read them,
+// since SprayProxy itself only stores the URLs of the registered backends.
+type LeaseStore interface {
+	Save(record LeaseRecord) error
+	Delete(url string) error
+	List() ([]LeaseRecord, error)
+}
+
+// ConfigMapLeaseStore stores the lease records in a ConfigMap, one data key per backend.
+type ConfigMapLeaseStore struct {
+	Client    crclient.Client
+	Namespace string
+	Name      string
+}
+
+// NewConfigMapLeaseStore returns a ConfigMapLeaseStore using the sprayproxy-leases ConfigMap in the given namespace.
+func NewConfigMapLeaseStore(client crclient.Client, namespace string) *ConfigMapLeaseStore {
+	return &ConfigMapLeaseStore{Client: client, Namespace: namespace, Name: leasesConfigMapName}
+}
+
+// NewLeaseStoreFromEnv returns a ConfigMapLeaseStore in the cluster of the QE_SPRAYPROXY_LEASE_KUBECONFIG kubeconfig,
+// in the QE_SPRAYPROXY_LEASE_NAMESPACE namespace ("sprayproxy" by default). It returns nil if the kubeconfig is not set.
+func NewLeaseStoreFromEnv() (LeaseStore, error) {
+	kubeconfig := os.Getenv("QE_SPRAYPROXY_LEASE_KUBECONFIG")
+	if kubeconfig == "" {
+		return nil, nil
+	}
+	namespace := os.Getenv("QE_SPRAYPROXY_LEASE_NAMESPACE")
+	if namespace == "" {
+		namespace = sprayProxyNamespace
+	}
+	client, err := kubeCl.NewKubernetesClientForContext(kubeconfig, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a client for the SprayProxy leases from %s: %+v", kubeconfig, err)
+	}
+	return NewConfigMapLeaseStore(client.KubeRest(), namespace), nil
+}
+
+// Save creates or updates the record of the lease of its backend.
+func (s *ConfigMapLeaseStore) Save(record LeaseRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.update(func(data map[string]string) {
+		data[leaseKey(record.URL)] = string(value)
+	})
+}
+
+// Delete removes the record of the lease of the backend, deleting a missing record is a no-op.
+func (s *ConfigMapLeaseStore) Delete(url string) error {
+	return s.update(func(data map[string]string) {
+		delete(data, leaseKey(url))
+	})
+}
+
+// List returns the stored lease records sorted by backend URL.
+func (s *ConfigMapLeaseStore) List() ([]LeaseRecord, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.Client.Get(context.Background(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, cm); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	records := make([]LeaseRecord, 0, len(cm.Data))
+	for key, value := range cm.Data {
+		record := LeaseRecord{}
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse lease %s in ConfigMap %s/%s: %+v", key, s.Namespace, s.Name, err)
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].URL < records[j].URL })
+	return records, nil
+}
+
+// update applies fn to the data of the ConfigMap, creating it if it doesn't exist.
+// Concurrent updates by other CI jobs are retried.
+func (s *ConfigMapLeaseStore) update(fn func(data map[string]string)) error {
+	isConcurrentUpdate := func(err error) bool {
+		return k8sErrors.IsConflict(err) || k8sErrors.IsAlreadyExists(err)
+	}
+	return retry.OnError(retry.DefaultRetry, isConcurrentUpdate, func() error {
+		ctx := context.Background()
+		cm := &corev1.ConfigMap{}
+		err := s.Client.Get(ctx, types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, cm)
+		if k8sErrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: s.Namespace, Name: s.Name}, Data: map[string]string{}}
+			fn(cm.Data)
+			return s.Client.Create(ctx, cm)
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		fn(cm.Data)
+		return s.Client.Update(ctx, cm)
+	})
+}
+
+var invalidKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// leaseKey returns the ConfigMap data key of the backend URL.
+func leaseKey(url string) string {
+	for _, scheme := range []string{"https://", "http://"} {
+		url = strings.TrimPrefix(url, scheme)
+	}
+	return invalidKeyChars.ReplaceAllString(url, "_")
+}
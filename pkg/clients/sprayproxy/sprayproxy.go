@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
 	routev1 "github.com/openshift/api/route/v1"
@@ -25,68 +26,113 @@ func NewSprayProxyConfig(url string, token string) (*SprayProxyConfig, error) {
 					InsecureSkipVerify: true,
 				},
 			},
+			Timeout: 30 * time.Second,
 		},
 		Token: token,
 	}, nil
 }
 
-func (s *SprayProxyConfig) RegisterServer(pacHost string) (string, error) {
-	bytesData, err := buildBodyData(pacHost)
+func (s *SprayProxyConfig) RegisterServer(pacHost string) (*Response, error) {
+	return s.registerBackend(Backend{URL: pacHost})
+}
+
+func (s *SprayProxyConfig) UnregisterServer(pacHost string) (*Response, error) {
+	bytesData, err := json.Marshal(Backend{URL: pacHost})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	result, err := s.sendRequest(http.MethodPost, bytes.NewReader(bytesData))
+	return s.sendRequest(http.MethodDelete, "/backends", bytes.NewReader(bytesData))
+}
+
+// ListBackends returns the backends registered in SprayProxy.
+func (s *SprayProxyConfig) ListBackends() ([]Backend, error) {
+	res, err := s.sendRequest(http.MethodGet, "/backends", nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return result, nil
+	return parseBackends(res.Message)
 }
 
-func (s *SprayProxyConfig) UnregisterServer(pacHost string) (string, error) {
-	bytesData, err := buildBodyData(pacHost)
+// GetServers returns the comma separated URLs of the backends registered in SprayProxy.
+func (s *SprayProxyConfig) GetServers() (string, error) {
+	backends, err := s.ListBackends()
 	if err != nil {
 		return "", err
 	}
+	urls := make([]string, 0, len(backends))
+	for _, b := range backends {
+		urls = append(urls, b.URL)
+	}
+	return strings.Join(urls, ","), nil
+}
 
-	result, err := s.sendRequest(http.MethodDelete, bytes.NewReader(bytesData))
+// HealthCheck returns an error if the SprayProxy server is not healthy.
+func (s *SprayProxyConfig) HealthCheck() error {
+	_, err := s.sendRequest(http.MethodGet, "/healthz", nil)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("SprayProxy server %s is not healthy: %+v", s.BaseURL, err)
 	}
-	return result, nil
+	return nil
 }
 
-func (s *SprayProxyConfig) GetServers() (string, error) {
-	result, err := s.sendRequest(http.MethodGet, nil)
+func (s *SprayProxyConfig) registerBackend(backend Backend) (*Response, error) {
+	bytesData, err := json.Marshal(backend)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return strings.TrimPrefix(result, "Backend urls:"), nil
+
+	return s.sendRequest(http.MethodPost, "/backends", bytes.NewReader(bytesData))
 }
 
-func (s *SprayProxyConfig) sendRequest(httpMethod string, data io.Reader) (string, error) {
-	requestURL := s.BaseURL + "/backends"
+func (s *SprayProxyConfig) sendRequest(httpMethod, path string, data io.Reader) (*Response, error) {
+	requestURL := s.BaseURL + path
 
 	req, err := http.NewRequest(httpMethod, requestURL, data)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.Token))
 
 	res, err := s.HTTPClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to access SprayProxy server with status code: %d and body: %s", res.StatusCode, string(body))
+		return nil, fmt.Errorf("failed to read response of SprayProxy server with status code: %d: %+v", res.StatusCode, err)
 	}
+	response := &Response{StatusCode: res.StatusCode, Message: strings.TrimSpace(string(body))}
+	if res.StatusCode > 299 {
+		return response, fmt.Errorf("SprayProxy server responded to %s %s with status code: %d and body: %s", httpMethod, path, res.StatusCode, response.Message)
+	}
+	return response, nil
+}
 
-	defer res.Body.Close()
+// parseBackends parses the "Backend urls: a,b" response of SprayProxy listing the registered backends.
+func parseBackends(body string) ([]Backend, error) {
+	var backends []Backend
+	for _, url := range strings.Split(strings.TrimPrefix(body, "Backend urls:"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			backends = append(backends, Backend{URL: url})
+		}
+	}
+	return backends, nil
+}
 
-	return string(body), err
+// IsBackendReachable returns true if the backend URL responds to HTTP requests.
+func IsBackendReachable(url string) bool {
+	// #nosec G402
+	httpClient := http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, Timeout: 30 * time.Second}
+	res, err := httpClient.Get(strings.TrimSpace(url))
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+	return true
 }
 
 func GetPaCHost() (string, error) {
@@ -107,13 +153,3 @@ func GetPaCHost() (string, error) {
 	}
 	return fmt.Sprintf("https://%s", route.Spec.Host), nil
 }
-
-func buildBodyData(pacHost string) ([]byte, error) {
-	data := make(map[string]string)
-	data["url"] = pacHost
-	bytesData, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-	return bytesData, nil
-}
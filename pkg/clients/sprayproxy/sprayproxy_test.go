@@ -0,0 +1,126 @@
+package sprayproxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeSprayProxyConfig(t *testing.T) (*SprayProxyConfig, *FakeSprayProxyServer) {
+	server := NewFakeSprayProxyServer("token")
+	t.Cleanup(server.Close)
+	config, err := NewSprayProxyConfig(server.URL(), "token")
+	assert.NoError(t, err)
+	config.Leases = NewConfigMapLeaseStore(fake.NewClientBuilder().Build(), "sprayproxy")
+	return config, server
+}
+
+func TestRegisterAndUnregisterServer(t *testing.T) {
+	config, server := newFakeSprayProxyConfig(t)
+
+	assert.NoError(t, config.HealthCheck())
+
+	res, err := config.RegisterServer("https://pac-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "registered backend https://pac-a", res.Message)
+
+	servers, err := config.GetServers()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://pac-a", servers)
+
+	_, err = config.UnregisterServer("https://pac-a")
+	assert.NoError(t, err)
+	assert.Empty(t, server.Backends())
+
+	res, err = config.UnregisterServer("https://pac-a")
+	assert.ErrorContains(t, err, "status code: 404")
+	assert.Equal(t, 404, res.StatusCode)
+
+	config.Token = "wrong"
+	_, err = config.ListBackends()
+	assert.ErrorContains(t, err, "status code: 401")
+}
+
+func TestParseLegacyBackends(t *testing.T) {
+	backends, err := parseBackends("Backend urls:https://pac-a, https://pac-b")
+	assert.NoError(t, err)
+	assert.Equal(t, []Backend{{URL: "https://pac-a"}, {URL: "https://pac-b"}}, backends)
+
+	backends, err = parseBackends("Backend urls:")
+	assert.NoError(t, err)
+	assert.Empty(t, backends)
+}
+
+func TestLease(t *testing.T) {
+	config, server := newFakeSprayProxyConfig(t)
+
+	lease, err := config.AcquireLease("https://pac-a", "e2e-tests", "job-1", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []Backend{{URL: "https://pac-a"}}, server.Backends())
+	records, err := config.Leases.List()
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "job-1", records[0].JobID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), records[0].ExpiresAt, time.Minute)
+
+	// a failed release can be retried, e.g. by the interrupt handler and then by ReleaseLeases
+	lease.ReleaseOnInterrupt()
+	config.Token = "wrong"
+	assert.ErrorContains(t, lease.Release(), "status code: 401")
+	assert.ErrorContains(t, lease.Release(), "status code: 401")
+	assert.Len(t, server.Backends(), 1)
+
+	config.Token = "token"
+	assert.NoError(t, ReleaseLeases())
+	assert.NoError(t, lease.Release())
+	assert.NoError(t, ReleaseLeases())
+	assert.Empty(t, server.Backends())
+	records, err = config.Leases.List()
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestReconcile(t *testing.T) {
+	config, server := newFakeSprayProxyConfig(t)
+
+	_, err := config.RegisterServer("https://unreachable")
+	assert.NoError(t, err)
+	_, err = config.RegisterServer("https://reachable")
+	assert.NoError(t, err)
+
+	removed, err := config.Reconcile(func(url string) bool { return url != "https://unreachable" })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://unreachable"}, removed)
+
+	servers, err := config.GetServers()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://reachable", servers)
+	assert.Len(t, server.Backends(), 1)
+}
+
+func TestReconcileExpiredLeases(t *testing.T) {
+	config, server := newFakeSprayProxyConfig(t)
+
+	_, err := config.AcquireLease("https://pac-valid", "e2e-tests", "job-1", time.Hour)
+	assert.NoError(t, err)
+	// lease of a job which crashed without releasing it
+	_, err = config.RegisterServer("https://pac-expired")
+	assert.NoError(t, err)
+	assert.NoError(t, config.Leases.Save(LeaseRecord{URL: "https://pac-expired", Owner: "e2e-tests", JobID: "job-2", ExpiresAt: time.Now().Add(-time.Minute)}))
+	// expired lease of a backend which was already unregistered
+	assert.NoError(t, config.Leases.Save(LeaseRecord{URL: "https://pac-gone", Owner: "e2e-tests", JobID: "job-3", ExpiresAt: time.Now().Add(-time.Hour)}))
+
+	removed, err := config.Reconcile(func(string) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://pac-expired"}, removed)
+	assert.Equal(t, []Backend{{URL: "https://pac-valid"}}, server.Backends())
+
+	records, err := config.Leases.List()
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "https://pac-valid", records[0].URL)
+	assert.NoError(t, ReleaseLeases())
+}
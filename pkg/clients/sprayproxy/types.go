@@ -1,6 +1,8 @@
 package sprayproxy
 
-import "net/http"
+import (
+	"net/http"
+)
 
 const (
 	sprayProxyNamespace = "sprayproxy"
@@ -13,4 +15,17 @@ type SprayProxyConfig struct {
 	BaseURL    string
 	Token      string
 	HTTPClient *http.Client
+	// Leases stores the leases acquired by AcquireLease, leases are not recorded if nil
+	Leases LeaseStore
+}
+
+// Backend is a PaC server registered in SprayProxy.
+type Backend struct {
+	URL string `json:"url"`
+}
+
+// Response is the response of SprayProxy to a register or unregister request.
+type Response struct {
+	StatusCode int
+	Message    string
 }
@@ -6,6 +6,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/wait"
 
+	"github.com/konflux-ci/e2e-tests/pkg/utils/cosign"
 	"github.com/konflux-ci/e2e-tests/pkg/utils/tekton"
 	g "github.com/onsi/ginkgo/v2"
 )
@@ -21,3 +22,17 @@ func (t *TektonController) AwaitAttestationAndSignature(image string, timeout ti
 		return true, nil
 	})
 }
+
+// VerifyCosignResults downloads the signatures and attestations of the image, which has to be referenced by digest,
+// and verifies them with the Tekton Chains public key.
+func (t *TektonController) VerifyCosignResults(image string) (*cosign.VerificationResult, error) {
+	publicKey, err := t.GetTektonChainsPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := cosign.NewVerifier(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return verifier.Verify(context.Background(), image)
+}
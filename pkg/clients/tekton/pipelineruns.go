@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/logs"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 
@@ -36,7 +38,9 @@ func (t *TektonController) createAndWait(pr *pipeline.PipelineRun, namespace str
 		return nil, err
 	}
 	g.GinkgoWriter.Printf("Creating Pipeline %q\n", pipelineRun.Name)
-	return pipelineRun, utils.WaitUntil(t.CheckPipelineRunStarted(pipelineRun.Name, namespace), time.Duration(taskTimeout)*time.Second)
+	timeout := time.Duration(taskTimeout) * time.Second
+	err = utils.WaitUntil(t.CheckPipelineRunStarted(pipelineRun.Name, namespace), timeout)
+	return pipelineRun, clienterrors.WrapTimeout(err, fmt.Sprintf("PipelineRun %s/%s to start", namespace, pipelineRun.Name), timeout, "", nil)
 }
 
 // RunPipeline creates a pipelineRun and waits for it to start.
@@ -112,13 +116,18 @@ func (t *TektonController) GetPipelineRunWatch(ctx context.Context, namespace st
 // WatchPipelineRun waits until pipelineRun finishes.
 func (t *TektonController) WatchPipelineRun(pipelineRunName, namespace string, taskTimeout int) error {
 	g.GinkgoWriter.Printf("Waiting for pipeline %q to finish\n", pipelineRunName)
-	return utils.WaitUntil(t.CheckPipelineRunFinished(pipelineRunName, namespace), time.Duration(taskTimeout)*time.Second)
+	timeout := time.Duration(taskTimeout) * time.Second
+	err := utils.WaitUntil(t.CheckPipelineRunFinished(pipelineRunName, namespace), timeout)
+	return clienterrors.WrapTimeout(err, fmt.Sprintf("PipelineRun %s/%s to finish", namespace, pipelineRunName), timeout, "", nil)
 }
 
 // WatchPipelineRunSucceeded waits until the pipelineRun succeeds.
+// A clienterrors.PipelineFailedError is returned as soon as the pipelineRun fails.
 func (t *TektonController) WatchPipelineRunSucceeded(pipelineRunName, namespace string, taskTimeout int) error {
 	g.GinkgoWriter.Printf("Waiting for pipeline %q to finish\n", pipelineRunName)
-	return utils.WaitUntil(t.CheckPipelineRunSucceeded(pipelineRunName, namespace), time.Duration(taskTimeout)*time.Second)
+	timeout := time.Duration(taskTimeout) * time.Second
+	err := utils.WaitUntil(t.CheckPipelineRunSucceeded(pipelineRunName, namespace), timeout)
+	return clienterrors.WrapTimeout(err, fmt.Sprintf("PipelineRun %s/%s to succeed", namespace, pipelineRunName), timeout, "", nil)
 }
 
 // CheckPipelineRunStarted checks if pipelineRUn started.
@@ -149,13 +158,17 @@ func (t *TektonController) CheckPipelineRunFinished(pipelineRunName, namespace s
 	}
 }
 
-// CheckPipelineRunSucceeded checks if pipelineRun succeeded. Returns error if getting pipelineRun fails.
+// CheckPipelineRunSucceeded checks if pipelineRun succeeded. Returns error if getting pipelineRun fails
+// or a clienterrors.PipelineFailedError if the pipelineRun failed.
 func (t *TektonController) CheckPipelineRunSucceeded(pipelineRunName, namespace string) wait.ConditionFunc {
 	return func() (bool, error) {
 		pr, err := t.GetPipelineRun(pipelineRunName, namespace)
 		if err != nil {
 			return false, err
 		}
+		if tekton.HasPipelineRunFailed(pr) {
+			return false, tekton.NewPipelineFailedError(t.KubeRest(), t.KubeInterface(), pr, true)
+		}
 		if len(pr.Status.Conditions) > 0 {
 			for _, c := range pr.Status.Conditions {
 				if c.Type == "Succeeded" && c.Status == "True" {
@@ -169,7 +182,8 @@ func (t *TektonController) CheckPipelineRunSucceeded(pipelineRunName, namespace
 
 // ListAllPipelineRuns returns a list of all pipelineRuns in a namespace.
 func (t *TektonController) ListAllPipelineRuns(ns string) (*pipeline.PipelineRunList, error) {
-	return t.PipelineClient().TektonV1().PipelineRuns(ns).List(context.Background(), metav1.ListOptions{})
+	pipelineRuns, err := common.ListResources[pipeline.PipelineRun](t.KubeRest(), ns, nil)
+	return &pipeline.PipelineRunList{Items: pipelineRuns}, err
 }
 
 // DeletePipelineRun deletes a pipelineRun form a given namespace.
@@ -204,7 +218,7 @@ func (t *TektonController) DeletePipelineRunIgnoreFinalizers(ns, name string) er
 		return true, nil
 	})
 	if err != nil {
-		return fmt.Errorf("deletion of PipelineRun '%s' in '%s' timed out", name, ns)
+		return clienterrors.Timeout(fmt.Sprintf("deletion of PipelineRun '%s' in '%s'", name, ns), 30*time.Second, "", err)
 	}
 
 	return nil
@@ -212,20 +226,7 @@ func (t *TektonController) DeletePipelineRunIgnoreFinalizers(ns, name string) er
 
 // DeleteAllPipelineRunsInASpecificNamespace deletes all PipelineRuns in a given namespace (removing the finalizers field, first)
 func (t *TektonController) DeleteAllPipelineRunsInASpecificNamespace(ns string) error {
-
-	pipelineRunList, err := t.ListAllPipelineRuns(ns)
-	if err != nil || pipelineRunList == nil {
-		return fmt.Errorf("unable to delete all PipelineRuns in '%s': %v", ns, err)
-	}
-
-	for _, pipelineRun := range pipelineRunList.Items {
-		err := t.DeletePipelineRunIgnoreFinalizers(ns, pipelineRun.Name)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return common.DeleteAllResources[pipeline.PipelineRun](t.KubeRest(), ns, common.DeleteAllOptions{RemoveFinalizers: true})
 }
 
 // StorePipelineRun stores a given PipelineRun as an artifact.
@@ -252,19 +253,9 @@ func (t *TektonController) StorePipelineRun(prefix string, pipelineRun *pipeline
 
 // StoreAllPipelineRuns stores all PipelineRuns in a given namespace.
 func (t *TektonController) StoreAllPipelineRuns(namespace string) error {
-	pipelineRuns, err := t.ListAllPipelineRuns(namespace)
-	if err != nil {
-		return fmt.Errorf("got error fetching PR list: %v\n", err.Error())
-	}
-
-	for _, pipelineRun := range pipelineRuns.Items {
-		pipelineRun := pipelineRun
-		if err := t.StorePipelineRun(pipelineRun.GetName(), &pipelineRun); err != nil {
-			return fmt.Errorf("got error storing PR: %v\n", err.Error())
-		}
-	}
-
-	return nil
+	return common.StoreAllResources[pipeline.PipelineRun](t.KubeRest(), namespace, func(pipelineRun *pipeline.PipelineRun) error {
+		return t.StorePipelineRun(pipelineRun.GetName(), pipelineRun)
+	})
 }
 
 func (t *TektonController) AddFinalizerToPipelineRun(pipelineRun *pipeline.PipelineRun, finalizerName string) error {
@@ -6,13 +6,13 @@ import (
 	"strings"
 	"time"
 
-	"github.com/konflux-ci/e2e-tests/pkg/logs"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	g "github.com/onsi/ginkgo/v2"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"sigs.k8s.io/yaml"
 	pointer "k8s.io/utils/ptr"
 
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -95,19 +95,7 @@ func (t *TektonController) GetTaskRun(name, namespace string) (*pipeline.TaskRun
 
 // StoreTaskRun stores a given TaskRun as an artifact.
 func (t *TektonController) StoreTaskRun(prefix string, taskRun *pipeline.TaskRun) error {
-	artifacts := make(map[string][]byte)
-
-	taskRunYaml, err := yaml.Marshal(taskRun)
-	if err != nil {
-		g.GinkgoWriter.Printf("failed to store taskRun %s:%s: %s\n", taskRun.GetNamespace(), taskRun.GetName(), err.Error())
-	}
-	artifacts["taskRun-"+taskRun.Name+".yaml"] = taskRunYaml
-
-	if err := logs.StoreArtifacts(artifacts); err != nil {
-		return err
-	}
-
-	return nil
+	return common.StoreResource(t.KubeRest(), taskRun)
 }
 
 func (t *TektonController) StoreTaskRunsForPipelineRun(c crclient.Client, pr *pipeline.PipelineRun) error {
@@ -145,7 +133,7 @@ func (t *TektonController) GetTaskRunLogs(pipelineRunName, pipelineTaskName, nam
 		}
 	}
 	if podName == "" {
-		return nil, fmt.Errorf("task with %s name doesn't exist in %s pipelinerun", pipelineTaskName, pipelineRunName)
+		return nil, clienterrors.NotFound("TaskRun", namespace, pipelineTaskName, "task with %s name doesn't exist in %s pipelinerun", pipelineTaskName, pipelineRunName)
 	}
 
 	podClient := t.KubeInterface().CoreV1().Pods(namespace)
@@ -180,7 +168,7 @@ func (t *TektonController) GetTaskRunFromPipelineRun(c crclient.Client, pr *pipe
 		return taskRun, nil
 	}
 
-	return nil, fmt.Errorf("task %q not found in PipelineRun %q/%q", pipelineTaskName, pr.Namespace, pr.Name)
+	return nil, clienterrors.NotFound("TaskRun", pr.Namespace, pipelineTaskName, "task %q not found in PipelineRun %q/%q", pipelineTaskName, pr.Namespace, pr.Name)
 }
 
 func (t *TektonController) GetTaskRunResult(c crclient.Client, pr *pipeline.PipelineRun, pipelineTaskName string, result string) (string, error) {
@@ -217,7 +205,7 @@ func (t *TektonController) GetTaskRunStatus(c crclient.Client, pr *pipeline.Pipe
 
 // DeleteAllTaskRunsInASpecificNamespace removes all TaskRuns from a given repository. Useful when creating a lot of resources and wanting to remove all of them.
 func (t *TektonController) DeleteAllTaskRunsInASpecificNamespace(namespace string) error {
-	return t.KubeRest().DeleteAllOf(context.Background(), &pipeline.TaskRun{}, crclient.InNamespace(namespace))
+	return common.DeleteAllResources[pipeline.TaskRun](t.KubeRest(), namespace, common.DeleteAllOptions{})
 }
 
 // GetTaskRunParam gets value of a TaskRun param.
@@ -260,7 +248,9 @@ func (t *TektonController) GetEnvVariable(tr *pipeline.TaskRun, envVar string) (
 
 func (t *TektonController) WatchTaskRun(taskRunName, namespace string, taskTimeout int) error {
 	g.GinkgoWriter.Printf("Waiting for pipeline %q to finish\n", taskRunName)
-	return utils.WaitUntil(t.CheckTaskRunFinished(taskRunName, namespace), time.Duration(taskTimeout)*time.Second)
+	timeout := time.Duration(taskTimeout) * time.Second
+	err := utils.WaitUntil(t.CheckTaskRunFinished(taskRunName, namespace), timeout)
+	return clienterrors.WrapTimeout(err, fmt.Sprintf("TaskRun %s/%s to finish", namespace, taskRunName), timeout, "", nil)
 }
 
 // CheckTaskRunFinished checks if taskRun finished.
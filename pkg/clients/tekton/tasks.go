@@ -4,10 +4,10 @@ import (
 	"context"
 	"os/exec"
 
+	"github.com/konflux-ci/e2e-tests/pkg/clients/common"
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Create a tekton task and return the task or error.
@@ -50,5 +50,5 @@ func (t *TektonController) GetTask(name, namespace string) (*pipeline.Task, erro
 
 // DeleteAllTasksInASpecificNamespace removes all Tasks from a given repository. Useful when creating a lot of resources and wanting to remove all of them.
 func (t *TektonController) DeleteAllTasksInASpecificNamespace(namespace string) error {
-	return t.KubeRest().DeleteAllOf(context.Background(), &pipeline.Task{}, crclient.InNamespace(namespace))
+	return common.DeleteAllResources[pipeline.Task](t.KubeRest(), namespace, common.DeleteAllOptions{})
 }
@@ -120,6 +120,37 @@ const (
 	// QE slack bot token used for delivering messages about critical failures during CI runs
 	SLACK_BOT_TOKEN_ENV = "SLACK_BOT_TOKEN"
 
+	// ID of the Slack channel the "slack" notifier backend posts alerts to. Defaults to SlackCIReportsChannelID
+	NOTIFIER_SLACK_CHANNEL_ID_ENV = "NOTIFIER_SLACK_CHANNEL_ID"
+
+	// Backend used for CI alerts: "slack", "webhook", "file" or "stdout". Defaults to "slack" when SLACK_BOT_TOKEN is set, "stdout" otherwise
+	NOTIFIER_BACKEND_ENV = "NOTIFIER_BACKEND"
+
+	// URL the "webhook" notifier backend posts alerts to
+	NOTIFIER_WEBHOOK_URL_ENV = "NOTIFIER_WEBHOOK_URL"
+
+	// Path of the file the "file" notifier backend appends alerts to
+	NOTIFIER_FILE_PATH_ENV = "NOTIFIER_FILE_PATH"
+
+	// Base URL of the Konflux UI, used for linking alerts to the Konflux PipelineRun running the tests
+	KONFLUX_UI_URL_ENV = "KONFLUX_UI_URL"
+
+	// Namespace of the Konflux PipelineRun running the tests
+	KONFLUX_PIPELINERUN_NAMESPACE_ENV = "KONFLUX_PIPELINERUN_NAMESPACE"
+
+	// Comma separated list of container registry hosts reached over plain HTTP, e.g. a local registry:2 or Zot instance. localhost and 127.0.0.1 are always reached over plain HTTP
+	INSECURE_REGISTRIES_ENV = "INSECURE_REGISTRIES"
+
+	// Comma separated list of <cluster>=<kubeconfig context> pairs for deployments spread over several clusters, e.g. "host=host-admin,member=member-admin,managed=managed-admin".
+	// Clusters not listed use the current context of the default kubeconfig
+	CLUSTER_CONTEXTS_ENV = "CLUSTER_CONTEXTS"
+
+	// Comma separated list of <cluster>=<kubeconfig path> pairs for clusters whose context is not in the default kubeconfig
+	CLUSTER_KUBECONFIGS_ENV = "CLUSTER_KUBECONFIGS"
+
+	// Token used for the Quay API calls, e.g. listing tags or checking the repository visibility
+	DEFAULT_QUAY_ORG_TOKEN_ENV = "DEFAULT_QUAY_ORG_TOKEN" // #nosec
+
 	// This variable is set by an automation in case Spray Proxy configuration fails in CI
 	SKIP_PAC_TESTS_ENV = "SKIP_PAC_TESTS"
 
@@ -248,13 +279,34 @@ const (
 	FbcBuilder                    BuildPipelineType = "fbc-builder"
 )
 
+// Component annotations understood by build-service, image-controller and Mintmaker
+const (
+	// BuildRequestAnnotation requests an action from build-service, e.g. configuring PaC
+	BuildRequestAnnotation = "build.appstudio.openshift.io/request"
+	// BuildPipelineAnnotation selects the build pipeline and its bundle
+	BuildPipelineAnnotation = "build.appstudio.openshift.io/pipeline"
+	// BuildStatusAnnotation is set by build-service with the PaC state and the merge request URL
+	BuildStatusAnnotation = "build.appstudio.openshift.io/status"
+	// BuildNudgeFilesAnnotation contains the comma separated file patterns updated by nudges
+	BuildNudgeFilesAnnotation = "build.appstudio.openshift.io/build-nudge-files"
+	// ImageRepositoryGenerateAnnotation requests image-controller to create the image repository
+	ImageRepositoryGenerateAnnotation = "image.redhat.com/generate"
+	// MintmakerDisabledAnnotation stops Mintmaker from creating dependency update PRs
+	MintmakerDisabledAnnotation = "mintmaker.appstudio.redhat.com/disabled"
+	// SkipInitialChecksAnnotation skips the initial checks of the Component
+	SkipInitialChecksAnnotation = "skip-initial-checks"
+
+	BuildRequestConfigurePaC       = "configure-pac"
+	BuildRequestTriggerSimpleBuild = "trigger-simple-build"
+)
+
 var (
-	ComponentPaCRequestAnnotation               = map[string]string{"build.appstudio.openshift.io/request": "configure-pac"}
-	ComponentTriggerSimpleBuildAnnotation       = map[string]string{"build.appstudio.openshift.io/request": "trigger-simple-build"}
-	ImageControllerAnnotationRequestPublicRepo  = map[string]string{"image.redhat.com/generate": `{"visibility": "public"}`}
-	ImageControllerAnnotationRequestPrivateRepo = map[string]string{"image.redhat.com/generate": `{"visibility": "private"}`}
+	ComponentPaCRequestAnnotation               = map[string]string{BuildRequestAnnotation: BuildRequestConfigurePaC}
+	ComponentTriggerSimpleBuildAnnotation       = map[string]string{BuildRequestAnnotation: BuildRequestTriggerSimpleBuild}
+	ImageControllerAnnotationRequestPublicRepo  = map[string]string{ImageRepositoryGenerateAnnotation: `{"visibility": "public"}`}
+	ImageControllerAnnotationRequestPrivateRepo = map[string]string{ImageRepositoryGenerateAnnotation: `{"visibility": "private"}`}
 	IntegrationTestScenarioDefaultLabels        = map[string]string{"test.appstudio.openshift.io/optional": "false"}
-	DefaultDockerBuildPipelineBundleAnnotation  = map[string]string{"build.appstudio.openshift.io/pipeline": `{"name": "docker-build", "bundle": "latest"}`}
-	DefaultFbcBuilderPipelineBundle             = map[string]string{"build.appstudio.openshift.io/pipeline": `{"name": "fbc-builder", "bundle": "latest"}`}
-	ComponentMintmakerDisabledAnnotation        = map[string]string{"mintmaker.appstudio.redhat.com/disabled": "true"}
+	DefaultDockerBuildPipelineBundleAnnotation  = map[string]string{BuildPipelineAnnotation: `{"name": "docker-build", "bundle": "latest"}`}
+	DefaultFbcBuilderPipelineBundle             = map[string]string{BuildPipelineAnnotation: `{"name": "fbc-builder", "bundle": "latest"}`}
+	ComponentMintmakerDisabledAnnotation        = map[string]string{MintmakerDisabledAnnotation: "true"}
 )
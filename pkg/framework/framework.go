@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -38,6 +39,7 @@ type ControllerHub struct {
 type Framework struct {
 	AsKubeAdmin          *ControllerHub
 	AsKubeDeveloper      *ControllerHub
+	Clusters             *kubeCl.Clusters
 	ClusterAppDomain     string
 	OpenshiftConsoleHost string
 	ProxyUrl             string
@@ -47,6 +49,11 @@ type Framework struct {
 	UserToken            string
 }
 
+var (
+	clusterHubsMu sync.Mutex
+	clusterHubs   = map[*kubeCl.CustomClient]*ControllerHub{}
+)
+
 func NewFramework(userName string, stageConfig ...utils.Options) (*Framework, error) {
 	return NewFrameworkWithTimeout(userName, time.Second*60, stageConfig...)
 }
@@ -87,12 +94,17 @@ func newFrameworkWithTimeout(userName string, timeout time.Duration, options ...
 		GinkgoWriter.Printf("WARNING: username %q is longer than 20 characters - the tenant namespace prefix will be shortened to %s\n", userName, userName[:20])
 	}
 
+	clusters, err := kubeCl.NewClustersFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	// in some very rare cases fail to get the client for some timeout in member operator.
 	// Just try several times to get the user kubeconfig
 
 	err = retry.Do(
 		func() error {
-			if k, err = kubeCl.NewDevSandboxProxyClient(userName, isStage, isSA, option); err != nil {
+			if k, err = kubeCl.NewDevSandboxProxyClient(userName, isStage, isSA, option, clusters); err != nil {
 				GinkgoWriter.Printf("error when creating dev sandbox proxy client: %+v\n", err)
 			}
 			return err
@@ -152,6 +164,7 @@ func newFrameworkWithTimeout(userName string, timeout time.Duration, options ...
 	return &Framework{
 		AsKubeAdmin:          asAdmin,
 		AsKubeDeveloper:      asUser,
+		Clusters:             k.Clusters,
 		ClusterAppDomain:     clusterAppDomain,
 		OpenshiftConsoleHost: openshiftConsoleHost,
 		ProxyUrl:             k.ProxyUrl,
@@ -231,3 +244,27 @@ func InitControllerHub(cc *kubeCl.CustomClient) (*ControllerHub, error) {
 		ImageController:       imageController,
 	}, nil
 }
+
+// ClusterHub returns the controllers connected as admin to the named cluster, e.g. kubeCl.ManagedCluster, so tests can verify
+// objects in the cluster they live in. Clusters not configured by CLUSTER_CONTEXTS and CLUSTER_KUBECONFIGS env vars are the cluster of AsKubeAdmin.
+func (f *Framework) ClusterHub(name string) (*ControllerHub, error) {
+	if f.Clusters == nil || !f.Clusters.IsConfigured(name) {
+		return f.AsKubeAdmin, nil
+	}
+	cc, err := f.Clusters.Client(name)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterHubsMu.Lock()
+	defer clusterHubsMu.Unlock()
+	if hub, ok := clusterHubs[cc]; ok {
+		return hub, nil
+	}
+	hub, err := InitControllerHub(cc)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing appstudio hub controllers for %s cluster: %v", name, err)
+	}
+	clusterHubs[cc] = hub
+	return hub, nil
+}
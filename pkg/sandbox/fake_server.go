@@ -0,0 +1,296 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var nonCompliantUsernameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// FakeSandboxServer is an in-process stand-in for Keycloak and the toolchain registration service serving the
+// OIDC token, Keycloak admin users and registration service signup endpoints used by the SandboxController.
+// Point a SandboxController at it by setting KeycloakUrl to server.URL() and HttpClient to server.Client().
+type FakeSandboxServer struct {
+	server *httptest.Server
+	// realm -> username -> user
+	users map[string]map[string]*KeycloakUser
+	// token -> owner
+	accessTokens  map[string]tokenOwner
+	refreshTokens map[string]tokenOwner
+	signups       map[string]*Signup
+	// signups wait for ApproveSignup instead of being provisioned right away
+	requireApproval bool
+	tokenCounter    int
+	requests        []string
+	mu              sync.Mutex
+}
+
+type tokenOwner struct {
+	realm    string
+	username string
+}
+
+// NewFakeSandboxServer starts a new FakeSandboxServer. Call Close when done.
+func NewFakeSandboxServer() *FakeSandboxServer {
+	f := &FakeSandboxServer{
+		users:         map[string]map[string]*KeycloakUser{},
+		accessTokens:  map[string]tokenOwner{},
+		refreshTokens: map[string]tokenOwner{},
+		signups:       map[string]*Signup{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/realms/", f.handleToken)
+	mux.HandleFunc("/auth/admin/realms/", f.handleUsers)
+	mux.HandleFunc(REGISTRATION_SERVICE_SIGNUP_PATH, f.handleSignup)
+	f.server = httptest.NewTLSServer(mux)
+	return f
+}
+
+// URL returns the base URL of the server, used both as Keycloak and registration service URL.
+func (f *FakeSandboxServer) URL() string {
+	return f.server.URL
+}
+
+// TokenURL returns the OIDC token endpoint of the realm, equivalent to the stage one passed to GetKeycloakTokenStage.
+func (f *FakeSandboxServer) TokenURL(realm string) string {
+	return fmt.Sprintf("%s/auth/realms/%s/protocol/openid-connect/token", f.server.URL, realm)
+}
+
+// Client returns an http client which trusts the server certificate.
+func (f *FakeSandboxServer) Client() *http.Client {
+	return f.server.Client()
+}
+
+// Close shuts the server down.
+func (f *FakeSandboxServer) Close() {
+	f.server.Close()
+}
+
+// AddUser registers a user with the given password in the realm.
+func (f *FakeSandboxServer) AddUser(realm, username, password string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.addUser(realm, &KeycloakUser{
+		Username:    username,
+		Enabled:     "true",
+		Credentials: []KeycloakUserCredentials{{Type: "password", Value: password, Temporary: "false"}},
+	})
+}
+
+// NewOfflineToken returns an offline token of the user of the realm, like the ones used to log in to stage.
+func (f *FakeSandboxServer) NewOfflineToken(realm, username string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	token := f.newToken("offline", username)
+	f.refreshTokens[token] = tokenOwner{realm: realm, username: username}
+	return token
+}
+
+// ExpireAccessTokens invalidates all the access tokens issued so far, the refresh and offline tokens remain valid.
+func (f *FakeSandboxServer) ExpireAccessTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.accessTokens = map[string]tokenOwner{}
+}
+
+// RequireApproval makes new signups pending until they are approved with ApproveSignup.
+func (f *FakeSandboxServer) RequireApproval() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requireApproval = true
+}
+
+// ApproveSignup provisions the signup of the user.
+func (f *FakeSandboxServer) ApproveSignup(username string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	signup, ok := f.signups[username]
+	if !ok {
+		return fmt.Errorf("user %s didn't sign up", username)
+	}
+	f.provision(signup)
+	return nil
+}
+
+// KeycloakUser returns the user of the realm, nil if it doesn't exist.
+func (f *FakeSandboxServer) KeycloakUser(realm, username string) *KeycloakUser {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.users[realm][username]
+}
+
+// Requests returns the methods and paths of all the requests received by the server, e.g. "POST /api/v1/signup".
+func (f *FakeSandboxServer) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.requests...)
+}
+
+// handleToken serves the password and refresh token grants of /auth/realms/<realm>/protocol/openid-connect/token.
+func (f *FakeSandboxServer) handleToken(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req.Method+" "+req.URL.Path)
+
+	realm, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/auth/realms/"), "/protocol/openid-connect/token")
+	if !ok || req.Method != http.MethodPost {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Unable to find matching target resource method"})
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	var owner tokenOwner
+	switch req.PostForm.Get("grant_type") {
+	case "password":
+		user := f.users[realm][req.PostForm.Get("username")]
+		if user == nil || len(user.Credentials) == 0 || user.Credentials[0].Value != req.PostForm.Get("password") {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_grant", "error_description": "Invalid user credentials"})
+			return
+		}
+		owner = tokenOwner{realm: realm, username: user.Username}
+	case "refresh_token":
+		owner, ok = f.refreshTokens[req.PostForm.Get("refresh_token")]
+		if !ok || owner.realm != realm {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "Invalid refresh token"})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	accessToken := f.newToken("access", owner.username)
+	refreshToken := f.newToken("refresh", owner.username)
+	f.accessTokens[accessToken] = owner
+	f.refreshTokens[refreshToken] = owner
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    300,
+	})
+}
+
+// handleUsers serves the search and creation of users of /auth/admin/realms/<realm>/users, authorized by a master realm token.
+func (f *FakeSandboxServer) handleUsers(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req.Method+" "+req.URL.Path)
+
+	realm, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/auth/admin/realms/"), "/users")
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "Unable to find matching target resource method"})
+		return
+	}
+	if owner, ok := f.authorize(req); !ok || owner.realm != DEFAULT_KEYCLOAK_MASTER_REALM {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "HTTP 401 Unauthorized"})
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		users := []*KeycloakUser{}
+		if user := f.users[realm][req.URL.Query().Get("username")]; user != nil {
+			users = append(users, user)
+		}
+		writeJSON(w, http.StatusOK, users)
+	case http.MethodPost:
+		user := &KeycloakUser{}
+		if err := json.NewDecoder(req.Body).Decode(user); err != nil || user.Username == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"errorMessage": "invalid user representation"})
+			return
+		}
+		if f.users[realm][user.Username] != nil {
+			writeJSON(w, http.StatusConflict, map[string]string{"errorMessage": "User exists with same username"})
+			return
+		}
+		f.addUser(realm, user)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "HTTP 405 Method Not Allowed"})
+	}
+}
+
+// handleSignup serves the signup creation and status of the user owning the bearer token.
+func (f *FakeSandboxServer) handleSignup(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req.Method+" "+req.URL.Path)
+
+	owner, ok := f.authorize(req)
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"status": "Unauthorized", "code": http.StatusUnauthorized, "message": "invalid bearer token"})
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		signup, ok := f.signups[owner.username]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"status": "Not Found", "code": http.StatusNotFound, "message": "UserSignup not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, signup)
+	case http.MethodPost:
+		if _, ok := f.signups[owner.username]; ok {
+			writeJSON(w, http.StatusConflict, map[string]interface{}{"status": "Conflict", "code": http.StatusConflict, "message": "UserSignup already exists"})
+			return
+		}
+		signup := &Signup{
+			Name:     owner.username,
+			Username: owner.username,
+			Status:   SignupStatus{Reason: "PendingApproval", Message: "waiting for approval"},
+		}
+		f.signups[owner.username] = signup
+		if !f.requireApproval {
+			f.provision(signup)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"status": "Method Not Allowed", "code": http.StatusMethodNotAllowed})
+	}
+}
+
+func (f *FakeSandboxServer) authorize(req *http.Request) (tokenOwner, bool) {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return tokenOwner{}, false
+	}
+	owner, ok := f.accessTokens[token]
+	return owner, ok
+}
+
+func (f *FakeSandboxServer) provision(signup *Signup) {
+	signup.CompliantUsername = strings.Trim(nonCompliantUsernameChars.ReplaceAllString(strings.ToLower(signup.Username), "-"), "-")
+	signup.APIEndpoint = f.server.URL
+	signup.ClusterName = "member"
+	signup.ProxyURL = f.server.URL
+	signup.DefaultUserNamespace = signup.CompliantUsername + "-tenant"
+	signup.Status = SignupStatus{Ready: true, Reason: "Provisioned"}
+}
+
+func (f *FakeSandboxServer) addUser(realm string, user *KeycloakUser) {
+	if f.users[realm] == nil {
+		f.users[realm] = map[string]*KeycloakUser{}
+	}
+	f.users[realm][user.Username] = user
+}
+
+func (f *FakeSandboxServer) newToken(kind, username string) string {
+	f.tokenCounter++
+	return fmt.Sprintf("%s-%s-%d", kind, username, f.tokenCounter)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,131 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/utils"
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// Path of the registration service signup endpoint
+const REGISTRATION_SERVICE_SIGNUP_PATH = "/api/v1/signup"
+
+// Signup is the user signup as returned by the registration service
+type Signup struct {
+	// Name of the UserSignup resource
+	Name string `json:"name"`
+
+	Username string `json:"username"`
+
+	// Username used for the provisioned Space and namespaces
+	CompliantUsername string `json:"compliantUsername"`
+
+	// Url of the member cluster api the user was provisioned to
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Url of the proxy used to access the user namespaces
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	DefaultUserNamespace string `json:"defaultUserNamespace,omitempty"`
+
+	Status SignupStatus `json:"status"`
+}
+
+// SignupStatus tells if the user was provisioned, e.g. reason "PendingApproval" while waiting for an approval
+type SignupStatus struct {
+	Ready                bool   `json:"ready"`
+	Reason               string `json:"reason"`
+	Message              string `json:"message,omitempty"`
+	VerificationRequired bool   `json:"verificationRequired"`
+}
+
+// CreateSignup signs up the user owning the token in the registration service. Signing up an already signed up user is a no-op.
+func (s *SandboxController) CreateSignup(registrationServiceUrl, userToken string) error {
+	resp, err := s.doRegistrationServiceRequest(http.MethodPost, registrationServiceUrl, userToken)
+	if err != nil {
+		return fmt.Errorf("failed to create signup: %+v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create signup. Status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// GetSignup returns the signup of the user owning the token, a clienterrors.NotFoundError if the user didn't sign up.
+func (s *SandboxController) GetSignup(registrationServiceUrl, userToken string) (*Signup, error) {
+	resp, err := s.doRegistrationServiceRequest(http.MethodGet, registrationServiceUrl, userToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signup: %+v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, clienterrors.NotFound("UserSignup", DEFAULT_TOOLCHAIN_NAMESPACE, "", "signup not found in registration service %s", registrationServiceUrl)
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get signup. Status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	signup := &Signup{}
+	if err := json.NewDecoder(resp.Body).Decode(signup); err != nil {
+		return nil, fmt.Errorf("failed to decode signup: %+v", err)
+	}
+	return signup, nil
+}
+
+// WaitForSignupReady waits until the user owning the token is provisioned and returns its signup.
+func (s *SandboxController) WaitForSignupReady(registrationServiceUrl, userToken string, timeout time.Duration) (*Signup, error) {
+	var signup *Signup
+	var lastState string
+	var lastErr error
+	err := utils.WaitUntil(func() (done bool, err error) {
+		signup, lastErr = s.GetSignup(registrationServiceUrl, userToken)
+		if lastErr != nil {
+			GinkgoWriter.Printf("failed to get signup: %+v\n", lastErr)
+			return false, nil
+		}
+		lastState = signup.Status.Reason
+		return signup.Status.Ready, nil
+	}, timeout)
+	if err != nil {
+		return nil, clienterrors.WrapTimeout(err, "signup to be ready", timeout, lastState, lastErr)
+	}
+	return signup, nil
+}
+
+// SignupUser signs up the user owning the token in the registration service, if not signed up yet, and waits until it is provisioned.
+func (s *SandboxController) SignupUser(registrationServiceUrl, userToken string, timeout time.Duration) (*Signup, error) {
+	if _, err := s.GetSignup(registrationServiceUrl, userToken); err != nil {
+		if !clienterrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := s.CreateSignup(registrationServiceUrl, userToken); err != nil {
+			return nil, err
+		}
+	}
+	return s.WaitForSignupReady(registrationServiceUrl, userToken, timeout)
+}
+
+func (s *SandboxController) doRegistrationServiceRequest(method, registrationServiceUrl, userToken string) (*http.Response, error) {
+	req, err := http.NewRequest(method, strings.TrimSuffix(registrationServiceUrl, "/")+REGISTRATION_SERVICE_SIGNUP_PATH, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", userToken))
+
+	return s.HttpClient.Do(req)
+}
@@ -0,0 +1,146 @@
+package sandbox
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+	"github.com/konflux-ci/e2e-tests/pkg/constants"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeSandboxController(t *testing.T) (*SandboxController, *FakeSandboxServer) {
+	server := NewFakeSandboxServer()
+	t.Cleanup(server.Close)
+	t.Setenv(constants.USER_KUBE_CONFIG_PATH_ENV, filepath.Join(t.TempDir(), "user.kubeconfig"))
+	return &SandboxController{HttpClient: server.Client(), KeycloakUrl: server.URL()}, server
+}
+
+func TestReconcileUserCreationStage(t *testing.T) {
+	s, server := newFakeSandboxController(t)
+	offlineToken := server.NewOfflineToken(DEFAULT_KEYCLOAK_TESTING_REALM, "e2e-user")
+
+	authInfo, err := s.ReconcileUserCreationStage("e2e-user", server.URL(), server.TokenURL(DEFAULT_KEYCLOAK_TESTING_REALM), offlineToken, false)
+	require.NoError(t, err)
+	assert.Equal(t, "e2e-user-tenant", authInfo.UserNamespace)
+	assert.True(t, strings.HasPrefix(authInfo.UserToken, "access-e2e-user-"))
+
+	kubeconfig, err := clientcmd.LoadFromFile(authInfo.KubeconfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, authInfo.UserToken, kubeconfig.AuthInfos["e2e-user/"+server.URL()].Token)
+
+	signup, err := s.SignupUser(server.URL(), authInfo.UserToken, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "e2e-user", signup.CompliantUsername)
+	assert.Equal(t, "e2e-user-tenant", signup.DefaultUserNamespace)
+
+	// the access token expires, a new one is got with the offline token
+	server.ExpireAccessTokens()
+	_, err = s.GetSignup(server.URL(), authInfo.UserToken)
+	assert.ErrorContains(t, err, "Status code 401")
+	userToken, err := s.GetKeycloakTokenStage("e2e-user", server.TokenURL(DEFAULT_KEYCLOAK_TESTING_REALM), offlineToken)
+	require.NoError(t, err)
+	signup, err = s.GetSignup(server.URL(), userToken)
+	require.NoError(t, err)
+	assert.True(t, signup.Status.Ready)
+
+	// SA flow uses the offline token as user token
+	authInfo, err = s.ReconcileUserCreationStage("e2e-sa", server.URL(), server.TokenURL(DEFAULT_KEYCLOAK_TESTING_REALM), "sa-token", true)
+	require.NoError(t, err)
+	assert.Equal(t, "sa-token", authInfo.UserToken)
+
+	_, err = s.GetKeycloakTokenStage("e2e-user", server.TokenURL(DEFAULT_KEYCLOAK_TESTING_REALM), "invalid")
+	assert.EqualError(t, err, "failed to get keycloak token, userName: e2e-user, statusCode: 400")
+}
+
+func TestSignupApproval(t *testing.T) {
+	s, server := newFakeSandboxController(t)
+	server.AddUser(DEFAULT_KEYCLOAK_TESTING_REALM, "Pending_User", "secret")
+	server.RequireApproval()
+
+	token, err := s.GetKeycloakToken(DEFAULT_KEYCLOAK_TEST_CLIENT_ID, "Pending_User", "secret", DEFAULT_KEYCLOAK_TESTING_REALM)
+	require.NoError(t, err)
+
+	_, err = s.GetSignup(server.URL(), token)
+	assert.True(t, clienterrors.IsNotFound(err))
+
+	_, err = s.SignupUser(server.URL(), token, time.Second)
+	assert.True(t, clienterrors.IsTimeout(err))
+	assert.ErrorContains(t, err, "last observed state: PendingApproval")
+
+	require.NoError(t, server.ApproveSignup("Pending_User"))
+	signup, err := s.SignupUser(server.URL(), token, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "pending-user", signup.CompliantUsername)
+	assert.Equal(t, []string{
+		"POST /auth/realms/redhat-external/protocol/openid-connect/token",
+		"GET /api/v1/signup",
+		"GET /api/v1/signup",
+		"POST /api/v1/signup",
+	}, server.Requests()[:4])
+}
+
+func TestReconcileUserCreation(t *testing.T) {
+	s, server := newFakeSandboxController(t)
+	server.AddUser(DEFAULT_KEYCLOAK_MASTER_REALM, DEFAULT_KEYCLOAK_ADMIN_USERNAME, "admin-password")
+	host := strings.TrimPrefix(server.URL(), "https://")
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, toolchainApi.AddToScheme(scheme))
+	require.NoError(t, routev1.AddToScheme(scheme))
+
+	userSignup := GetUserSignupSpecs("e2e-user")
+	userSignup.Status.CompliantUsername = "e2e-user"
+	userSignup.Status.Conditions = []toolchainApi.Condition{{Type: toolchainApi.UserSignupComplete, Status: corev1.ConditionTrue}}
+	space := &toolchainApi.Space{ObjectMeta: metav1.ObjectMeta{Name: "e2e-user", Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}}
+	space.Status.ProvisionedNamespaces = []toolchainApi.SpaceNamespace{{Name: "e2e-user-tenant", Type: "default"}}
+	space.Status.Conditions = []toolchainApi.Condition{{Type: toolchainApi.ConditionReady, Status: corev1.ConditionTrue, Reason: toolchainApi.SpaceProvisionedReason}}
+
+	s.KubeRest = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_TOOLCHAIN_INSTANCE_NAME, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}, Spec: routev1.RouteSpec{Host: host}},
+		&routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_KEYCLOAK_INSTANCE_NAME, Namespace: DEFAULT_KEYCLOAK_NAMESPACE}, Spec: routev1.RouteSpec{Host: host}},
+		userSignup, space,
+	).WithStatusSubresource(userSignup, space).Build()
+	s.KubeClient = kubefake.NewSimpleClientset(
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_KEYCLOAK_INSTANCE_NAME, Namespace: DEFAULT_KEYCLOAK_NAMESPACE},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(1))},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_KEYCLOAK_ADMIN_SECRET, Namespace: DEFAULT_KEYCLOAK_NAMESPACE},
+			Data:       map[string][]byte{SECRET_KEY: []byte("admin-password")},
+		},
+	)
+
+	authInfo, err := s.ReconcileUserCreation("e2e-user")
+	require.NoError(t, err)
+	assert.Equal(t, "e2e-user", authInfo.UserName)
+	assert.Equal(t, "e2e-user-tenant", authInfo.UserNamespace)
+	assert.Equal(t, server.URL(), authInfo.ProxyUrl)
+	assert.Equal(t, "e2e-user@test.com", server.KeycloakUser(DEFAULT_KEYCLOAK_TESTING_REALM, "e2e-user").Email)
+
+	// the keycloak user is reused on the next provisioning
+	_, err = s.ReconcileUserCreation("e2e-user")
+	require.NoError(t, err)
+	created := 0
+	for _, request := range server.Requests() {
+		if request == "POST /auth/admin/realms/redhat-external/users" {
+			created++
+		}
+	}
+	assert.Equal(t, 1, created)
+}
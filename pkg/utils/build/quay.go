@@ -1,6 +1,7 @@
 package build
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -9,11 +10,12 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/konflux-ci/e2e-tests/pkg/clients/imagecontroller"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/registry"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 	quay "github.com/konflux-ci/image-controller/pkg/quay"
 	. "github.com/onsi/gomega"
-	"github.com/openshift/library-go/pkg/image/reference"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -25,10 +27,9 @@ const (
 )
 
 var (
-	quayApiUrl = "https://quay.io/api/v1"
 	quayOrg    = utils.GetEnv("DEFAULT_QUAY_ORG", "redhat-appstudio-qe")
 	quayToken  = utils.GetEnv("DEFAULT_QUAY_ORG_TOKEN", "")
-	quayClient = quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayToken, quayApiUrl)
+	quayClient = quay.NewQuayClient(&http.Client{Transport: &http.Transport{}}, quayToken, registry.QuayAPIURL)
 )
 
 type ImageInspectInfo struct {
@@ -37,26 +38,11 @@ type ImageInspectInfo struct {
 }
 
 func DoesImageRepoExistInQuay(quayImageRepoName string) (bool, error) {
-	exists, err := quayClient.DoesRepositoryExist(quayOrg, quayImageRepoName)
-	if exists {
-		return true, nil
-	} else if !exists && strings.Contains(err.Error(), "does not exist") {
-		return false, nil
-	} else {
-		return false, err
-	}
+	return imagecontroller.DoesQuayRepositoryExist(quayClient, quayOrg, quayImageRepoName)
 }
 
 func DoesRobotAccountExistInQuay(robotAccountName string) (bool, error) {
-	_, err := quayClient.GetRobotAccount(quayOrg, robotAccountName)
-	if err != nil {
-		if err.Error() == "Could not find robot with specified username" {
-			return false, nil
-		} else {
-			return false, err
-		}
-	}
-	return true, nil
+	return imagecontroller.DoesQuayRobotAccountExist(quayClient, quayOrg, robotAccountName)
 }
 
 func DeleteImageRepo(imageName string) (bool, error) {
@@ -70,32 +56,16 @@ func DeleteImageRepo(imageName string) (bool, error) {
 	return true, nil
 }
 
+// DoesTagExistsInQuay checks whether the tag of the image exists. Despite the name, any registry is supported,
+// e.g. a local registry:2 or Zot instance.
 // imageURL format example: quay.io/redhat-appstudio-qe/devfile-go-rhtap-uvv7:build-66d4e-1685533053
 func DoesTagExistsInQuay(imageURL string) (bool, error) {
-	ref, err := reference.Parse(imageURL)
-	if err != nil {
-		return false, err
-	}
-	if ref.Tag == "" {
-		return false, fmt.Errorf("image URL %s does not have tag", imageURL)
-	}
-	if ref.Namespace == "" {
-		return false, fmt.Errorf("image URL %s does not have namespace", imageURL)
-	}
-	tagList, _, err := quayClient.GetTagsFromPage(ref.Namespace, ref.Name, 0)
-	if err != nil {
-		return false, err
-	}
-	for _, tag := range tagList {
-		if tag.Name == ref.Tag {
-			return true, nil
-		}
-	}
-	return false, nil
+	return registry.TagExists(context.Background(), imageURL)
 }
 
+// IsImageRepoPublic checks whether the image repository created by image-controller in the Quay org is public.
 func IsImageRepoPublic(quayImageRepoName string) (bool, error) {
-	return quayClient.IsRepositoryPublic(quayOrg, quayImageRepoName)
+	return registry.NewQuayRegistry(quayToken).IsRepositoryPublic(context.Background(), quayOrg+"/"+quayImageRepoName)
 }
 
 func DoesQuayOrgSupportPrivateRepo() (bool, error) {
@@ -153,23 +123,9 @@ func GetRobotAccountInfoFromSecret(secret *corev1.Secret) (string, string) {
 	return robotAccountName, robotAccountToken
 }
 
-func GetImageTag(organization, repository, tagName string) (quay.Tag, error) {
-	page := 0
-	for {
-		page++
-		tags, hasAdditional, err := quayClient.GetTagsFromPage(organization, repository, page)
-		if err != nil {
-			return quay.Tag{}, err
-		}
-		for _, tag := range tags {
-			if tag.Name == tagName {
-				return tag, nil
-			}
-		}
-		if !hasAdditional {
-			return quay.Tag{}, fmt.Errorf("%s", fmt.Sprintf("cannot find tag %s", tagName))
-		}
-	}
+// GetImageTag returns the tag of the repository, e.g. "org/repo", in the given registry, e.g. "quay.io" or a local registry.
+func GetImageTag(registryHost, repository, tagName string) (registry.Tag, error) {
+	return registry.ForHost(registryHost).GetTag(context.Background(), repository, tagName)
 }
 
 func GetBuiltImageManifestMediaType(imageUrl string) (string, error) {
@@ -7,6 +7,8 @@ import (
 
 type Sbom interface {
 	GetPackages() []SbomPackage
+	// Normalize returns the format independent model of the SBOM, which can be compared with SBOMs of other formats
+	Normalize() *NormalizedSbom
 }
 
 type SbomPackage interface {
@@ -15,20 +17,78 @@ type SbomPackage interface {
 	GetPurl() string
 }
 
+// SbomCyclonedx is a CycloneDX 1.4 - 1.6 SBOM.
 type SbomCyclonedx struct {
-	BomFormat   string
-	SpecVersion string
-	Version     int
-	Components  []CyclonedxComponent `json:"components"`
+	BomFormat    string
+	SpecVersion  string
+	SerialNumber string `json:"serialNumber,omitempty"`
+	Version      int
+	Metadata     *CyclonedxMetadata    `json:"metadata,omitempty"`
+	Components   []CyclonedxComponent  `json:"components"`
+	Dependencies []CyclonedxDependency `json:"dependencies,omitempty"`
+	Annotations  []CyclonedxAnnotation `json:"annotations,omitempty"`
+}
+
+type CyclonedxMetadata struct {
+	Timestamp string              `json:"timestamp,omitempty"`
+	Component *CyclonedxComponent `json:"component,omitempty"`
 }
 
 type CyclonedxComponent struct {
-	Name    string `json:"name"`
-	Purl    string `json:"purl"`
-	Type    string `json:"type"`
-	Version string `json:"version"`
+	BomRef             string                       `json:"bom-ref,omitempty"`
+	Name               string                       `json:"name"`
+	Group              string                       `json:"group,omitempty"`
+	Purl               string                       `json:"purl"`
+	Type               string                       `json:"type"`
+	Version            string                       `json:"version"`
+	Hashes             []CyclonedxHash              `json:"hashes,omitempty"`
+	Licenses           []CyclonedxLicenseChoice     `json:"licenses,omitempty"`
+	ExternalReferences []CyclonedxExternalReference `json:"externalReferences,omitempty"`
+	Properties         []CyclonedxProperty          `json:"properties,omitempty"`
+	Components         []CyclonedxComponent         `json:"components,omitempty"`
+}
+
+type CyclonedxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+// CyclonedxLicenseChoice is either a license or an SPDX license expression.
+type CyclonedxLicenseChoice struct {
+	License    *CyclonedxLicense `json:"license,omitempty"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+type CyclonedxLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type CyclonedxExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type CyclonedxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
+type CyclonedxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// CyclonedxAnnotation is an annotation of CycloneDX 1.6, whose subjects are bom-refs.
+type CyclonedxAnnotation struct {
+	Subjects  []string `json:"subjects"`
+	Annotator any      `json:"annotator,omitempty"`
+	Timestamp string   `json:"timestamp,omitempty"`
+	Text      string   `json:"text"`
+}
+
+// GetPackages returns the top-level components of the SBOM. Nested components are only part of Normalize.
 func (s *SbomCyclonedx) GetPackages() []SbomPackage {
 	packages := []SbomPackage{}
 	for i := range s.Components {
@@ -49,16 +109,38 @@ func (c *CyclonedxComponent) GetPurl() string {
 	return c.Purl
 }
 
+// SbomSpdx is an SPDX 2.3 SBOM.
 type SbomSpdx struct {
-	SPDXID      string        `json:"SPDXID"`
-	SpdxVersion string        `json:"spdxVersion"`
-	Packages    []SpdxPackage `json:"packages"`
+	SPDXID            string             `json:"SPDXID"`
+	SpdxVersion       string             `json:"spdxVersion"`
+	Name              string             `json:"name,omitempty"`
+	DocumentNamespace string             `json:"documentNamespace,omitempty"`
+	CreationInfo      *SpdxCreationInfo  `json:"creationInfo,omitempty"`
+	Packages          []SpdxPackage      `json:"packages"`
+	Relationships     []SpdxRelationship `json:"relationships,omitempty"`
+}
+
+type SpdxCreationInfo struct {
+	Created  string   `json:"created,omitempty"`
+	Creators []string `json:"creators,omitempty"`
 }
 
 type SpdxPackage struct {
-	Name         string            `json:"name"`
-	VersionInfo  string            `json:"versionInfo"`
-	ExternalRefs []SpdxExternalRef `json:"externalRefs"`
+	SPDXID           string            `json:"SPDXID,omitempty"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo"`
+	Supplier         string            `json:"supplier,omitempty"`
+	DownloadLocation string            `json:"downloadLocation,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded,omitempty"`
+	LicenseDeclared  string            `json:"licenseDeclared,omitempty"`
+	Checksums        []SpdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []SpdxExternalRef `json:"externalRefs"`
+	Annotations      []SpdxAnnotation  `json:"annotations,omitempty"`
+}
+
+type SpdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
 }
 
 type SpdxExternalRef struct {
@@ -67,6 +149,19 @@ type SpdxExternalRef struct {
 	ReferenceType     string `json:"referenceType"`
 }
 
+type SpdxAnnotation struct {
+	AnnotationType string `json:"annotationType"`
+	Annotator      string `json:"annotator"`
+	AnnotationDate string `json:"annotationDate,omitempty"`
+	Comment        string `json:"comment"`
+}
+
+type SpdxRelationship struct {
+	SpdxElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
 func (s *SbomSpdx) GetPackages() []SbomPackage {
 	packages := []SbomPackage{}
 	for i := range s.Packages {
@@ -0,0 +1,166 @@
+package build
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SbomAssertion checks a policy on an SBOM, e.g. that every package has a license.
+type SbomAssertion func(s *NormalizedSbom) error
+
+// prefetchPurlTypes maps the package managers supported by the prefetch-dependencies task to the purl type of the packages they fetch.
+var prefetchPurlTypes = map[string]string{
+	"gomod":   "golang",
+	"pip":     "pypi",
+	"npm":     "npm",
+	"yarn":    "npm",
+	"bundler": "gem",
+	"cargo":   "cargo",
+	"rpm":     "rpm",
+	"generic": "generic",
+}
+
+// CheckSbom runs the assertions on the SBOM and returns all the failures.
+func CheckSbom(s *NormalizedSbom, assertions ...SbomAssertion) error {
+	var errs []error
+	for _, assertion := range assertions {
+		if err := assertion(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EveryPackageHasPurl fails when a package lacks a purl.
+func EveryPackageHasPurl() SbomAssertion {
+	return everyPackage("purl", func(p NormalizedPackage) bool { return p.Purl != "" })
+}
+
+// EveryPackageHasLicense fails when a package lacks a license.
+func EveryPackageHasLicense() SbomAssertion {
+	return everyPackage("license", func(p NormalizedPackage) bool { return len(p.Licenses) > 0 })
+}
+
+// EveryPackageHasHash fails when a package lacks a hash with the given algorithm, e.g. "sha256".
+func EveryPackageHasHash(algorithm string) SbomAssertion {
+	return everyPackage(algorithm+" hash", func(p NormalizedPackage) bool { return p.Hashes[normalizeHashAlgorithm(algorithm)] != "" })
+}
+
+// ContainsPackage fails when no package has the given purl, ignoring its version and qualifiers when the purl has none.
+func ContainsPackage(purl string) SbomAssertion {
+	return func(s *NormalizedSbom) error {
+		want := NormalizedPackage{Purl: purl}
+		for _, p := range s.Packages {
+			if p.Purl == purl || (want.Key() == purl && p.Key() == purl) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s SBOM does not contain package %s", s.Format, purl)
+	}
+}
+
+// ContainsPrefetchedDependencies fails when a package of the prefetched SBOM, i.e. the SBOM of the dependencies
+// fetched by the prefetch-dependencies task, is missing from the SBOM in the same version. Packages are matched by Key.
+func ContainsPrefetchedDependencies(prefetched *NormalizedSbom) SbomAssertion {
+	return func(s *NormalizedSbom) error {
+		packages := packagesByKey(s)
+		var missing []string
+		for _, p := range prefetched.Packages {
+			if p.Purl == "" {
+				continue
+			}
+			if _, ok := findVersion(packages[p.Key()], p.Version); !ok {
+				missing = append(missing, strings.TrimSpace(p.Key()+" "+p.Version))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%s SBOM does not contain %d prefetched dependencies: %s", s.Format, len(missing), strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
+
+// ContainsPrefetchedPackageManagers fails when a package manager of the prefetch-input value, as returned by GetPrefetchValue,
+// has no package with a purl of its type in the SBOM, or when a package reported by Cachi2 lacks a purl.
+// Use ContainsPrefetchedDependencies to check every prefetched dependency.
+func ContainsPrefetchedPackageManagers(prefetchValue string) SbomAssertion {
+	return func(s *NormalizedSbom) error {
+		packageManagers, err := ParsePrefetchValue(prefetchValue)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, pm := range packageManagers {
+			purlType, ok := prefetchPurlTypes[pm]
+			if !ok {
+				errs = append(errs, fmt.Errorf("unknown prefetch package manager %q", pm))
+				continue
+			}
+			found := false
+			for _, p := range s.Packages {
+				if p.PurlType() == purlType {
+					found = true
+					break
+				}
+			}
+			if !found {
+				errs = append(errs, fmt.Errorf("%s SBOM does not contain any %s package prefetched by %s", s.Format, purlType, pm))
+			}
+		}
+		if err := everyPackage("purl", func(p NormalizedPackage) bool {
+			return p.Properties["cachi2:found_by"] == "" || p.Purl != ""
+		})(s); err != nil {
+			errs = append(errs, fmt.Errorf("prefetched dependencies: %w", err))
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// ParsePrefetchValue returns the package managers of the prefetch-input parameter, which is either
+// the name of a package manager ("gomod"), an object ({"type": "gomod", "path": "."}) or a list of objects.
+func ParsePrefetchValue(prefetchValue string) ([]string, error) {
+	prefetchValue = strings.TrimSpace(prefetchValue)
+	if prefetchValue == "" {
+		return nil, nil
+	}
+	type prefetchInput struct {
+		Type string `json:"type"`
+	}
+	switch prefetchValue[0] {
+	case '{':
+		input := prefetchInput{}
+		if err := json.Unmarshal([]byte(prefetchValue), &input); err != nil {
+			return nil, fmt.Errorf("cannot parse prefetch-input %s: %+v", prefetchValue, err)
+		}
+		return []string{input.Type}, nil
+	case '[':
+		var inputs []prefetchInput
+		if err := json.Unmarshal([]byte(prefetchValue), &inputs); err != nil {
+			return nil, fmt.Errorf("cannot parse prefetch-input %s: %+v", prefetchValue, err)
+		}
+		var types []string
+		for _, input := range inputs {
+			types = append(types, input.Type)
+		}
+		return types, nil
+	default:
+		return []string{prefetchValue}, nil
+	}
+}
+
+func everyPackage(what string, ok func(p NormalizedPackage) bool) SbomAssertion {
+	return func(s *NormalizedSbom) error {
+		var missing []string
+		for _, p := range s.Packages {
+			if !ok(p) {
+				missing = append(missing, strings.TrimSpace(p.Name+" "+p.Version))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%d packages of the %s SBOM lack a %s: %s", len(missing), s.Format, what, strings.Join(missing, ", "))
+		}
+		return nil
+	}
+}
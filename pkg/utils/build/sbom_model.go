@@ -0,0 +1,320 @@
+package build
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	SbomFormatCyclonedx = "CycloneDX"
+	SbomFormatSpdx      = "SPDX"
+
+	// SbomRelationshipDependsOn is the relationship of a package to its dependency, in both formats
+	SbomRelationshipDependsOn = "DEPENDS_ON"
+	// SbomRelationshipContains is the relationship of a package to a nested package, in both formats
+	SbomRelationshipContains = "CONTAINS"
+)
+
+// NormalizedSbom is the format independent model of a CycloneDX or SPDX SBOM.
+type NormalizedSbom struct {
+	Format        string
+	SpecVersion   string
+	Packages      []NormalizedPackage
+	Relationships []SbomRelationship
+}
+
+// NormalizedPackage is a CycloneDX component or an SPDX package.
+// Hash algorithms are lower case without dashes, e.g. "sha256".
+type NormalizedPackage struct {
+	// ID is the bom-ref of a CycloneDX component or the SPDXID of an SPDX package
+	ID           string
+	Name         string
+	Version      string
+	Purl         string
+	Licenses     []string
+	Hashes       map[string]string
+	ExternalRefs []SbomExternalRef
+	Annotations  []string
+	Properties   map[string]string
+}
+
+type SbomExternalRef struct {
+	Type    string
+	Locator string
+}
+
+// SbomRelationship is a CycloneDX dependency or an SPDX relationship between the packages with the given IDs.
+type SbomRelationship struct {
+	From string
+	Type string
+	To   string
+}
+
+// Key identifies the package across SBOMs: the purl without version, qualifiers and subpath, or the name.
+func (p NormalizedPackage) Key() string {
+	if p.Purl == "" {
+		return p.Name
+	}
+	key, _, _ := strings.Cut(p.Purl, "?")
+	key, _, _ = strings.Cut(key, "#")
+	if i := strings.LastIndex(key, "@"); i > strings.LastIndex(key, "/") {
+		key = key[:i]
+	}
+	return key
+}
+
+// PurlType returns the type of the purl of the package, e.g. "golang" for "pkg:golang/github.com/org/repo@v1.0.0".
+func (p NormalizedPackage) PurlType() string {
+	purlType, _, _ := strings.Cut(strings.TrimPrefix(p.Purl, "pkg:"), "/")
+	return purlType
+}
+
+// Package returns the package with the given ID.
+func (s *NormalizedSbom) Package(id string) (NormalizedPackage, bool) {
+	for _, p := range s.Packages {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return NormalizedPackage{}, false
+}
+
+// Dependencies returns the IDs of the packages the package with the given ID depends on.
+func (s *NormalizedSbom) Dependencies(id string) []string {
+	var deps []string
+	for _, r := range s.Relationships {
+		if r.From == id && r.Type == SbomRelationshipDependsOn {
+			deps = append(deps, r.To)
+		}
+	}
+	return deps
+}
+
+func (s *SbomCyclonedx) Normalize() *NormalizedSbom {
+	normalized := &NormalizedSbom{Format: SbomFormatCyclonedx, SpecVersion: s.SpecVersion}
+	annotations := map[string][]string{}
+	for _, a := range s.Annotations {
+		for _, subject := range a.Subjects {
+			annotations[subject] = append(annotations[subject], a.Text)
+		}
+	}
+
+	var walk func(parent string, components []CyclonedxComponent)
+	walk = func(parent string, components []CyclonedxComponent) {
+		for _, c := range components {
+			p := NormalizedPackage{
+				ID:          c.BomRef,
+				Name:        c.Name,
+				Version:     c.Version,
+				Purl:        c.Purl,
+				Hashes:      map[string]string{},
+				Annotations: annotations[c.BomRef],
+				Properties:  map[string]string{},
+			}
+			if c.Group != "" {
+				p.Name = c.Group + "/" + c.Name
+			}
+			for _, h := range c.Hashes {
+				p.Hashes[normalizeHashAlgorithm(h.Alg)] = h.Content
+			}
+			for _, l := range c.Licenses {
+				switch {
+				case l.Expression != "":
+					p.Licenses = append(p.Licenses, l.Expression)
+				case l.License != nil && l.License.ID != "":
+					p.Licenses = append(p.Licenses, l.License.ID)
+				case l.License != nil && l.License.Name != "":
+					p.Licenses = append(p.Licenses, l.License.Name)
+				}
+			}
+			for _, r := range c.ExternalReferences {
+				p.ExternalRefs = append(p.ExternalRefs, SbomExternalRef{Type: r.Type, Locator: r.URL})
+			}
+			for _, prop := range c.Properties {
+				p.Properties[prop.Name] = prop.Value
+			}
+			normalized.Packages = append(normalized.Packages, p)
+			if parent != "" && c.BomRef != "" {
+				normalized.Relationships = append(normalized.Relationships, SbomRelationship{From: parent, Type: SbomRelationshipContains, To: c.BomRef})
+			}
+			walk(c.BomRef, c.Components)
+		}
+	}
+	walk("", s.Components)
+
+	for _, d := range s.Dependencies {
+		for _, dep := range d.DependsOn {
+			normalized.Relationships = append(normalized.Relationships, SbomRelationship{From: d.Ref, Type: SbomRelationshipDependsOn, To: dep})
+		}
+	}
+	return normalized
+}
+
+func (s *SbomSpdx) Normalize() *NormalizedSbom {
+	normalized := &NormalizedSbom{Format: SbomFormatSpdx, SpecVersion: strings.TrimPrefix(s.SpdxVersion, "SPDX-")}
+	for _, pkg := range s.Packages {
+		p := NormalizedPackage{
+			ID:         pkg.SPDXID,
+			Name:       pkg.Name,
+			Version:    pkg.VersionInfo,
+			Purl:       pkg.GetPurl(),
+			Hashes:     map[string]string{},
+			Properties: map[string]string{},
+		}
+		for _, license := range []string{pkg.LicenseConcluded, pkg.LicenseDeclared} {
+			if license != "" && license != "NOASSERTION" && license != "NONE" && !slices.Contains(p.Licenses, license) {
+				p.Licenses = append(p.Licenses, license)
+			}
+		}
+		for _, c := range pkg.Checksums {
+			p.Hashes[normalizeHashAlgorithm(c.Algorithm)] = c.ChecksumValue
+		}
+		for _, r := range pkg.ExternalRefs {
+			p.ExternalRefs = append(p.ExternalRefs, SbomExternalRef{Type: r.ReferenceType, Locator: r.ReferenceLocator})
+		}
+		for _, a := range pkg.Annotations {
+			p.Annotations = append(p.Annotations, a.Comment)
+		}
+		normalized.Packages = append(normalized.Packages, p)
+	}
+	for _, r := range s.Relationships {
+		normalized.Relationships = append(normalized.Relationships, SbomRelationship{From: r.SpdxElementID, Type: r.RelationshipType, To: r.RelatedSpdxElement})
+	}
+	return normalized
+}
+
+// normalizeHashAlgorithm maps the CycloneDX ("SHA-256") and SPDX ("SHA256") algorithm names to the same value ("sha256").
+func normalizeHashAlgorithm(alg string) string {
+	return strings.ToLower(strings.ReplaceAll(alg, "-", ""))
+}
+
+// SbomPackageChange is a package present in both SBOMs with a different version, licenses or hashes.
+type SbomPackageChange struct {
+	Old NormalizedPackage
+	New NormalizedPackage
+}
+
+// SbomDiff is the difference between two SBOMs, e.g. of the source and of the built image.
+type SbomDiff struct {
+	Added   []NormalizedPackage
+	Removed []NormalizedPackage
+	Changed []SbomPackageChange
+}
+
+// IsEmpty returns true when both SBOMs contain the same packages.
+func (d SbomDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d SbomDiff) String() string {
+	var b strings.Builder
+	for _, p := range d.Added {
+		fmt.Fprintf(&b, "+ %s %s\n", p.Key(), p.Version)
+	}
+	for _, p := range d.Removed {
+		fmt.Fprintf(&b, "- %s %s\n", p.Key(), p.Version)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %s %s -> %s\n", c.Old.Key(), c.Old.Version, c.New.Version)
+	}
+	return b.String()
+}
+
+// DiffSboms compares the packages of two SBOMs, which can be of different formats.
+// Packages are matched by Key, so a version bump is a change rather than an addition and a removal.
+// When a package is present in several versions, the versions present in both SBOMs are matched first,
+// then the remaining ones in version order, so the result does not depend on the order of the packages.
+// Hashes are only compared when both packages have a hash with the same algorithm.
+func DiffSboms(oldSbom, newSbom *NormalizedSbom) SbomDiff {
+	diff := SbomDiff{}
+	oldPackages := packagesByKey(oldSbom)
+	newPackages := packagesByKey(newSbom)
+
+	for _, key := range sortedKeys(newPackages) {
+		oldVersions, newVersions := oldPackages[key], newPackages[key]
+		var unmatchedOld, unmatchedNew []NormalizedPackage
+		for _, oldPkg := range oldVersions {
+			if _, ok := findVersion(newVersions, oldPkg.Version); !ok {
+				unmatchedOld = append(unmatchedOld, oldPkg)
+			}
+		}
+		for _, newPkg := range newVersions {
+			oldPkg, ok := findVersion(oldVersions, newPkg.Version)
+			if !ok {
+				unmatchedNew = append(unmatchedNew, newPkg)
+			} else if packageChanged(oldPkg, newPkg) {
+				diff.Changed = append(diff.Changed, SbomPackageChange{Old: oldPkg, New: newPkg})
+			}
+		}
+		for i, newPkg := range unmatchedNew {
+			if i < len(unmatchedOld) {
+				diff.Changed = append(diff.Changed, SbomPackageChange{Old: unmatchedOld[i], New: newPkg})
+			} else {
+				diff.Added = append(diff.Added, newPkg)
+			}
+		}
+		if len(unmatchedOld) > len(unmatchedNew) {
+			diff.Removed = append(diff.Removed, unmatchedOld[len(unmatchedNew):]...)
+		}
+	}
+	for _, key := range sortedKeys(oldPackages) {
+		if _, ok := newPackages[key]; !ok {
+			diff.Removed = append(diff.Removed, oldPackages[key]...)
+		}
+	}
+	return diff
+}
+
+// packagesByKey groups the packages by Key, with one package per version sorted by version.
+func packagesByKey(s *NormalizedSbom) map[string][]NormalizedPackage {
+	packages := map[string][]NormalizedPackage{}
+	for _, p := range s.Packages {
+		key := p.Key()
+		// the same package can be listed more than once, e.g. found in several layers
+		if _, ok := findVersion(packages[key], p.Version); !ok {
+			packages[key] = append(packages[key], p)
+		}
+	}
+	for _, versions := range packages {
+		sort.SliceStable(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	}
+	return packages
+}
+
+func findVersion(packages []NormalizedPackage, version string) (NormalizedPackage, bool) {
+	for _, p := range packages {
+		if p.Version == version {
+			return p, true
+		}
+	}
+	return NormalizedPackage{}, false
+}
+
+func sortedKeys(packages map[string][]NormalizedPackage) []string {
+	keys := make([]string, 0, len(packages))
+	for k := range packages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func packageChanged(oldPkg, newPkg NormalizedPackage) bool {
+	if oldPkg.Version != newPkg.Version {
+		return true
+	}
+	oldLicenses, newLicenses := slices.Clone(oldPkg.Licenses), slices.Clone(newPkg.Licenses)
+	slices.Sort(oldLicenses)
+	slices.Sort(newLicenses)
+	if len(oldLicenses) > 0 && len(newLicenses) > 0 && !slices.Equal(oldLicenses, newLicenses) {
+		return true
+	}
+	for alg, value := range oldPkg.Hashes {
+		if newValue, ok := newPkg.Hashes[alg]; ok && newValue != value {
+			return true
+		}
+	}
+	return false
+}
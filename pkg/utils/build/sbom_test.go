@@ -0,0 +1,142 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cyclonedxSbom = `{
+	"bomFormat": "CycloneDX",
+	"specVersion": "1.6",
+	"version": 1,
+	"components": [
+		{
+			"bom-ref": "app", "type": "application", "name": "app", "version": "1.0", "purl": "pkg:oci/app@sha256:abc",
+			"components": [
+				{"bom-ref": "x-text", "type": "library", "name": "golang.org/x/text", "version": "v0.14.0",
+				 "purl": "pkg:golang/golang.org/x/text@v0.14.0?type=module",
+				 "hashes": [{"alg": "SHA-256", "content": "aaa"}],
+				 "licenses": [{"license": {"id": "BSD-3-Clause"}}],
+				 "properties": [{"name": "cachi2:found_by", "value": "cachi2"}]}
+			]
+		},
+		{"bom-ref": "requests", "type": "library", "name": "requests", "version": "2.31.0", "purl": "pkg:pypi/requests@2.31.0",
+		 "licenses": [{"expression": "Apache-2.0"}],
+		 "externalReferences": [{"type": "vcs", "url": "https://github.com/psf/requests"}]}
+	],
+	"dependencies": [{"ref": "app", "dependsOn": ["x-text", "requests"]}],
+	"annotations": [{"subjects": ["requests"], "text": "prefetched"}]
+}`
+
+const spdxSbom = `{
+	"SPDXID": "SPDXRef-DOCUMENT",
+	"spdxVersion": "SPDX-2.3",
+	"packages": [
+		{"SPDXID": "SPDXRef-app", "name": "app", "versionInfo": "1.0", "licenseConcluded": "NOASSERTION",
+		 "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:oci/app@sha256:abc"}]},
+		{"SPDXID": "SPDXRef-x-text", "name": "golang.org/x/text", "versionInfo": "v0.15.0", "licenseConcluded": "BSD-3-Clause",
+		 "checksums": [{"algorithm": "SHA256", "checksumValue": "bbb"}],
+		 "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/golang.org/x/text@v0.15.0?type=module"}]},
+		{"SPDXID": "SPDXRef-openssl", "name": "openssl", "versionInfo": "3.0.7", "licenseDeclared": "Apache-2.0",
+		 "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:rpm/redhat/openssl@3.0.7"}],
+		 "annotations": [{"annotationType": "OTHER", "annotator": "Tool: syft", "comment": "found in layer 1"}]}
+	],
+	"relationships": [{"spdxElementId": "SPDXRef-app", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-x-text"}]
+}`
+
+func TestNormalizeSbom(t *testing.T) {
+	sbom, err := UnmarshalSbom([]byte(cyclonedxSbom))
+	assert.NoError(t, err)
+	assert.Len(t, sbom.GetPackages(), 2)
+
+	cdx := sbom.Normalize()
+	assert.Equal(t, SbomFormatCyclonedx, cdx.Format)
+	assert.Equal(t, []string{"x-text", "requests"}, cdx.Dependencies("app"))
+	assert.Contains(t, cdx.Relationships, SbomRelationship{From: "app", Type: SbomRelationshipContains, To: "x-text"})
+	xText, ok := cdx.Package("x-text")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"sha256": "aaa"}, xText.Hashes)
+	assert.Equal(t, []string{"BSD-3-Clause"}, xText.Licenses)
+	assert.Equal(t, "golang", xText.PurlType())
+	assert.Equal(t, "pkg:golang/golang.org/x/text", xText.Key())
+	requests, _ := cdx.Package("requests")
+	assert.Equal(t, []string{"prefetched"}, requests.Annotations)
+	assert.Equal(t, []SbomExternalRef{{Type: "vcs", Locator: "https://github.com/psf/requests"}}, requests.ExternalRefs)
+
+	sbom, err = UnmarshalSbom([]byte(spdxSbom))
+	assert.NoError(t, err)
+	spdx := sbom.Normalize()
+	assert.Equal(t, "2.3", spdx.SpecVersion)
+	assert.Equal(t, []string{"SPDXRef-x-text"}, spdx.Dependencies("SPDXRef-app"))
+	app, _ := spdx.Package("SPDXRef-app")
+	assert.Empty(t, app.Licenses)
+	openssl, _ := spdx.Package("SPDXRef-openssl")
+	assert.Equal(t, []string{"found in layer 1"}, openssl.Annotations)
+}
+
+func TestDiffSboms(t *testing.T) {
+	cdx, err := UnmarshalSbom([]byte(cyclonedxSbom))
+	assert.NoError(t, err)
+	spdx, err := UnmarshalSbom([]byte(spdxSbom))
+	assert.NoError(t, err)
+
+	diff := DiffSboms(cdx.Normalize(), spdx.Normalize())
+	assert.False(t, diff.IsEmpty())
+	assert.Len(t, diff.Added, 1)
+	assert.Equal(t, "openssl", diff.Added[0].Name)
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "requests", diff.Removed[0].Name)
+	assert.Len(t, diff.Changed, 1)
+	assert.Equal(t, "v0.15.0", diff.Changed[0].New.Version)
+	assert.Contains(t, diff.String(), "~ pkg:golang/golang.org/x/text v0.14.0 -> v0.15.0")
+
+	assert.True(t, DiffSboms(cdx.Normalize(), cdx.Normalize()).IsEmpty())
+}
+
+func TestDiffSbomsWithSeveralVersions(t *testing.T) {
+	pkg := func(version string) NormalizedPackage {
+		return NormalizedPackage{Name: "x/text", Version: version, Purl: "pkg:golang/x/text@" + version}
+	}
+	oldSbom := &NormalizedSbom{Packages: []NormalizedPackage{pkg("v0.14.0"), pkg("v0.13.0")}}
+	// the order of the packages does not matter
+	assert.True(t, DiffSboms(oldSbom, &NormalizedSbom{Packages: []NormalizedPackage{pkg("v0.13.0"), pkg("v0.14.0")}}).IsEmpty())
+
+	diff := DiffSboms(oldSbom, &NormalizedSbom{Packages: []NormalizedPackage{pkg("v0.15.0"), pkg("v0.13.0")}})
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Equal(t, []SbomPackageChange{{Old: pkg("v0.14.0"), New: pkg("v0.15.0")}}, diff.Changed)
+
+	diff = DiffSboms(oldSbom, &NormalizedSbom{Packages: []NormalizedPackage{pkg("v0.14.0")}})
+	assert.Equal(t, []NormalizedPackage{pkg("v0.13.0")}, diff.Removed)
+	assert.Empty(t, diff.Changed)
+}
+
+func TestSbomAssertions(t *testing.T) {
+	cdx, err := UnmarshalSbom([]byte(cyclonedxSbom))
+	assert.NoError(t, err)
+	spdx, err := UnmarshalSbom([]byte(spdxSbom))
+	assert.NoError(t, err)
+
+	assert.NoError(t, CheckSbom(cdx.Normalize(), EveryPackageHasPurl(), ContainsPackage("pkg:pypi/requests"),
+		ContainsPrefetchedPackageManagers(`[{"type": "gomod", "path": "."}, {"type": "pip"}]`)))
+
+	err = CheckSbom(spdx.Normalize(), EveryPackageHasLicense(), EveryPackageHasHash("SHA-256"), ContainsPrefetchedPackageManagers("pip"))
+	assert.ErrorContains(t, err, "1 packages of the SPDX SBOM lack a license: app 1.0")
+	assert.ErrorContains(t, err, "2 packages of the SPDX SBOM lack a SHA-256 hash: app 1.0, openssl 3.0.7")
+	assert.ErrorContains(t, err, "does not contain any pypi package prefetched by pip")
+
+	// every prefetched dependency has to be in the SBOM in the same version, including the nested ones
+	prefetched := &NormalizedSbom{Packages: []NormalizedPackage{
+		{Name: "requests", Version: "2.31.0", Purl: "pkg:pypi/requests@2.31.0"},
+		{Name: "golang.org/x/text", Version: "v0.14.0", Purl: "pkg:golang/golang.org/x/text@v0.14.0"},
+	}}
+	assert.NoError(t, CheckSbom(cdx.Normalize(), ContainsPrefetchedDependencies(prefetched)))
+	prefetched.Packages = append(prefetched.Packages, NormalizedPackage{Name: "urllib3", Version: "2.0.7", Purl: "pkg:pypi/urllib3@2.0.7"})
+	err = CheckSbom(spdx.Normalize(), ContainsPrefetchedDependencies(prefetched))
+	assert.ErrorContains(t, err, "SPDX SBOM does not contain 3 prefetched dependencies: pkg:pypi/requests 2.31.0, pkg:golang/golang.org/x/text v0.14.0, pkg:pypi/urllib3 2.0.7")
+
+	packageManagers, err := ParsePrefetchValue(`{"type": "rpm"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rpm"}, packageManagers)
+}
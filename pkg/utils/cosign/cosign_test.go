@@ -0,0 +1,150 @@
+package cosign
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcr "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/konflux-ci/e2e-tests/pkg/clients/oras"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	testDigest    = "sha256:0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0"
+	testPredicate = `{
+		"builder": {"id": "https://tekton.dev/chains/v2"},
+		"buildType": "tekton.dev/v1beta1/PipelineRun",
+		"invocation": {"parameters": {"git-url": "https://github.com/org/repo", "dockerfile": "Dockerfile"}},
+		"buildConfig": {"tasks": [{"name": "build-container", "results": [
+			{"name": "IMAGE_DIGEST", "type": "string", "value": "` + testDigest + `"},
+			{"name": "BASE_IMAGES", "type": "array", "value": ["registry.access.redhat.com/ubi9"]}
+		]}]},
+		"materials": [{"uri": "git+https://github.com/org/repo.git", "digest": {"sha1": "abc"}}]
+	}`
+)
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, data []byte) string {
+	digest := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func signedEnvelope(t *testing.T, key *ecdsa.PrivateKey, digest string) []byte {
+	statement, err := json.Marshal(map[string]any{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": SLSAProvenanceV02,
+		"subject":       []Subject{{Name: "quay.io/org/repo", Digest: map[string]string{"sha256": strings.TrimPrefix(digest, "sha256:")}}},
+		"predicate":     json.RawMessage(testPredicate),
+	})
+	assert.NoError(t, err)
+	envelope, err := json.Marshal(Envelope{
+		PayloadType: InTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(statement),
+		Signatures:  []EnvelopeSignature{{Sig: sign(t, key, pae(InTotoPayloadType, statement))}},
+	})
+	assert.NoError(t, err)
+	return envelope
+}
+
+func pushCosignImage(t *testing.T, ref, mediaType string, content []byte, annotations map[string]string) {
+	img, err := mutate.Append(mutate.MediaType(empty.Image, types.OCIManifestSchema1), mutate.Addendum{
+		Layer:       static.NewLayer(content, types.MediaType(mediaType)),
+		Annotations: annotations,
+	})
+	assert.NoError(t, err)
+	tag, err := name.NewTag(ref, name.Insecure)
+	assert.NoError(t, err)
+	assert.NoError(t, remote.Write(tag, img))
+}
+
+func TestVerify(t *testing.T) {
+	server := httptest.NewServer(ggcr.New())
+	t.Cleanup(server.Close)
+	repo := strings.TrimPrefix(server.URL, "http://") + "/org/repo"
+	cosignTag := repo + ":" + strings.Replace(testDigest, ":", "-", 1)
+
+	key, publicKey := newTestKey(t)
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"quay.io/org/repo"},"image":{"docker-manifest-digest":"` + testDigest + `"},"type":"cosign container image signature"},"optional":null}`)
+	pushCosignImage(t, cosignTag+".sig", SimpleSigningMediaType, payload, map[string]string{SignatureAnnotation: sign(t, key, payload)})
+	pushCosignImage(t, cosignTag+".att", DSSEEnvelopeMediaType, signedEnvelope(t, key, testDigest), nil)
+
+	verifier, err := NewVerifier(publicKey)
+	assert.NoError(t, err)
+	verifier.Client = &oras.Client{Credentials: func(context.Context, string) (auth.Credential, error) { return auth.EmptyCredential, nil }, PlainHTTP: true}
+
+	result, err := verifier.Verify(context.Background(), repo+"@"+testDigest)
+	assert.NoError(t, err)
+	assert.Len(t, result.Signatures, 1)
+	assert.Equal(t, "quay.io/org/repo", result.Signatures[0].Critical.Identity.DockerReference)
+	assert.Len(t, result.Attestations, 1)
+
+	g := NewWithT(t)
+	g.Expect(result.Attestations[0]).To(HaveSubjectDigest(testDigest))
+	provenance, err := result.Provenance()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(provenance).To(And(
+		HaveBuilderID("https://tekton.dev/chains/v2"),
+		HaveMaterial(ContainSubstring("github.com/org/repo")),
+		HaveInvocationParam("dockerfile", "Dockerfile"),
+		HaveTaskResult("build-container", "IMAGE_DIGEST", testDigest),
+		HaveTaskResult("build-container", "BASE_IMAGES", `["registry.access.redhat.com/ubi9"]`),
+	))
+	g.Expect(provenance).NotTo(HaveTaskResult("build-container", "IMAGE_URL", Not(BeEmpty())))
+
+	// signatures made with another key are rejected
+	_, otherPublicKey := newTestKey(t)
+	verifier.PublicKey, err = ParsePublicKey(otherPublicKey)
+	assert.NoError(t, err)
+	_, err = verifier.VerifySignatures(context.Background(), repo+"@"+testDigest)
+	assert.ErrorContains(t, err, "invalid ECDSA signature")
+	_, err = verifier.VerifyAttestations(context.Background(), repo+"@"+testDigest)
+	assert.ErrorContains(t, err, "no valid signature of the DSSE envelope")
+}
+
+func TestVerifyEnvelopeSubject(t *testing.T) {
+	key, _ := newTestKey(t)
+	statement, err := verifyEnvelope(&key.PublicKey, signedEnvelope(t, key, testDigest))
+	assert.NoError(t, err)
+	assert.True(t, statement.HasSubjectDigest(testDigest))
+	assert.False(t, statement.HasSubjectDigest("sha256:other"))
+
+	_, err = verifyEnvelope(&key.PublicKey, []byte(`{"payloadType": "text/plain"}`))
+	assert.ErrorContains(t, err, "unexpected payload type")
+}
+
+func TestCosignImage(t *testing.T) {
+	sigImage, digest, err := cosignImage("quay.io/org/repo:tag@"+testDigest, "sig")
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/org/repo:"+strings.Replace(testDigest, ":", "-", 1)+".sig", sigImage)
+	assert.Equal(t, testDigest, digest)
+
+	_, _, err = cosignImage("quay.io/org/repo:tag", "att")
+	assert.ErrorContains(t, err, "is not referenced by digest")
+}
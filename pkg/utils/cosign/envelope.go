@@ -0,0 +1,150 @@
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	// SimpleSigningMediaType is the media type of the layers of a cosign signature image
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// DSSEEnvelopeMediaType is the media type of the layers of a cosign attestation image
+	DSSEEnvelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+	// SignatureAnnotation holds the base64 encoded signature of a simple signing layer
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// InTotoPayloadType is the payload type of the DSSE envelopes holding an in-toto statement
+	InTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+// SimpleSigning is the payload signed by cosign when signing an image.
+type SimpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]any `json:"optional"`
+}
+
+// Envelope is a DSSE envelope, used by cosign to store the signed attestations.
+type Envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     string              `json:"payload"`
+	Signatures  []EnvelopeSignature `json:"signatures"`
+}
+
+type EnvelopeSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// ParsePublicKey parses a PEM encoded public key, e.g. the cosign.pub key of Tekton Chains.
+// ECDSA, RSA and ed25519 keys are supported.
+func ParsePublicKey(publicKeyPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("the public key is not PEM encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the public key: %+v", err)
+	}
+	return publicKey, nil
+}
+
+// verifySignature checks the signature of the data with the public key, hashing the data with sha256 for ECDSA and RSA keys.
+func verifySignature(publicKey crypto.PublicKey, data, signature []byte) error {
+	digest := sha256.Sum256(data)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("invalid RSA signature: %+v", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, signature) {
+			return fmt.Errorf("invalid ed25519 signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
+
+// verifySimpleSigning checks the base64 encoded signature of the simple signing payload and decodes it.
+func verifySimpleSigning(publicKey crypto.PublicKey, payload []byte, signature string) (SimpleSigning, error) {
+	simpleSigning := SimpleSigning{}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return simpleSigning, fmt.Errorf("cannot decode the signature: %+v", err)
+	}
+	if err := verifySignature(publicKey, payload, sig); err != nil {
+		return simpleSigning, err
+	}
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return simpleSigning, fmt.Errorf("cannot parse the simple signing payload: %+v", err)
+	}
+	return simpleSigning, nil
+}
+
+// verifyEnvelope checks that at least one signature of the DSSE envelope is valid and returns the decoded in-toto statement.
+func verifyEnvelope(publicKey crypto.PublicKey, envelopeJSON []byte) (Statement, error) {
+	statement := Statement{}
+	envelope := Envelope{}
+	if err := json.Unmarshal(envelopeJSON, &envelope); err != nil {
+		return statement, fmt.Errorf("cannot parse the DSSE envelope: %+v", err)
+	}
+	if envelope.PayloadType != InTotoPayloadType {
+		return statement, fmt.Errorf("unexpected payload type %q of the DSSE envelope", envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return statement, fmt.Errorf("cannot decode the payload of the DSSE envelope: %+v", err)
+	}
+	if len(envelope.Signatures) == 0 {
+		return statement, fmt.Errorf("the DSSE envelope is not signed")
+	}
+
+	var errs []error
+	verified := false
+	for _, s := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot decode the signature: %+v", err))
+			continue
+		}
+		if err := verifySignature(publicKey, pae(envelope.PayloadType, payload), sig); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		verified = true
+		break
+	}
+	if !verified {
+		return statement, fmt.Errorf("no valid signature of the DSSE envelope: %+v", errs)
+	}
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return statement, fmt.Errorf("cannot parse the in-toto statement: %+v", err)
+	}
+	return statement, nil
+}
+
+// pae returns the DSSE pre-authentication encoding of the payload, which is what gets signed.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
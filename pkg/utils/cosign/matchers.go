@@ -0,0 +1,42 @@
+package cosign
+
+import (
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+)
+
+// HaveBuilderID matches a *Provenance built by the given builder, e.g. "https://tekton.dev/chains/v2".
+func HaveBuilderID(id interface{}) types.GomegaMatcher {
+	return gomega.HaveField("BuilderID", id)
+}
+
+// HaveMaterial matches a *Provenance with a material whose URI matches uri, a value or a matcher.
+func HaveMaterial(uri interface{}) types.GomegaMatcher {
+	return gomega.WithTransform(func(p *Provenance) []string { return p.MaterialURIs() }, gomega.ContainElement(uri))
+}
+
+// HaveInvocationParam matches a *Provenance with the invocation parameter, whose value matches value, a value or a matcher.
+func HaveInvocationParam(name string, value interface{}) types.GomegaMatcher {
+	return gomega.WithTransform(func(p *Provenance) map[string]any { return p.InvocationParams }, gomega.HaveKeyWithValue(name, value))
+}
+
+// HaveTaskResult matches a *Provenance in which the task has the result, whose value matches value, a value or a matcher.
+func HaveTaskResult(taskName, resultName string, value interface{}) types.GomegaMatcher {
+	return gomega.WithTransform(func(p *Provenance) map[string]string {
+		task, _ := p.Task(taskName)
+		return task.Results
+	}, gomega.HaveKeyWithValue(resultName, value))
+}
+
+// HaveSubjectDigest matches a Statement about the image with the given digest, e.g. "sha256:abc...".
+func HaveSubjectDigest(digest string) types.GomegaMatcher {
+	return gomega.WithTransform(func(s Statement) []string {
+		var digests []string
+		for _, subject := range s.Subject {
+			for algorithm, encoded := range subject.Digest {
+				digests = append(digests, algorithm+":"+encoded)
+			}
+		}
+		return digests
+	}, gomega.ContainElement(digest))
+}
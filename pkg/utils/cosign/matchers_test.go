@@ -0,0 +1,49 @@
+package cosign
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchers(t *testing.T) {
+	statement := Statement{
+		PredicateType: SLSAProvenanceV02,
+		Subject:       []Subject{{Name: "quay.io/org/repo", Digest: map[string]string{"sha256": "abc"}}},
+		Predicate:     json.RawMessage(testPredicate),
+	}
+	provenance, err := statement.Provenance()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		actual  any
+		pass    types.GomegaMatcher
+		fail    types.GomegaMatcher
+		message []string
+	}{
+		{"HaveBuilderID", provenance, HaveBuilderID("https://tekton.dev/chains/v2"), HaveBuilderID("https://other/builder"), []string{"Value for field 'BuilderID' failed to satisfy matcher", "https://other/builder"}},
+		{"HaveMaterial", provenance, HaveMaterial("git+https://github.com/org/repo.git"), HaveMaterial(ContainSubstring("gitlab.com")), []string{"git+https://github.com/org/repo.git", "to contain element matching", "gitlab.com"}},
+		{"HaveInvocationParam", provenance, HaveInvocationParam("git-url", "https://github.com/org/repo"), HaveInvocationParam("dockerfile", "Containerfile"), []string{"to have {key: value}", `"dockerfile": <string>"Containerfile"`}},
+		{"HaveTaskResult", provenance, HaveTaskResult("build-container", "IMAGE_DIGEST", testDigest), HaveTaskResult("build-container", "IMAGE_URL", Not(BeEmpty())), []string{`"IMAGE_DIGEST": "` + testDigest + `"`, "to have {key: value} matching", `"IMAGE_URL"`}},
+		{"HaveSubjectDigest", statement, HaveSubjectDigest("sha256:abc"), HaveSubjectDigest("sha256:other"), []string{`["sha256:abc"]`, "to contain element matching", "sha256:other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := tt.pass.Match(tt.actual)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = tt.fail.Match(tt.actual)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+			message := tt.fail.FailureMessage(tt.actual)
+			for _, m := range tt.message {
+				assert.Contains(t, message, m)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package cosign
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	SLSAProvenanceV02 = "https://slsa.dev/provenance/v0.2"
+	SLSAProvenanceV1  = "https://slsa.dev/provenance/v1"
+)
+
+// Statement is an in-toto statement, the payload of a cosign attestation.
+type Statement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []Subject       `json:"subject"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Material is an input of the build, e.g. the git repository or a task bundle.
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// TaskProvenance is a task of the PipelineRun recorded in the provenance.
+type TaskProvenance struct {
+	Name    string
+	Results map[string]string
+}
+
+// Provenance is the SLSA provenance predicate, normalized over the v0.2 and v1 formats.
+type Provenance struct {
+	PredicateType string
+	BuilderID     string
+	BuildType     string
+	Materials     []Material
+	// InvocationParams are the invocation parameters for v0.2 and the external parameters for v1
+	InvocationParams map[string]any
+	// Tasks are only recorded by the v0.2 format
+	Tasks []TaskProvenance
+}
+
+type provenanceV02 struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	BuildType  string `json:"buildType"`
+	Invocation struct {
+		Parameters map[string]any `json:"parameters"`
+	} `json:"invocation"`
+	BuildConfig struct {
+		Tasks []struct {
+			Name    string `json:"name"`
+			Results []struct {
+				Name  string `json:"name"`
+				Value any    `json:"value"`
+			} `json:"results"`
+		} `json:"tasks"`
+	} `json:"buildConfig"`
+	Materials []Material `json:"materials"`
+}
+
+type provenanceV1 struct {
+	BuildDefinition struct {
+		BuildType            string         `json:"buildType"`
+		ExternalParameters   map[string]any `json:"externalParameters"`
+		ResolvedDependencies []Material     `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+	} `json:"runDetails"`
+}
+
+// HasSubjectDigest checks whether the statement is about the image with the given digest, e.g. "sha256:abc...".
+func (s Statement) HasSubjectDigest(digest string) bool {
+	algorithm, encoded, _ := strings.Cut(digest, ":")
+	for _, subject := range s.Subject {
+		if subject.Digest[algorithm] == encoded {
+			return true
+		}
+	}
+	return false
+}
+
+// Provenance decodes the SLSA provenance predicate of the statement.
+func (s Statement) Provenance() (*Provenance, error) {
+	provenance := &Provenance{PredicateType: s.PredicateType}
+	switch s.PredicateType {
+	case SLSAProvenanceV02:
+		predicate := provenanceV02{}
+		if err := json.Unmarshal(s.Predicate, &predicate); err != nil {
+			return nil, fmt.Errorf("cannot parse the SLSA v0.2 provenance: %+v", err)
+		}
+		provenance.BuilderID = predicate.Builder.ID
+		provenance.BuildType = predicate.BuildType
+		provenance.Materials = predicate.Materials
+		provenance.InvocationParams = predicate.Invocation.Parameters
+		for _, task := range predicate.BuildConfig.Tasks {
+			t := TaskProvenance{Name: task.Name, Results: map[string]string{}}
+			for _, result := range task.Results {
+				t.Results[result.Name] = resultValue(result.Value)
+			}
+			provenance.Tasks = append(provenance.Tasks, t)
+		}
+	case SLSAProvenanceV1:
+		predicate := provenanceV1{}
+		if err := json.Unmarshal(s.Predicate, &predicate); err != nil {
+			return nil, fmt.Errorf("cannot parse the SLSA v1 provenance: %+v", err)
+		}
+		provenance.BuilderID = predicate.RunDetails.Builder.ID
+		provenance.BuildType = predicate.BuildDefinition.BuildType
+		provenance.Materials = predicate.BuildDefinition.ResolvedDependencies
+		provenance.InvocationParams = predicate.BuildDefinition.ExternalParameters
+	default:
+		return nil, fmt.Errorf("predicate type %q is not a SLSA provenance", s.PredicateType)
+	}
+	return provenance, nil
+}
+
+// MaterialURIs returns the URIs of the materials.
+func (p *Provenance) MaterialURIs() []string {
+	uris := make([]string, 0, len(p.Materials))
+	for _, m := range p.Materials {
+		uris = append(uris, m.URI)
+	}
+	return uris
+}
+
+// InvocationParam returns the invocation parameter with the given name.
+func (p *Provenance) InvocationParam(name string) (any, bool) {
+	value, ok := p.InvocationParams[name]
+	return value, ok
+}
+
+// Task returns the task with the given name.
+func (p *Provenance) Task(name string) (TaskProvenance, bool) {
+	for _, t := range p.Tasks {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TaskProvenance{}, false
+}
+
+// TaskResult returns the result of the task. Array and object results are returned as JSON.
+func (p *Provenance) TaskResult(taskName, resultName string) (string, bool) {
+	task, ok := p.Task(taskName)
+	if !ok {
+		return "", false
+	}
+	value, ok := task.Results[resultName]
+	return value, ok
+}
+
+func resultValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
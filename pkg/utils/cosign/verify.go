@@ -0,0 +1,143 @@
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/oras"
+	"github.com/openshift/library-go/pkg/image/reference"
+)
+
+// Verifier downloads the cosign signatures and attestations of images and verifies them with a public key.
+type Verifier struct {
+	PublicKey crypto.PublicKey
+	// Client downloads the signature and attestation images
+	Client *oras.Client
+}
+
+// VerificationResult contains the verified signatures and attestations of an image.
+type VerificationResult struct {
+	Image        string
+	Signatures   []SimpleSigning
+	Attestations []Statement
+}
+
+// NewVerifier returns a Verifier for the PEM encoded public key, e.g. the key returned by GetTektonChainsPublicKey.
+func NewVerifier(publicKeyPEM []byte) (*Verifier, error) {
+	publicKey, err := ParsePublicKey(publicKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{PublicKey: publicKey, Client: oras.NewClient()}, nil
+}
+
+// Verify verifies both the signatures and the attestations of the image, which has to be referenced by digest.
+func (v *Verifier) Verify(ctx context.Context, image string) (*VerificationResult, error) {
+	signatures, err := v.VerifySignatures(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	attestations, err := v.VerifyAttestations(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	return &VerificationResult{Image: image, Signatures: signatures, Attestations: attestations}, nil
+}
+
+// VerifySignatures verifies the signatures stored in the .sig image of the image,
+// checking they were made with the public key and are about the image digest.
+func (v *Verifier) VerifySignatures(ctx context.Context, image string) ([]SimpleSigning, error) {
+	sigImage, digest, err := cosignImage(image, "sig")
+	if err != nil {
+		return nil, err
+	}
+	manifest, _, err := v.Client.FetchManifest(ctx, sigImage)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get signature image %s: %+v", sigImage, err)
+	}
+
+	var signatures []SimpleSigning
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != SimpleSigningMediaType {
+			continue
+		}
+		payload, err := v.Client.FetchBlob(ctx, sigImage, layer)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := verifySimpleSigning(v.PublicKey, payload, layer.Annotations[SignatureAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("signature %s of image %s is not valid: %+v", layer.Digest, image, err)
+		}
+		if signature.Critical.Image.DockerManifestDigest != digest {
+			return nil, fmt.Errorf("signature %s is about image digest %s instead of %s", layer.Digest, signature.Critical.Image.DockerManifestDigest, digest)
+		}
+		signatures = append(signatures, signature)
+	}
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("signature image %s does not contain any signature", sigImage)
+	}
+	return signatures, nil
+}
+
+// VerifyAttestations verifies the attestation envelopes stored in the .att image of the image,
+// checking they were signed with the public key and their subject is the image digest.
+func (v *Verifier) VerifyAttestations(ctx context.Context, image string) ([]Statement, error) {
+	attImage, digest, err := cosignImage(image, "att")
+	if err != nil {
+		return nil, err
+	}
+	manifest, _, err := v.Client.FetchManifest(ctx, attImage)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get attestation image %s: %+v", attImage, err)
+	}
+
+	var statements []Statement
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != DSSEEnvelopeMediaType {
+			continue
+		}
+		envelope, err := v.Client.FetchBlob(ctx, attImage, layer)
+		if err != nil {
+			return nil, err
+		}
+		statement, err := verifyEnvelope(v.PublicKey, envelope)
+		if err != nil {
+			return nil, fmt.Errorf("attestation %s of image %s is not valid: %+v", layer.Digest, image, err)
+		}
+		if !statement.HasSubjectDigest(digest) {
+			return nil, fmt.Errorf("attestation %s is not about image digest %s, subject: %+v", layer.Digest, digest, statement.Subject)
+		}
+		statements = append(statements, statement)
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("attestation image %s does not contain any attestation", attImage)
+	}
+	return statements, nil
+}
+
+// Provenance returns the first SLSA provenance among the attestations.
+func (r *VerificationResult) Provenance() (*Provenance, error) {
+	for _, statement := range r.Attestations {
+		if statement.PredicateType == SLSAProvenanceV02 || statement.PredicateType == SLSAProvenanceV1 {
+			return statement.Provenance()
+		}
+	}
+	return nil, fmt.Errorf("no SLSA provenance found among the %d attestations of image %s", len(r.Attestations), r.Image)
+}
+
+// cosignImage returns the reference of the image cosign stores the signatures ("sig") or attestations ("att") of the image in,
+// e.g. quay.io/org/repo@sha256:abc => quay.io/org/repo:sha256-abc.sig, along with the image digest.
+func cosignImage(image, suffix string) (string, string, error) {
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot parse image %s: %+v", image, err)
+	}
+	if ref.ID == "" {
+		return "", "", fmt.Errorf("image %s is not referenced by digest", image)
+	}
+	repository := ref.AsRepository().String()
+	return fmt.Sprintf("%s:%s.%s", repository, strings.Replace(ref.ID, ":", "-", 1), suffix), ref.ID, nil
+}
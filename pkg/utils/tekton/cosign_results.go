@@ -1,8 +1,11 @@
 package tekton
 
 import (
+	"context"
 	"fmt"
 	"strings"
+
+	"github.com/konflux-ci/e2e-tests/pkg/clients/registry"
 )
 
 type CosignResult struct {
@@ -10,34 +13,39 @@ type CosignResult struct {
 	AttestationImageRef string
 }
 
-// FindCosignResultsForImage looks for .sig and .att image tags in the OpenShift image stream for the provided image reference.
-// If none can be found errors.IsNotFound(err) is true, when err is nil CosignResult contains image references for signature and attestation images, otherwise other errors could be returned.
+// FindCosignResultsForImage looks for .sig and .att image tags in the registry hosting the provided image reference.
+// When err is nil CosignResult contains image references for signature and attestation images, otherwise the references found so far are returned along with the error.
 func FindCosignResultsForImage(imageRef string) (*CosignResult, error) {
 	var errMsg string
 	// Split the image ref into image repo+tag (e.g quay.io/repo/name:tag), and image digest (sha256:abcd...)
 	imageInfo := strings.Split(imageRef, "@")
-	imageRegistryName := strings.Split(imageInfo[0], "/")[0]
-	// imageRepoName is stripped from container registry name and a tag e.g. "quay.io/<org>/<repo>:tagprefix" => "<org>/<repo>"
-	imageRepoName := strings.Split(strings.TrimPrefix(imageInfo[0], fmt.Sprintf("%s/", imageRegistryName)), ":")[0]
+	if len(imageInfo) != 2 {
+		return nil, fmt.Errorf("image reference %s does not contain a digest", imageRef)
+	}
+	reg, imageRepoName, err := registry.ForImage(imageInfo[0])
+	if err != nil {
+		return nil, err
+	}
 	// Cosign creates tags for attestation and signature based on the image digest. Compute
 	// the expected prefix for later usage: sha256:abcd... -> sha256-abcd...
 	// Also, this prefix is really the prefix of the image tag resource which follows the
 	// format: <image-repo>:<tag-name>
 	imageTagPrefix := strings.Replace(imageInfo[1], ":", "-", 1)
+	ctx := context.Background()
 
 	results := CosignResult{}
-	signatureTag, err := getImageInfoFromQuay(imageRepoName, imageTagPrefix+".sig")
+	signatureTag, err := reg.GetTag(ctx, imageRepoName, imageTagPrefix+".sig")
 	if err != nil {
 		errMsg += fmt.Sprintf("error when getting signature tag: %+v\n", err)
 	} else {
-		results.SignatureImageRef = signatureTag.ImageRef
+		results.SignatureImageRef = fmt.Sprintf("%s/%s@%s", reg.Host(), imageRepoName, signatureTag.Digest)
 	}
 
-	attestationTag, err := getImageInfoFromQuay(imageRepoName, imageTagPrefix+".att")
+	attestationImageRef, err := getAttestationImageRef(ctx, reg, imageRepoName, imageTagPrefix+".att")
 	if err != nil {
 		errMsg += fmt.Sprintf("error when getting attestation tag: %+v\n", err)
 	} else {
-		results.AttestationImageRef = attestationTag.ImageRef
+		results.AttestationImageRef = attestationImageRef
 	}
 
 	if len(errMsg) > 0 {
@@ -47,6 +55,23 @@ func FindCosignResultsForImage(imageRef string) (*CosignResult, error) {
 	return &results, nil
 }
 
+// getAttestationImageRef returns the reference of the attestation image with the given tag, checking it contains at least one attestation.
+func getAttestationImageRef(ctx context.Context, reg registry.Registry, imageRepoName, tag string) (string, error) {
+	attestationTag, err := reg.GetTag(ctx, imageRepoName, tag)
+	if err != nil {
+		return "", err
+	}
+	imageRef := fmt.Sprintf("%s/%s@%s", reg.Host(), imageRepoName, attestationTag.Digest)
+	manifest, err := reg.GetManifest(ctx, imageRepoName, attestationTag.Digest)
+	if err != nil {
+		return "", fmt.Errorf("cannot get %s image from container registry: %+v", imageRef, err)
+	}
+	if len(manifest.Layers) < 1 {
+		return "", fmt.Errorf("cannot get layers from %s image", imageRef)
+	}
+	return imageRef, nil
+}
+
 // IsPresent checks if CosignResult is present.
 func (c CosignResult) IsPresent() bool {
 	return c.SignatureImageRef != "" && c.AttestationImageRef != ""
@@ -10,6 +10,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"knative.dev/pkg/apis"
 
+	"github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
 
 	pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -242,6 +243,25 @@ func GetFailedPipelineRunLogs(c crclient.Client, ki kubernetes.Interface, pipeli
 	return failMessage, nil
 }
 
+// NewPipelineFailedError returns the error of a failed PipelineRun, including its failed TaskRun, pod and step
+// found by GetFailedPipelineRunDetails and, if withLogs is true, the logs of the failed step.
+func NewPipelineFailedError(c crclient.Client, ki kubernetes.Interface, pipelineRun *pipeline.PipelineRun, withLogs bool) *clienterrors.PipelineFailedError {
+	failure := clienterrors.PipelineFailed(pipelineRun)
+	if d, err := GetFailedPipelineRunDetails(c, pipelineRun); err == nil {
+		failure.FailedTaskRun = d.FailedTaskRunName
+		failure.PodName = d.PodName
+		failure.FailedStep = d.FailedContainerName
+	}
+	if withLogs {
+		logs, err := GetFailedPipelineRunLogs(c, ki, pipelineRun)
+		if err != nil {
+			logs = fmt.Sprintf("failed to get PLR logs: %+v", err)
+		}
+		failure.Logs = logs
+	}
+	return failure
+}
+
 func HasPipelineRunSucceeded(pr *pipeline.PipelineRun) bool {
 	return pr.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue()
 }
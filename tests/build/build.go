@@ -381,7 +381,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build-ser
 				Expect(err).ShouldNot(HaveOccurred(),
 					fmt.Sprintf("cannot parse image pullspec: %s", builtImage))
 				for _, tagName := range additionalTags {
-					_, err := build.GetImageTag(builtImageRef.Namespace, builtImageRef.Name, tagName)
+					_, err := build.GetImageTag(builtImageRef.Registry, builtImageRef.RepositoryName(), tagName)
 					Expect(err).ShouldNot(HaveOccurred(),
 						fmt.Sprintf("failed to get tag %s from image repo", tagName),
 					)
@@ -550,7 +550,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build-ser
 			It("After updating image visibility to private, it should not trigger another PipelineRun", func() {
 				Expect(f.AsKubeAdmin.TektonController.DeleteAllPipelineRunsInASpecificNamespace(testNamespace)).To(Succeed())
 				Eventually(func() error {
-					_, err := f.AsKubeAdmin.ImageController.ChangeVisibilityToPrivate(testNamespace, applicationName, customBranchComponentName)
+					_, err := f.AsKubeAdmin.ImageController.ChangeVisibilityToPrivate(testNamespace, customBranchComponentName)
 					if err != nil {
 						GinkgoWriter.Printf("failed to change visibility to private with error %v\n", err)
 						return err
@@ -568,6 +568,8 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build-ser
 				}, 2*time.Minute, constants.PipelineRunPollingInterval).Should(BeTrue(), fmt.Sprintf("expected no PipelineRun to be triggered for the component %s in %s namespace", customBranchComponentName, testNamespace))
 			})
 			It("image repo is updated to private", func() {
+				_, err := f.AsKubeAdmin.ImageController.WaitForVisibility(testNamespace, customBranchComponentName, "private", time.Minute)
+				Expect(err).ShouldNot(HaveOccurred())
 				isPublic, err := build.IsImageRepoPublic(imageRepoName)
 				Expect(err).ShouldNot(HaveOccurred(), fmt.Sprintf("failed while checking if the image repo %s is private", imageRepoName))
 				Expect(isPublic).To(BeFalse(), "Expected image repo to changed to private, but it is public")
@@ -401,7 +401,7 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 				Expect(err).ShouldNot(HaveOccurred(),
 					fmt.Sprintf("cannot parse image pullspec: %s", builtImage))
 				for _, tagName := range additionalTags {
-					_, err := build.GetImageTag(builtImageRef.Namespace, builtImageRef.Name, tagName)
+					_, err := build.GetImageTag(builtImageRef.Registry, builtImageRef.RepositoryName(), tagName)
 					Expect(err).ShouldNot(HaveOccurred(),
 						fmt.Sprintf("failed to get tag %s from image repo", tagName),
 					)
@@ -455,7 +455,7 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 				Expect(err).ShouldNot(HaveOccurred(),
 					fmt.Sprintf("cannot parse binary image pullspec %s", binaryImage))
 
-				tagInfo, err := build.GetImageTag(binaryImageRef.Namespace, binaryImageRef.Name, binaryImageRef.Tag)
+				tagInfo, err := build.GetImageTag(binaryImageRef.Registry, binaryImageRef.RepositoryName(), binaryImageRef.Tag)
 				Expect(err).ShouldNot(HaveOccurred(),
 					fmt.Sprintf("failed to get tag %s info for constructing source container image", binaryImageRef.Tag),
 				)
@@ -464,7 +464,7 @@ var _ = framework.BuildSuiteDescribe("Build templates E2E test", Label("build",
 					Registry:  binaryImageRef.Registry,
 					Namespace: binaryImageRef.Namespace,
 					Name:      binaryImageRef.Name,
-					Tag:       fmt.Sprintf("%s.src", strings.Replace(tagInfo.ManifestDigest, ":", "-", 1)),
+					Tag:       fmt.Sprintf("%s.src", strings.Replace(tagInfo.Digest, ":", "-", 1)),
 				}
 				srcImage := srcImageRef.String()
 				tagExists, err := build.DoesTagExistsInQuay(srcImage)
@@ -901,10 +901,10 @@ func ensureOriginalDockerfileIsPushed(hub *framework.ControllerHub, pr *tektonpi
 	binaryImageRef, err := reference.Parse(binaryImage)
 	Expect(err).Should(Succeed())
 
-	tagInfo, err := build.GetImageTag(binaryImageRef.Namespace, binaryImageRef.Name, binaryImageRef.Tag)
+	tagInfo, err := build.GetImageTag(binaryImageRef.Registry, binaryImageRef.RepositoryName(), binaryImageRef.Tag)
 	Expect(err).Should(Succeed())
 
-	dockerfileImageTag := fmt.Sprintf("%s.dockerfile", strings.Replace(tagInfo.ManifestDigest, ":", "-", 1))
+	dockerfileImageTag := fmt.Sprintf("%s.dockerfile", strings.Replace(tagInfo.Digest, ":", "-", 1))
 
 	dockerfileImage := reference.DockerImageReference{
 		Registry:  binaryImageRef.Registry,
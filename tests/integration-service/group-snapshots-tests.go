@@ -516,8 +516,8 @@ var _ = framework.IntegrationServiceSuiteDescribe("Creation of group snapshots f
 					return nil
 				}, time.Minute*20, constants.PipelineRunPollingInterval).Should(Succeed(), "timeout while waiting for group snapshot")
 
-				// check annotation test.appstudio.openshift.io/group-test-info for each group snapshot
-				annotation := groupSnapshots.Items[0].GetAnnotations()
+				// check annotation test.appstudio.openshift.io/group-test-info for the latest group snapshot
+				annotation := f.AsKubeAdmin.IntegrationController.LatestSnapshot(groupSnapshots.Items).GetAnnotations()
 				if annotation, ok := annotation[testGroupSnapshotAnnotation]; ok {
 					// konflux-test
 					Expect(annotation).To(ContainSubstring(componentRepoNameForGroupIntegration))
@@ -532,7 +532,7 @@ var _ = framework.IntegrationServiceSuiteDescribe("Creation of group snapshots f
 				for _, component := range componentNames {
 					pipelineRun, err = f.AsKubeDeveloper.IntegrationController.GetBuildPipelineRun(component, applicationName, testNamespace, false, "")
 					Expect(err).ShouldNot(HaveOccurred())
-					annotation := groupSnapshots.Items[0].GetAnnotations()
+					annotation := f.AsKubeAdmin.IntegrationController.LatestSnapshot(groupSnapshots.Items).GetAnnotations()
 					if annotation, ok := annotation[testGroupSnapshotAnnotation]; ok {
 						Expect(annotation).To(ContainSubstring(pipelineRun.Name))
 					}
@@ -1,12 +1,20 @@
 package main
 
 import "fmt"
+import "os"
+import "os/signal"
+import "syscall"
 import "time"
 
+import chaos "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/chaos"
+import compare "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/compare"
+import distributed "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/distributed"
+import exporter "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/exporter"
 import journey "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/journey"
 import options "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/options"
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
 
+import kubeclient "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
 import cobra "github.com/spf13/cobra"
 import klog "k8s.io/klog/v2"
 
@@ -29,6 +37,28 @@ var rootCmd = &cobra.Command{
 	Long:  `Konflux performance test`,
 }
 
+var coordinatorOpts = distributed.CoordinatorOptions{}
+
+var coordinateCmd = &cobra.Command{
+	Use:          "coordinate",
+	Short:        "Coordinate distributed load test",
+	Long:         `Split user threads between load test workers started with --coordinator and merge their measurements and errors to one result set`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runCoordinate,
+}
+
+var compareOpts = compare.Options{}
+
+var compareCmd = &cobra.Command{
+	Use:          "compare BASELINE_DIR CANDIDATE_DIR...",
+	Short:        "Compare results of load test runs",
+	Long:         `Compare measurements of candidate runs (their --output-dir directories) to the baseline run and fail if some metric regressed`,
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE:         runCompare,
+}
+
 func init() {
 	rootCmd.Flags().StringVar(&opts.ComponentRepoUrl, "component-repo", "https://github.com/nodeshift-starters/devfile-sample", "the component repo URL to be used")
 	rootCmd.Flags().IntVar(&opts.ApplicationsCount, "applications-count", 1, "number of applications to create per user")
@@ -46,32 +76,134 @@ func init() {
 	rootCmd.Flags().StringVar(&opts.TestScenarioPathInRepo, "test-scenario-path-in-repo", "pipelines/integration_resolver_pipeline_pass.yaml", "test scenario path in GIT repo")
 	rootCmd.Flags().BoolVarP(&opts.WaitPipelines, "waitpipelines", "w", false, "if you want to wait for pipelines to finish")
 	rootCmd.Flags().BoolVarP(&opts.WaitIntegrationTestsPipelines, "waitintegrationtestspipelines", "i", false, "if you want to wait for IntegrationTests (Integration Test Scenario) pipelines to finish")
+	rootCmd.Flags().BoolVar(&opts.Release, "release", false, "if you want to create ReleasePlan and ReleasePlanAdmission per application and release every component (needs --waitpipelines)")
+	rootCmd.Flags().StringVar(&opts.ReleasePipelineUrl, "release-pipeline-url", "https://github.com/konflux-ci/release-service-catalog", "GIT URL of repo with release pipeline")
+	rootCmd.Flags().StringVar(&opts.ReleasePipelineRevision, "release-pipeline-revision", "development", "release pipeline GIT URL repo revision to use")
+	rootCmd.Flags().StringVar(&opts.ReleasePipelinePath, "release-pipeline-path-in-repo", "pipelines/managed/e2e/e2e.yaml", "release pipeline path in GIT repo")
+	rootCmd.Flags().StringVar(&opts.ReleaseServiceAccount, "release-service-account", "release-service-account", "service account in managed namespace to run release pipeline as (created if missing)")
 	rootCmd.Flags().BoolVar(&opts.FailFast, "fail-fast", false, "if you want the test to fail fast at first failure")
 	rootCmd.Flags().IntVarP(&opts.Concurrency, "concurrency", "c", 1, "number of concurrent threads to execute")
+	rootCmd.Flags().IntVar(&opts.ThreadOffset, "thread-offset", 0, "index of the first thread, so users of more load test processes do not overlap (users are '<username>-<index>' or index into users.json on Stage)")
+	rootCmd.Flags().StringVar(&opts.Coordinator, "coordinator", "", "URL of coordinator (e.g. 'http://coordinator:8080') to run as distributed load test worker, overrides --concurrency and --thread-offset")
+	rootCmd.Flags().StringVar(&opts.ArrivalProfile, "arrival-profile", "", "start user journeys according to arrival profile (constant, ramp, step or spike) for --journey-duration instead of running --concurrency threads")
+	rootCmd.Flags().Float64Var(&opts.ArrivalRate, "arrival-rate", 1, "number of new user journeys per minute (initial rate for ramp, rate increment for step, base rate for spike)")
+	rootCmd.Flags().Float64Var(&opts.ArrivalPeakRate, "arrival-peak-rate", 0, "final rate for ramp, maximal rate for step (unlimited when 0), spike rate for spike arrival profile")
+	rootCmd.Flags().DurationVar(&opts.ArrivalStep, "arrival-step", 10*time.Minute, "how often step arrival profile increases the rate, how long spike arrival profile lasts")
+	rootCmd.Flags().IntVar(&opts.MaxInFlight, "max-in-flight", 0, "maximal number of user journeys running at once with --arrival-profile, 0 for unlimited")
 	rootCmd.Flags().IntVar(&opts.JourneyRepeats, "journey-repeats", 1, "number of times to repeat user journey (either this or --journey-duration)")
+	rootCmd.Flags().StringVar(&opts.JourneyFile, "journey-file", "", "YAML file with journey definition to run instead of the built-in journey (see journeys/ directory)")
 	rootCmd.Flags().StringVar(&opts.JourneyDuration, "journey-duration", "1h", "repeat user journey until this timeout (either this or --journey-repeats)")
 	rootCmd.Flags().BoolVar(&opts.PipelineMintmakerDisabled, "pipeline-mintmaker-disabled", true, "if you want to stop Mintmaker to be creating update PRs for your component (default in loadtest different from Konflux default)")
 	rootCmd.Flags().BoolVar(&opts.PipelineRepoTemplating, "pipeline-repo-templating", false, "if we should use in repo template pipelines (merge PaC PR, template repo pipelines and ignore custom pipeline run, e.g. required for multi arch test)")
 	rootCmd.Flags().StringArrayVar(&opts.PipelineImagePullSecrets, "pipeline-image-pull-secrets", []string{}, "space separated secrets needed to pull task images")
+	rootCmd.Flags().BoolVar(&opts.Resume, "resume", false, "continue interrupted run from checkpoint in --output-dir (with --purge-only just purge resources recorded in the checkpoint)")
+	rootCmd.Flags().DurationVar(&opts.CheckpointInterval, "checkpoint-interval", time.Minute, "how often to save checkpoint to --output-dir, 0 to disable periodic saving (it is still saved whenever resources are created)")
 	rootCmd.Flags().StringVarP(&opts.OutputDir, "output-dir", "o", ".", "directory where output files such as load-tests.log or load-tests.json are stored")
 	rootCmd.Flags().StringVar(&opts.BuildPipelineSelectorBundle, "build-pipeline-selector-bundle", "", "BuildPipelineSelector bundle to use when testing with build-definition PR")
+	rootCmd.Flags().StringArrayVar(&opts.ChaosFaults, "chaos-fault", []string{}, "fault to inject during the test, repeat for more faults injected in turns: 'delete-pods:<controller>', 'scale-down:<controller>' or 'api-latency:<duration>' (controllers: build-service, integration-service, release-service, tekton-pipelines-controller, pipelines-as-code)")
+	rootCmd.Flags().DurationVar(&opts.ChaosInterval, "chaos-interval", 10*time.Minute, "how often to inject next fault from --chaos-fault")
+	rootCmd.Flags().DurationVar(&opts.ChaosDuration, "chaos-duration", 2*time.Minute, "how long scale-down and api-latency faults last")
+	rootCmd.Flags().StringVar(&opts.MetricsAddress, "metrics-address", "", "address (e.g. ':9090') to serve Prometheus metrics of measurements and active threads on, disabled when empty")
 	rootCmd.Flags().BoolVarP(&opts.LogInfo, "log-info", "v", false, "log messages with info level and above")
 	rootCmd.Flags().BoolVarP(&opts.LogDebug, "log-debug", "d", false, "log messages with debug level and above")
 	rootCmd.Flags().BoolVarP(&opts.LogTrace, "log-trace", "t", false, "log messages with trace level and above (i.e. everything)")
+
+	compareCmd.Flags().StringVar(&compareOpts.Statistic, "statistic", "mean", "statistic of durations to compare, one of mean, p50, p90, p95 or p99")
+	compareCmd.Flags().Float64Var(&compareOpts.Threshold, "threshold", 0.1, "relative increase of the statistic considered a regression, e.g. 0.1 for 10%")
+	compareCmd.Flags().Float64Var(&compareOpts.Alpha, "alpha", 0.05, "significance level (Mann-Whitney U test p-value) the increase has to reach to be considered a regression")
+	rootCmd.AddCommand(compareCmd)
+
+	coordinateCmd.Flags().IntVarP(&coordinatorOpts.Concurrency, "concurrency", "c", 1, "total number of concurrent threads to split between workers")
+	coordinateCmd.Flags().IntVar(&coordinatorOpts.Workers, "workers", 1, "number of workers to wait for")
+	coordinateCmd.Flags().StringVar(&coordinatorOpts.Listen, "listen", ":8080", "address to listen for workers on")
+	coordinateCmd.Flags().StringVarP(&coordinatorOpts.OutputDir, "output-dir", "o", ".", "directory where merged load-test-timings.csv, load-test-errors.csv and load-test-summary.json are stored")
+	coordinateCmd.Flags().DurationVar(&coordinatorOpts.Timeout, "timeout", 24*time.Hour, "how long to wait for all workers to finish")
+	coordinateCmd.Flags().DurationVar(&coordinatorOpts.WorkerTimeout, "worker-timeout", time.Minute, "how long a worker may not send anything before its assignment is given to the next worker that registers, e.g. the same worker restarted")
+	rootCmd.AddCommand(coordinateCmd)
+}
+
+// End active fault when the test is interrupted, so controllers are not left
+// scaled down. Second signal terminates the test immediately.
+func stopInjectorOnSignal(injector *chaos.Injector) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		signal.Stop(signals)
+		logging.Logger.Warning("Got %s, ending active fault before exiting, interrupt again to exit immediately", sig)
+		injector.Stop()
+		os.Exit(1)
+	}()
+}
+
+// Run coordinator of distributed load test
+func runCoordinate(cmd *cobra.Command, args []string) error {
+	logging.Logger.Level = logging.INFO
+	return distributed.Coordinate(coordinatorOpts)
+}
+
+// Compare every candidate run to the baseline run
+func runCompare(cmd *cobra.Command, args []string) error {
+	valid := false
+	for _, s := range compare.Statistics {
+		valid = valid || s == compareOpts.Statistic
+	}
+	if !valid {
+		return fmt.Errorf("Unknown statistic %q, use one of %v", compareOpts.Statistic, compare.Statistics)
+	}
+
+	baseline, err := compare.LoadRun(args[0])
+	if err != nil {
+		return err
+	}
+
+	regressions := 0
+	for _, dir := range args[1:] {
+		candidate, err := compare.LoadRun(dir)
+		if err != nil {
+			return err
+		}
+		report := compare.Compare(baseline, candidate, compareOpts)
+		if err := report.Write(os.Stdout); err != nil {
+			return err
+		}
+		fmt.Println()
+		regressions += report.Regressions()
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("Found %d regressions compared to %s", regressions, args[0])
+	}
+	return nil
 }
 
 func main() {
 	var err error
 
 	// Setup argument parser
-	err = rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
 		klog.Fatalln(err)
 	}
+	if cmd != rootCmd {
+		// Subcommand like 'compare' already did its job
+		return
+	}
 	if rootCmd.Flags().Lookup("help").Value.String() == "true" {
 		fmt.Println(rootCmd.UsageString())
 		return
 	}
+	// When running as distributed worker, get part of users to run from coordinator
+	var worker *distributed.Worker
+	if opts.Coordinator != "" {
+		worker, err = distributed.Register(opts.Coordinator, 10*time.Minute)
+		if err != nil {
+			logging.Logger.Fatal("Failed to register with coordinator: %v", err)
+		}
+		opts.ThreadOffset = worker.Assignment().ThreadOffset
+		opts.Concurrency = worker.Assignment().Concurrency
+	}
+
 	err = opts.ProcessOptions()
 	if err != nil {
 		logging.Logger.Fatal("Failed to process options: %v", err)
@@ -92,23 +224,108 @@ func main() {
 	// Show test options
 	logging.Logger.Debug("Options: %+v", opts)
 
-	// Tier up measurements logger
-	logging.MeasurementsStart(opts.OutputDir)
+	// Serve measurements as Prometheus metrics if requested
+	var metricsExporter *exporter.Exporter
+	if opts.MetricsAddress != "" {
+		metricsExporter = exporter.New(journey.ActiveThreads)
+		metricsExporter.Start(opts.MetricsAddress)
+	}
+
+	// Send measurements and errors to coordinator
+	if worker != nil {
+		worker.Start(10 * time.Second)
+	}
+
+	// Tier up measurements logger, when resuming continue from checkpoint
+	if opts.Resume {
+		checkpoint, err := journey.LoadCheckpoint(opts.OutputDir)
+		if err != nil {
+			logging.Logger.Fatal("Failed to load checkpoint: %v", err)
+		}
+		if checkpoint.Purged {
+			logging.Logger.Fatal("Resources recorded in the checkpoint were already purged, nothing to resume")
+		}
+		journey.ResumeCheckpoint(checkpoint, &opts)
+		err = logging.MeasurementsResume(opts.OutputDir, checkpoint.MeasurementsOffset, checkpoint.ErrorsOffset)
+		if err != nil {
+			logging.Logger.Fatal("Failed to resume measurements: %v", err)
+		}
+	} else {
+		logging.MeasurementsStart(opts.OutputDir)
+	}
+
+	// Save checkpoint as resources are created and periodically so interrupted run can be resumed
+	journey.StartCheckpoints(&opts, opts.CheckpointInterval)
+
+	// Inject faults on schedule if requested, latency has to be wrapped
+	// around Kubernetes clients before frameworks are created
+	var injector *chaos.Injector
+	if len(opts.ChaosFaults) > 0 && !opts.PurgeOnly {
+		faults, _ := chaos.ParseFaults(opts.ChaosFaults)
+		kubeclient.WrapTransport = chaos.WrapTransport
+		adminClient, err := kubeclient.NewAdminKubernetesClient()
+		if err != nil {
+			logging.Logger.Fatal("Failed to create client for fault injection: %v", err)
+		}
+		injector = chaos.NewInjector(adminClient.KubeInterface(), faults, opts.ChaosInterval, opts.ChaosDuration)
+		injector.Start()
+		stopInjectorOnSignal(injector)
+	}
 
-	// Start given number of `perUserThread()` threads using `journey.Setup()` and wait for them to finish
-	_, err = logging.Measure(journey.Setup, perUserThread, &opts)
+	// Use journey from definition file if provided
+	userThread := perUserThread
+	if opts.JourneyFile != "" {
+		definition, err := journey.LoadDefinition(opts.JourneyFile)
+		if err != nil {
+			logging.Logger.Fatal("Failed to load journey: %v", err)
+		}
+		userThread = definition.UserThread
+	}
+
+	// Start given number of `userThread()` threads using `journey.Setup()`
+	// or start them according to the arrival profile using `journey.SetupArrivals()`
+	// and wait for them to finish
+	if opts.ArrivalProfile != "" && !opts.PurgeOnly && !opts.Resume {
+		_, err = logging.Measure(journey.SetupArrivals, userThread, &opts)
+	} else {
+		_, err = logging.Measure(journey.Setup, userThread, &opts)
+	}
 	if err != nil {
 		logging.Logger.Fatal("Threads setup failed: %v", err)
 	}
 
+	// Stop injecting faults, so they do not affect purging
+	if injector != nil {
+		injector.Stop()
+	}
+
+	// Save final checkpoint before purging, so interrupted purge can be resumed
+	journey.StopCheckpoints(&opts)
+
 	// Cleanup resources
 	_, err = logging.Measure(journey.Purge)
 	if err != nil {
 		logging.Logger.Error("Purging failed: %v", err)
+	} else if opts.Purge {
+		if err := journey.MarkPurged(&opts); err != nil {
+			logging.Logger.Error("Failed to save checkpoint: %v", err)
+		}
 	}
 
 	// Tier down measurements logger
 	logging.MeasurementsStop()
+
+	// Send remaining measurements and errors to coordinator
+	if worker != nil {
+		if err := worker.Stop(); err != nil {
+			logging.Logger.Error("Failed to send results to coordinator: %v", err)
+		}
+	}
+
+	// Stop serving metrics
+	if metricsExporter != nil {
+		metricsExporter.Stop()
+	}
 }
 
 // Single user journey
@@ -194,7 +411,7 @@ func perUserThread(threadCtx *journey.MainContext) {
 	//watcher.Stop()
 	//os.Exit(10)
 
-	for threadCtx.JourneyRepeatsCounter = 1; threadCtx.JourneyRepeatsCounter <= threadCtx.Opts.JourneyRepeats; threadCtx.JourneyRepeatsCounter++ {
+	for threadCtx.JourneyRepeatsCounter = threadCtx.CompletedIterations + 1; threadCtx.JourneyRepeatsCounter <= threadCtx.Opts.JourneyRepeats && !journey.IterationsDone(threadCtx); threadCtx.JourneyRepeatsCounter++ {
 
 		// Start given number of `perApplicationThread()` threads using `journey.PerApplicationSetup()` and wait for them to finish
 		_, err = logging.Measure(journey.PerApplicationSetup, perApplicationThread, threadCtx)
@@ -202,6 +419,8 @@ func perUserThread(threadCtx *journey.MainContext) {
 			logging.Logger.Fatal("Per application threads setup failed: %v", err)
 		}
 
+		journey.CompleteIteration(threadCtx)
+
 		// Check if we are supposed to quit based on --journey-duration
 		if time.Now().UTC().After(threadCtx.Opts.JourneyUntil) {
 			logging.Logger.Debug("Done with user journey because of timeout")
@@ -246,6 +465,12 @@ func perApplicationThread(perApplicationCtx *journey.PerApplicationContext) {
 		return
 	}
 
+	_, err = logging.Measure(journey.HandleReleaseSetup, perApplicationCtx)
+	if err != nil {
+		logging.Logger.Error("Thread failed: %v", err)
+		return
+	}
+
 	// Start given number of `perComponentThread()` threads using `journey.PerComponentSetup()` and wait for them to finish
 	_, err = logging.Measure(journey.PerComponentSetup, perComponentThread, perApplicationCtx)
 	if err != nil {
@@ -293,4 +518,10 @@ func perComponentThread(perComponentCtx *journey.PerComponentContext) {
 		logging.Logger.Error("Per component thread failed: %v", err)
 		return
 	}
+
+	_, err = logging.Measure(journey.HandleRelease, perComponentCtx)
+	if err != nil {
+		logging.Logger.Error("Per component thread failed: %v", err)
+		return
+	}
 }
@@ -0,0 +1,99 @@
+package arrival
+
+import "fmt"
+import "math"
+import "time"
+
+// Supported arrival profiles
+const (
+	Constant = "constant" // constant rate for the whole duration
+	Ramp     = "ramp"     // rate linearly growing from rate to peak rate over the whole duration
+	Step     = "step"     // rate growing by rate every step interval, up to peak rate (if given)
+	Spike    = "spike"    // constant rate with peak rate for one step interval in the middle of the duration
+)
+
+// Profiles lists all supported arrival profiles
+var Profiles = []string{Constant, Ramp, Step, Spike}
+
+// Precision of computing arrival times
+const scheduleResolution = 10 * time.Millisecond
+
+// Describes how many new user journeys should start over time (open model).
+// Rates are in users per minute.
+type Profile struct {
+	Kind     string
+	Rate     float64
+	PeakRate float64
+	Step     time.Duration
+	Duration time.Duration
+}
+
+// Check profile parameters make sense
+func (p *Profile) Validate() error {
+	known := false
+	for _, kind := range Profiles {
+		if p.Kind == kind {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("Unknown arrival profile %q, supported are %v", p.Kind, Profiles)
+	}
+	if p.Rate <= 0 && p.Kind != Ramp {
+		return fmt.Errorf("Arrival rate has to be positive, got %v", p.Rate)
+	}
+	if p.Rate < 0 || p.PeakRate < 0 {
+		return fmt.Errorf("Arrival rates can not be negative, got %v and %v", p.Rate, p.PeakRate)
+	}
+	if (p.Kind == Ramp || p.Kind == Spike) && p.PeakRate <= 0 {
+		return fmt.Errorf("Arrival profile %s needs peak rate", p.Kind)
+	}
+	if (p.Kind == Step || p.Kind == Spike) && p.Step <= 0 {
+		return fmt.Errorf("Arrival profile %s needs step interval", p.Kind)
+	}
+	if p.Duration <= 0 {
+		return fmt.Errorf("Arrival duration has to be positive, got %v", p.Duration)
+	}
+	return nil
+}
+
+// Rate (users per minute) at given time since start
+func (p *Profile) RateAt(elapsed time.Duration) float64 {
+	if elapsed < 0 || elapsed >= p.Duration {
+		return 0
+	}
+	switch p.Kind {
+	case Ramp:
+		return p.Rate + (p.PeakRate-p.Rate)*elapsed.Seconds()/p.Duration.Seconds()
+	case Step:
+		rate := p.Rate * float64(1+elapsed/p.Step)
+		if p.PeakRate > 0 {
+			rate = math.Min(rate, p.PeakRate)
+		}
+		return rate
+	case Spike:
+		spikeStart := (p.Duration - p.Step) / 2
+		if elapsed >= spikeStart && elapsed < spikeStart+p.Step {
+			return p.PeakRate
+		}
+		return p.Rate
+	default:
+		return p.Rate
+	}
+}
+
+// Offsets from start at which user journeys should start. Arrival n
+// is scheduled when integral of the rate reaches n, so the first one
+// starts right away.
+func (p *Profile) Schedule() []time.Duration {
+	schedule := []time.Duration{}
+	expected := 0.0
+	for elapsed := time.Duration(0); elapsed < p.Duration; elapsed += scheduleResolution {
+		if expected >= float64(len(schedule)) {
+			schedule = append(schedule, elapsed)
+		}
+		expected += p.RateAt(elapsed) * scheduleResolution.Minutes()
+	}
+	return schedule
+}
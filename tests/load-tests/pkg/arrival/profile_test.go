@@ -0,0 +1,47 @@
+package arrival
+
+import "testing"
+import "time"
+
+import "github.com/stretchr/testify/assert"
+
+func TestSchedule(t *testing.T) {
+	constant := Profile{Kind: Constant, Rate: 6, Duration: time.Minute}
+	assert.NoError(t, constant.Validate())
+	schedule := constant.Schedule()
+	assert.Len(t, schedule, 6)
+	assert.Equal(t, time.Duration(0), schedule[0])
+	assert.InDelta(t, 10*time.Second, schedule[1], float64(scheduleResolution))
+	assert.InDelta(t, 50*time.Second, schedule[5], float64(scheduleResolution))
+
+	ramp := Profile{Kind: Ramp, Rate: 0, PeakRate: 20, Duration: time.Minute}
+	assert.NoError(t, ramp.Validate())
+	assert.Equal(t, 10.0, ramp.RateAt(30*time.Second))
+	schedule = ramp.Schedule()
+	assert.Len(t, schedule, 10)
+	// arrivals get denser as the rate grows
+	assert.Greater(t, schedule[2]-schedule[1], schedule[9]-schedule[8])
+
+	step := Profile{Kind: Step, Rate: 1, PeakRate: 2, Step: time.Minute, Duration: 3 * time.Minute}
+	assert.NoError(t, step.Validate())
+	assert.Equal(t, 1.0, step.RateAt(30*time.Second))
+	assert.Equal(t, 2.0, step.RateAt(90*time.Second))
+	assert.Equal(t, 2.0, step.RateAt(150*time.Second))
+	assert.Len(t, step.Schedule(), 5)
+
+	spike := Profile{Kind: Spike, Rate: 1, PeakRate: 60, Step: time.Minute, Duration: 3 * time.Minute}
+	assert.NoError(t, spike.Validate())
+	assert.Equal(t, 1.0, spike.RateAt(30*time.Second))
+	assert.Equal(t, 60.0, spike.RateAt(90*time.Second))
+	assert.Equal(t, 1.0, spike.RateAt(150*time.Second))
+	assert.Equal(t, 0.0, spike.RateAt(3*time.Minute))
+	assert.InDelta(t, 62, len(spike.Schedule()), 1)
+}
+
+func TestValidate(t *testing.T) {
+	assert.ErrorContains(t, (&Profile{Kind: "poisson", Rate: 1, Duration: time.Minute}).Validate(), "Unknown arrival profile")
+	assert.ErrorContains(t, (&Profile{Kind: Constant, Duration: time.Minute}).Validate(), "rate has to be positive")
+	assert.ErrorContains(t, (&Profile{Kind: Ramp, Rate: 1, Duration: time.Minute}).Validate(), "needs peak rate")
+	assert.ErrorContains(t, (&Profile{Kind: Step, Rate: 1, Duration: time.Minute}).Validate(), "needs step interval")
+	assert.ErrorContains(t, (&Profile{Kind: Constant, Rate: 1}).Validate(), "duration has to be positive")
+}
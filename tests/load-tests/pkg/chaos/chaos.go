@@ -0,0 +1,332 @@
+package chaos
+
+import "context"
+import "fmt"
+import "net/http"
+import "sort"
+import "strings"
+import "sync"
+import "sync/atomic"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+import corev1 "k8s.io/api/core/v1"
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import types "k8s.io/apimachinery/pkg/types"
+import kubernetes "k8s.io/client-go/kubernetes"
+import retry "k8s.io/client-go/util/retry"
+
+// Kinds of faults that can be injected
+const (
+	DeletePods = "delete-pods" // delete all pods of the target controller
+	ScaleDown  = "scale-down"  // scale the target controller to zero replicas for the fault duration
+	APILatency = "api-latency" // delay every Kubernetes API request of the load test for the fault duration
+)
+
+// Metrics recorded to the measurements timeline
+const (
+	InjectMetric   = "chaos.Inject"   // fault was active for the duration
+	RecoveryMetric = "chaos.Recovery" // time from the end of the fault until target controller was ready again
+)
+
+// Controller deployment faults can be injected to
+type Target struct {
+	Namespace  string
+	Deployment string
+}
+
+// Controllers faults can be injected to
+var Targets = map[string]Target{
+	"build-service":               {Namespace: "build-service", Deployment: "build-service-controller-manager"},
+	"integration-service":         {Namespace: "integration-service", Deployment: "integration-service-controller-manager"},
+	"release-service":             {Namespace: "release-service", Deployment: "release-service-controller-manager"},
+	"tekton-pipelines-controller": {Namespace: "openshift-pipelines", Deployment: "tekton-pipelines-controller"},
+	"pipelines-as-code":           {Namespace: "openshift-pipelines", Deployment: "pipelines-as-code-controller"},
+}
+
+// One fault to inject, e.g. "delete-pods:build-service" or "api-latency:500ms"
+type Fault struct {
+	Kind    string
+	Target  string
+	Latency time.Duration
+}
+
+func (f Fault) String() string {
+	if f.Kind == APILatency {
+		return fmt.Sprintf("%s:%s", f.Kind, f.Latency)
+	}
+	return fmt.Sprintf("%s:%s", f.Kind, f.Target)
+}
+
+// Parse faults given as "<kind>:<target>" or "api-latency:<duration>"
+func ParseFaults(specs []string) ([]Fault, error) {
+	faults := []Fault{}
+	for _, spec := range specs {
+		kind, value, ok := strings.Cut(spec, ":")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("Invalid fault %q, expected <kind>:<target> or %s:<duration>", spec, APILatency)
+		}
+		switch kind {
+		case DeletePods, ScaleDown:
+			if _, ok := Targets[value]; !ok {
+				return nil, fmt.Errorf("Unknown fault target %q, use one of %v", value, targetNames())
+			}
+			faults = append(faults, Fault{Kind: kind, Target: value})
+		case APILatency:
+			latency, err := time.ParseDuration(value)
+			if err != nil || latency <= 0 {
+				return nil, fmt.Errorf("Invalid latency %q of fault %q", value, spec)
+			}
+			faults = append(faults, Fault{Kind: kind, Latency: latency})
+		default:
+			return nil, fmt.Errorf("Unknown fault kind %q, use one of %s, %s or %s", kind, DeletePods, ScaleDown, APILatency)
+		}
+	}
+	return faults, nil
+}
+
+func targetNames() []string {
+	names := []string{}
+	for name := range Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Latency currently added to every Kubernetes API request, in nanoseconds
+var latency atomic.Int64
+
+type latencyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *latencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if delay := time.Duration(latency.Load()); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// Wrap HTTP transport of Kubernetes clients so api-latency faults can delay their requests
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &latencyTransport{next: rt}
+}
+
+// Injects faults one by one in a round robin fashion on a schedule
+type Injector struct {
+	client       kubernetes.Interface
+	faults       []Fault
+	interval     time.Duration
+	duration     time.Duration
+	timeout      time.Duration
+	pollInterval time.Duration
+	stop         chan struct{}
+	stopOnce     sync.Once
+	wg           sync.WaitGroup
+}
+
+// Create injector of given faults, one fault is injected every interval
+// and scale-down and api-latency faults last for given duration
+func NewInjector(client kubernetes.Interface, faults []Fault, interval, duration time.Duration) *Injector {
+	return &Injector{
+		client:       client,
+		faults:       faults,
+		interval:     interval,
+		duration:     duration,
+		timeout:      15 * time.Minute,
+		pollInterval: 5 * time.Second,
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start injecting faults until Stop is called
+func (i *Injector) Start() {
+	i.wg.Add(1)
+	go func() {
+		defer i.wg.Done()
+		ticker := time.NewTicker(i.interval)
+		defer ticker.Stop()
+		for next := 0; ; next++ {
+			select {
+			case <-ticker.C:
+				fault := i.faults[next%len(i.faults)]
+				if err := i.Inject(fault); err != nil {
+					logging.Logger.Error("Failed to inject fault %s: %v", fault, err)
+				}
+			case <-i.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop injecting faults, active fault is ended and its recovery awaited.
+// It is safe to call it more times, e.g. from a signal handler.
+func (i *Injector) Stop() {
+	i.stopOnce.Do(func() { close(i.stop) })
+	i.wg.Wait()
+}
+
+// Inject the fault, end it after the fault duration and wait for the target to recover
+func (i *Injector) Inject(fault Fault) error {
+	logging.Logger.Info("Injecting fault %s", fault)
+	params := map[string]string{"fault": fault.Kind, "target": fault.Target}
+	if fault.Kind == APILatency {
+		params["latency"] = fault.Latency.String()
+	}
+
+	var gone map[types.UID]bool
+	var replicas int32
+	var err error
+	start := time.Now()
+
+	switch fault.Kind {
+	case DeletePods:
+		gone, replicas, err = i.deletePods(Targets[fault.Target])
+	case ScaleDown:
+		replicas, err = i.scale(Targets[fault.Target], 0)
+		if err == nil {
+			i.wait()
+			err = i.restore(Targets[fault.Target], replicas)
+		}
+	case APILatency:
+		latency.Store(int64(fault.Latency))
+		i.wait()
+		latency.Store(0)
+	}
+	logging.LogMeasurement(InjectMetric, params, time.Since(start), fault.String(), err)
+	if err != nil || fault.Kind == APILatency {
+		return err
+	}
+
+	start = time.Now()
+	err = i.waitForRecovery(Targets[fault.Target], replicas, gone)
+	logging.LogMeasurement(RecoveryMetric, params, time.Since(start), fault.String(), err)
+	if err == nil {
+		logging.Logger.Info("Recovered from fault %s in %s", fault, time.Since(start))
+	}
+	return err
+}
+
+// Wait for the fault duration or until the injector is stopped
+func (i *Injector) wait() {
+	timer := time.NewTimer(i.duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-i.stop:
+	}
+}
+
+// Delete all pods of target deployment, returns UIDs of deleted pods and number of desired replicas
+func (i *Injector) deletePods(target Target) (map[types.UID]bool, int32, error) {
+	deployment, err := i.client.AppsV1().Deployments(target.Namespace).Get(context.Background(), target.Deployment, metav1.GetOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	selector := metav1.FormatLabelSelector(deployment.Spec.Selector)
+	pods, err := i.client.CoreV1().Pods(target.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	gone := map[types.UID]bool{}
+	for _, pod := range pods.Items {
+		err = i.client.CoreV1().Pods(target.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed to delete pod %s in namespace %s: %v", pod.Name, target.Namespace, err)
+		}
+		gone[pod.UID] = true
+	}
+	return gone, replicasOf(deployment.Spec.Replicas), nil
+}
+
+// Set replicas of target deployment, returns number of replicas it had before.
+// Deployment is updated by its controller too, so update is retried on conflict.
+func (i *Injector) scale(target Target, replicas int32) (int32, error) {
+	var previous int32
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := i.client.AppsV1().Deployments(target.Namespace).Get(context.Background(), target.Deployment, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		previous = replicasOf(deployment.Spec.Replicas)
+		deployment.Spec.Replicas = &replicas
+		_, err = i.client.AppsV1().Deployments(target.Namespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Failed to scale deployment %s in namespace %s to %d: %v", target.Deployment, target.Namespace, replicas, err)
+	}
+	return previous, nil
+}
+
+// Scale target deployment back to given replicas, retrying until the
+// timeout so a failed restore does not leave the controller scaled down
+func (i *Injector) restore(target Target, replicas int32) error {
+	deadline := time.Now().Add(i.timeout)
+	for {
+		_, err := i.scale(target, replicas)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Failed to restore deployment %s in namespace %s in %s: %v", target.Deployment, target.Namespace, i.timeout, err)
+		}
+		logging.Logger.Warning("Failed to restore deployment %s in namespace %s, retrying: %v", target.Deployment, target.Namespace, err)
+		time.Sleep(i.pollInterval)
+	}
+}
+
+// Wait until target deployment has given number of ready pods, not counting pods that were deleted
+func (i *Injector) waitForRecovery(target Target, replicas int32, gone map[types.UID]bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), i.timeout)
+	defer cancel()
+	for {
+		deployment, err := i.client.AppsV1().Deployments(target.Namespace).Get(ctx, target.Deployment, metav1.GetOptions{})
+		if err == nil {
+			var pods *corev1.PodList
+			pods, err = i.client.CoreV1().Pods(target.Namespace).List(ctx, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(deployment.Spec.Selector)})
+			if err == nil && readyPods(pods.Items, gone) >= replicas {
+				return nil
+			}
+		}
+		if err != nil {
+			logging.Logger.Debug("Failed to check recovery of deployment %s in namespace %s: %v", target.Deployment, target.Namespace, err)
+		}
+
+		select {
+		case <-time.After(i.pollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("Deployment %s in namespace %s did not recover in %s", target.Deployment, target.Namespace, i.timeout)
+		}
+	}
+}
+
+func readyPods(pods []corev1.Pod, gone map[types.UID]bool) int32 {
+	var ready int32
+	for _, pod := range pods {
+		if gone[pod.UID] || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				ready++
+			}
+		}
+	}
+	return ready
+}
+
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
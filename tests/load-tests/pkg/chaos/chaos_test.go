@@ -0,0 +1,162 @@
+package chaos
+
+import "context"
+import "net/http"
+import "net/http/httptest"
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+import "github.com/stretchr/testify/assert"
+import "github.com/stretchr/testify/require"
+
+import appsv1 "k8s.io/api/apps/v1"
+import corev1 "k8s.io/api/core/v1"
+import k8serrors "k8s.io/apimachinery/pkg/api/errors"
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import runtime "k8s.io/apimachinery/pkg/runtime"
+import schema "k8s.io/apimachinery/pkg/runtime/schema"
+import types "k8s.io/apimachinery/pkg/types"
+import fake "k8s.io/client-go/kubernetes/fake"
+import k8stesting "k8s.io/client-go/testing"
+
+func TestParseFaults(t *testing.T) {
+	faults, err := ParseFaults([]string{"delete-pods:build-service", "scale-down:release-service", "api-latency:500ms"})
+	require.NoError(t, err)
+	assert.Equal(t, []Fault{
+		{Kind: DeletePods, Target: "build-service"},
+		{Kind: ScaleDown, Target: "release-service"},
+		{Kind: APILatency, Latency: 500 * time.Millisecond},
+	}, faults)
+	assert.Equal(t, "api-latency:500ms", faults[2].String())
+
+	for _, spec := range []string{"delete-pods", "delete-pods:unknown", "api-latency:soon", "reboot:build-service"} {
+		_, err = ParseFaults([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func readyPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "build-service", UID: types.UID(name), Labels: map[string]string{"app": "build"}},
+		Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+	}
+}
+
+func TestInject(t *testing.T) {
+	dir := t.TempDir()
+	logging.MeasurementsStart(dir)
+
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-service-controller-manager", Namespace: "build-service"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "build"}},
+		},
+	}
+	other := readyPod("other")
+	other.Labels = map[string]string{"app": "other"}
+	client := fake.NewSimpleClientset(deployment, readyPod("old"), other)
+
+	injector := NewInjector(client, nil, time.Hour, 10*time.Millisecond)
+	injector.pollInterval = 10 * time.Millisecond
+	injector.timeout = 5 * time.Second
+
+	// Replacement pod shows up after a while, as if created by the deployment
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = client.CoreV1().Pods("build-service").Create(context.Background(), readyPod("new"), metav1.CreateOptions{})
+	}()
+	require.NoError(t, injector.Inject(Fault{Kind: DeletePods, Target: "build-service"}))
+	pods, err := client.CoreV1().Pods("build-service").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	names := []string{}
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	assert.ElementsMatch(t, []string{"new", "other"}, names)
+
+	// Replicas are restored after scale down
+	require.NoError(t, injector.Inject(Fault{Kind: ScaleDown, Target: "build-service"}))
+	scaled, err := client.AppsV1().Deployments("build-service").Get(context.Background(), deployment.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), *scaled.Spec.Replicas)
+
+	// Recovery times out when pods do not come back
+	injector.timeout = 50 * time.Millisecond
+	assert.ErrorContains(t, injector.Inject(Fault{Kind: DeletePods, Target: "build-service"}), "did not recover")
+
+	logging.MeasurementsStop()
+	data, err := os.ReadFile(filepath.Join(dir, "load-test-timings.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), InjectMetric))
+	assert.Equal(t, 3, strings.Count(string(data), RecoveryMetric))
+}
+
+func TestScaleDownRestoreRetries(t *testing.T) {
+	dir := t.TempDir()
+	logging.MeasurementsStart(dir)
+	defer logging.MeasurementsStop()
+
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-service-controller-manager", Namespace: "build-service"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "build"}},
+		},
+	}
+	client := fake.NewSimpleClientset(deployment, readyPod("a"), readyPod("b"))
+
+	// First update scales down, then restoring hits conflicts and an outage
+	updates := 0
+	client.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updates++
+		switch updates {
+		case 2, 3:
+			return true, nil, k8serrors.NewConflict(schema.GroupResource{Resource: "deployments"}, deployment.Name, nil)
+		case 4:
+			return true, nil, k8serrors.NewServiceUnavailable("api server is down")
+		}
+		return false, nil, nil
+	})
+
+	injector := NewInjector(client, nil, time.Hour, 10*time.Millisecond)
+	injector.pollInterval = 10 * time.Millisecond
+	injector.timeout = 5 * time.Second
+	require.NoError(t, injector.Inject(Fault{Kind: ScaleDown, Target: "build-service"}))
+
+	restored, err := client.AppsV1().Deployments("build-service").Get(context.Background(), deployment.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *restored.Spec.Replicas)
+	assert.Equal(t, 5, updates)
+
+	// Stop can be called more times
+	injector.Stop()
+	injector.Stop()
+}
+
+func TestLatencyTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+
+	latency.Store(int64(100 * time.Millisecond))
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	latency.Store(0)
+	start = time.Now()
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
@@ -0,0 +1,317 @@
+package compare
+
+import "encoding/csv"
+import "fmt"
+import "io"
+import "math"
+import "os"
+import "path/filepath"
+import "sort"
+import "strconv"
+import "text/tabwriter"
+
+import stats "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/stats"
+
+// Name of the KPI row of the report
+const KPIMetric = "KPI"
+
+// Statistics runs can be compared by
+var Statistics = []string{"mean", "p50", "p90", "p95", "p99"}
+
+// Results of one load test run loaded from its output directory
+type Run struct {
+	Dir     string
+	Samples map[string][]float64 // durations of passed measurements per metric
+	Errors  map[string]int       // number of failed measurements per metric
+	Summary *stats.Summary       // nil when the run did not write load-test-summary.json
+}
+
+// Load measurements from load-test-timings.csv and summary from load-test-summary.json in given directory
+func LoadRun(dir string) (*Run, error) {
+	run := &Run{Dir: dir, Samples: map[string][]float64{}, Errors: map[string]int{}}
+
+	file, err := os.Open(filepath.Clean(filepath.Join(dir, "load-test-timings.csv")))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open measurements of %s: %v", dir, err)
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read measurements of %s: %v", dir, err)
+	}
+	for _, record := range records {
+		if len(record) < 5 {
+			continue
+		}
+		metric := stats.ShortMetricName(record[1])
+		if record[4] != "<nil>" {
+			run.Errors[metric]++
+			continue
+		}
+		seconds, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse duration %q of %s in %s: %v", record[2], metric, dir, err)
+		}
+		run.Samples[metric] = append(run.Samples[metric], seconds)
+	}
+
+	summary, err := stats.LoadSummary(filepath.Join(dir, "load-test-summary.json"))
+	if err == nil {
+		run.Summary = summary
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Failed to load summary of %s: %v", dir, err)
+	}
+
+	return run, nil
+}
+
+// How to decide about regressions
+type Options struct {
+	Statistic string  // one of Statistics
+	Threshold float64 // relative increase of the statistic considered a regression, e.g. 0.1 for 10%
+	Alpha     float64 // significance level the increase has to reach to be considered a regression
+}
+
+// Comparison of one metric between baseline and candidate run
+type MetricComparison struct {
+	Metric           string
+	Baseline         float64
+	Candidate        float64
+	Delta            float64 // relative change, e.g. 0.1 when candidate is 10% slower
+	PValue           float64 // NaN when samples are not available
+	BaselineSamples  int
+	CandidateSamples int
+	BaselineErrors   int
+	CandidateErrors  int
+	Regression       bool
+	Note             string
+}
+
+// Comparison of candidate run to baseline run
+type Report struct {
+	Baseline  string
+	Candidate string
+	Options   Options
+	Metrics   []MetricComparison
+	KPI       MetricComparison
+}
+
+// Compare all metrics and KPI of candidate run to baseline run
+func Compare(baseline, candidate *Run, opts Options) *Report {
+	report := &Report{Baseline: baseline.Dir, Candidate: candidate.Dir, Options: opts}
+
+	metrics := map[string]bool{}
+	for _, run := range []*Run{baseline, candidate} {
+		for metric := range run.Samples {
+			metrics[metric] = true
+		}
+		for metric := range run.Errors {
+			metrics[metric] = true
+		}
+	}
+	names := make([]string, 0, len(metrics))
+	for metric := range metrics {
+		names = append(names, metric)
+	}
+	sort.Strings(names)
+
+	for _, metric := range names {
+		c := compareSamples(metric, baseline.Samples[metric], candidate.Samples[metric], opts)
+		c.BaselineErrors = baseline.Errors[metric]
+		c.CandidateErrors = candidate.Errors[metric]
+		compareErrors(&c)
+		report.Metrics = append(report.Metrics, c)
+	}
+
+	report.KPI = compareKPI(baseline.Summary, candidate.Summary, opts)
+
+	return report
+}
+
+func compareSamples(metric string, baseline, candidate []float64, opts Options) MetricComparison {
+	c := MetricComparison{Metric: metric, BaselineSamples: len(baseline), CandidateSamples: len(candidate), PValue: math.NaN()}
+	switch {
+	case len(baseline) == 0 && len(candidate) == 0:
+		c.Note = "no passed samples"
+		return c
+	case len(baseline) == 0:
+		c.Candidate = statistic(candidate, opts.Statistic)
+		c.Note = "not in baseline"
+		return c
+	case len(candidate) == 0:
+		// Candidate lost all the passed samples, e.g. because every one failed
+		c.Baseline = statistic(baseline, opts.Statistic)
+		c.Note = "not in candidate"
+		c.Regression = true
+		return c
+	}
+
+	c.Baseline = statistic(baseline, opts.Statistic)
+	c.Candidate = statistic(candidate, opts.Statistic)
+	c.Delta = relativeDelta(c.Baseline, c.Candidate)
+	_, c.PValue = MannWhitney(baseline, candidate)
+	c.Regression = c.Delta > opts.Threshold && c.PValue < opts.Alpha
+	return c
+}
+
+func compareKPI(baseline, candidate *stats.Summary, opts Options) MetricComparison {
+	if baseline == nil || candidate == nil {
+		return MetricComparison{Metric: KPIMetric, PValue: math.NaN(), Note: "missing load-test-summary.json"}
+	}
+
+	// Compare end-to-end durations of journey iterations when available
+	if len(baseline.KPI.DurationSamples) > 0 && len(candidate.KPI.DurationSamples) > 0 {
+		c := compareSamples(KPIMetric, baseline.KPI.DurationSamples, candidate.KPI.DurationSamples, opts)
+		c.BaselineErrors = baseline.KPI.Errors
+		c.CandidateErrors = candidate.KPI.Errors
+		compareErrors(&c)
+		return c
+	}
+
+	// Otherwise compare sum of mean durations of KPI metrics, without significance
+	c := MetricComparison{
+		Metric:          KPIMetric,
+		Baseline:        baseline.KPI.Mean,
+		Candidate:       candidate.KPI.Mean,
+		PValue:          math.NaN(),
+		BaselineErrors:  baseline.KPI.Errors,
+		CandidateErrors: candidate.KPI.Errors,
+		Note:            "sum of means, no samples",
+	}
+	switch {
+	case c.Baseline > 0 && c.Candidate <= 0:
+		// Some KPI metric has no passed sample in candidate
+		c.Note = "KPI not available in candidate"
+		c.Regression = true
+	case c.Baseline <= 0 || c.Candidate <= 0:
+		c.Note = "KPI not available"
+	default:
+		c.Delta = relativeDelta(c.Baseline, c.Candidate)
+		c.Regression = c.Delta > opts.Threshold
+	}
+	compareErrors(&c)
+	return c
+}
+
+// Candidate failing more often than baseline is a regression regardless of durations
+func compareErrors(c *MetricComparison) {
+	if c.CandidateErrors > c.BaselineErrors || errorRate(c.CandidateSamples, c.CandidateErrors) > errorRate(c.BaselineSamples, c.BaselineErrors) {
+		c.Regression = true
+		if c.Note == "" {
+			c.Note = "more errors"
+		}
+	}
+}
+
+// Share of failed samples out of all samples
+func errorRate(passed, failed int) float64 {
+	if failed == 0 {
+		return 0
+	}
+	return float64(failed) / float64(passed+failed)
+}
+
+// Number of metrics (including KPI) that regressed
+func (r *Report) Regressions() int {
+	count := 0
+	for _, c := range r.Metrics {
+		if c.Regression {
+			count++
+		}
+	}
+	if r.KPI.Regression {
+		count++
+	}
+	return count
+}
+
+// Write human readable report
+func (r *Report) Write(out io.Writer) error {
+	fmt.Fprintf(out, "Baseline:  %s\n", r.Baseline)
+	fmt.Fprintf(out, "Candidate: %s\n", r.Candidate)
+	fmt.Fprintf(out, "Comparing %s duration, regression is increase over %.1f%% with p-value under %g\n\n", r.Options.Statistic, r.Options.Threshold*100, r.Options.Alpha)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tBASELINE\tCANDIDATE\tDELTA\tP-VALUE\tSAMPLES\tERRORS\tRESULT")
+	for _, c := range append(append([]MetricComparison{}, r.Metrics...), r.KPI) {
+		result := "ok"
+		if c.Regression && c.Note != "" {
+			result = fmt.Sprintf("REGRESSION (%s)", c.Note)
+		} else if c.Regression {
+			result = "REGRESSION"
+		} else if c.Note != "" {
+			result = c.Note
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%d/%d\t%s\n",
+			c.Metric, seconds(c.Baseline), seconds(c.Candidate), percent(c), pvalue(c.PValue),
+			c.BaselineSamples, c.CandidateSamples, c.BaselineErrors, c.CandidateErrors, result)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "\n%d regressions found\n", r.Regressions())
+	return err
+}
+
+// Compute given statistic of samples
+func statistic(samples []float64, name string) float64 {
+	sorted := append([]float64{}, samples...)
+	sort.Float64s(sorted)
+	quantile := func(q float64) float64 {
+		rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return sorted[rank]
+	}
+	switch name {
+	case "p50":
+		return quantile(0.50)
+	case "p90":
+		return quantile(0.90)
+	case "p95":
+		return quantile(0.95)
+	case "p99":
+		return quantile(0.99)
+	default:
+		sum := 0.0
+		for _, v := range sorted {
+			sum += v
+		}
+		return sum / float64(len(sorted))
+	}
+}
+
+func relativeDelta(baseline, candidate float64) float64 {
+	if baseline == 0 {
+		if candidate == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (candidate - baseline) / baseline
+}
+
+func seconds(value float64) string {
+	if value == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.3fs", value)
+}
+
+func percent(c MetricComparison) string {
+	if c.Baseline == 0 || c.Candidate == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", c.Delta*100)
+}
+
+func pvalue(p float64) string {
+	if math.IsNaN(p) {
+		return "-"
+	}
+	return fmt.Sprintf("%.4f", p)
+}
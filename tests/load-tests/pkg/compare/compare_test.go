@@ -0,0 +1,170 @@
+package compare
+
+import "fmt"
+import "math"
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+import "github.com/stretchr/testify/assert"
+
+import stats "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/stats"
+
+func TestMannWhitney(t *testing.T) {
+	// Identical samples are not significantly different
+	u, p := MannWhitney([]float64{1, 2, 3, 4, 5}, []float64{1, 2, 3, 4, 5})
+	assert.Equal(t, 12.5, u)
+	assert.Equal(t, 1.0, p)
+
+	// Completely separated samples are
+	a := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	b := []float64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	u, p = MannWhitney(a, b)
+	assert.Equal(t, 0.0, u)
+	assert.InDelta(t, 0.00018, p, 0.00001)
+
+	// All values tied
+	_, p = MannWhitney([]float64{1, 1}, []float64{1, 1})
+	assert.Equal(t, 1.0, p)
+
+	_, p = MannWhitney([]float64{}, b)
+	assert.Equal(t, 1.0, p)
+}
+
+func writeRun(t *testing.T, durations map[string][]float64, failures map[string]int, summary *stats.Summary) string {
+	dir := t.TempDir()
+	lines := []string{}
+	for metric, values := range durations {
+		for _, v := range values {
+			lines = append(lines, fmt.Sprintf("2024-01-01T00:00:00Z,github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/journey.%s,%f,[],<nil>", metric, v))
+		}
+	}
+	for metric, count := range failures {
+		for i := 0; i < count; i++ {
+			lines = append(lines, fmt.Sprintf("2024-01-01T00:00:00Z,github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/journey.%s,1.000000,[],some error", metric))
+		}
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "load-test-timings.csv"), []byte(strings.Join(lines, "\n")+"\n"), 0600))
+	if summary != nil {
+		assert.NoError(t, stats.WriteSummary(filepath.Join(dir, "load-test-summary.json"), summary))
+	}
+	return dir
+}
+
+func series(start, step float64, count int) []float64 {
+	values := []float64{}
+	for i := 0; i < count; i++ {
+		values = append(values, start+float64(i)*step)
+	}
+	return values
+}
+
+func TestCompare(t *testing.T) {
+	baselineDir := writeRun(t,
+		map[string][]float64{"createApplication": series(10, 0.1, 20), "createComponent": series(5, 0.1, 20), "onlyBaseline": {1}},
+		map[string]int{"createComponent": 1},
+		&stats.Summary{KPI: stats.KPIStats{Mean: 100, DurationSamples: series(100, 1, 20)}})
+	candidateDir := writeRun(t,
+		map[string][]float64{"createApplication": series(10.05, 0.1, 20), "createComponent": series(8, 0.1, 20), "onlyCandidate": {1}},
+		map[string]int{"createComponent": 3},
+		&stats.Summary{KPI: stats.KPIStats{Mean: 150, DurationSamples: series(150, 1, 20)}})
+
+	baseline, err := LoadRun(baselineDir)
+	assert.NoError(t, err)
+	candidate, err := LoadRun(candidateDir)
+	assert.NoError(t, err)
+	assert.Len(t, baseline.Samples["journey.createApplication"], 20)
+	assert.Equal(t, 1, baseline.Errors["journey.createComponent"])
+
+	report := Compare(baseline, candidate, Options{Statistic: "mean", Threshold: 0.1, Alpha: 0.05})
+	metrics := map[string]MetricComparison{}
+	for _, c := range report.Metrics {
+		metrics[c.Metric] = c
+	}
+
+	// Small change is not regression
+	assert.False(t, metrics["journey.createApplication"].Regression)
+
+	// Significant slow down is
+	comp := metrics["journey.createComponent"]
+	assert.True(t, comp.Regression)
+	assert.InDelta(t, 3/5.95, comp.Delta, 0.0001)
+	assert.Less(t, comp.PValue, 0.001)
+	assert.Equal(t, 1, comp.BaselineErrors)
+	assert.Equal(t, 3, comp.CandidateErrors)
+
+	// Metrics present only in one run are not compared, but losing samples is a regression
+	assert.Equal(t, "not in candidate", metrics["journey.onlyBaseline"].Note)
+	assert.True(t, metrics["journey.onlyBaseline"].Regression)
+	assert.Equal(t, "not in baseline", metrics["journey.onlyCandidate"].Note)
+	assert.False(t, metrics["journey.onlyCandidate"].Regression)
+	assert.True(t, math.IsNaN(metrics["journey.onlyCandidate"].PValue))
+
+	assert.True(t, report.KPI.Regression)
+	assert.Equal(t, 3, report.Regressions())
+
+	out := &strings.Builder{}
+	assert.NoError(t, report.Write(out))
+	assert.Contains(t, out.String(), "REGRESSION (not in candidate)")
+	assert.Contains(t, out.String(), "3 regressions found")
+
+	// No regressions when compared to itself
+	assert.Equal(t, 0, Compare(baseline, baseline, Options{Statistic: "p90", Threshold: 0.1, Alpha: 0.05}).Regressions())
+}
+
+func TestCompareCandidateFailingEverySample(t *testing.T) {
+	baselineDir := writeRun(t,
+		map[string][]float64{"createApplication": series(10, 0.1, 20), "createComponent": series(5, 0.1, 20)},
+		map[string]int{},
+		&stats.Summary{KPI: stats.KPIStats{Mean: 15, DurationSamples: series(15, 1, 20)}})
+	candidateDir := writeRun(t,
+		map[string][]float64{"createApplication": series(10, 0.1, 20)},
+		map[string]int{"createComponent": 20},
+		&stats.Summary{KPI: stats.KPIStats{Mean: -1, Errors: 20}})
+
+	baseline, err := LoadRun(baselineDir)
+	assert.NoError(t, err)
+	candidate, err := LoadRun(candidateDir)
+	assert.NoError(t, err)
+
+	report := Compare(baseline, candidate, Options{Statistic: "mean", Threshold: 0.1, Alpha: 0.05})
+	metrics := map[string]MetricComparison{}
+	for _, c := range report.Metrics {
+		metrics[c.Metric] = c
+	}
+	assert.False(t, metrics["journey.createApplication"].Regression)
+	assert.True(t, metrics["journey.createComponent"].Regression)
+	assert.Equal(t, 20, metrics["journey.createComponent"].CandidateErrors)
+	assert.True(t, report.KPI.Regression)
+	assert.Equal(t, "KPI not available in candidate", report.KPI.Note)
+	assert.Equal(t, 2, report.Regressions())
+}
+
+func TestCompareErrorRate(t *testing.T) {
+	c := MetricComparison{BaselineSamples: 100, BaselineErrors: 2, CandidateSamples: 100, CandidateErrors: 2}
+	compareErrors(&c)
+	assert.False(t, c.Regression)
+
+	// Same number of errors out of fewer samples
+	c = MetricComparison{BaselineSamples: 100, BaselineErrors: 2, CandidateSamples: 10, CandidateErrors: 2}
+	compareErrors(&c)
+	assert.True(t, c.Regression)
+	assert.Equal(t, "more errors", c.Note)
+
+	c = MetricComparison{BaselineSamples: 10, BaselineErrors: 0, CandidateSamples: 10, CandidateErrors: 1}
+	compareErrors(&c)
+	assert.True(t, c.Regression)
+}
+
+func TestCompareKPIWithoutSamples(t *testing.T) {
+	baseline := &Run{Dir: "a", Summary: &stats.Summary{KPI: stats.KPIStats{Mean: 100}}}
+	candidate := &Run{Dir: "b", Summary: &stats.Summary{KPI: stats.KPIStats{Mean: 115}}}
+	report := Compare(baseline, candidate, Options{Statistic: "mean", Threshold: 0.1, Alpha: 0.05})
+	assert.True(t, report.KPI.Regression)
+	assert.InDelta(t, 0.15, report.KPI.Delta, 0.0001)
+
+	report = Compare(baseline, &Run{Dir: "c"}, Options{Statistic: "mean", Threshold: 0.1, Alpha: 0.05})
+	assert.False(t, report.KPI.Regression)
+	assert.Equal(t, "missing load-test-summary.json", report.KPI.Note)
+}
@@ -0,0 +1,58 @@
+package compare
+
+import "math"
+import "sort"
+
+// Two-sided Mann-Whitney U test of whether samples a and b come from the
+// same distribution. Returns U statistic of a and p-value computed with
+// normal approximation (with tie and continuity correction), so it is
+// only reliable with more than a few samples on each side.
+func MannWhitney(a, b []float64) (u float64, p float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+
+	type sample struct {
+		value float64
+		fromA bool
+	}
+	all := make([]sample, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range b {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Sum ranks of a, tied values get average of their ranks
+	rankSumA := 0.0
+	tieCorrection := 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromA {
+				rankSumA += rank
+			}
+		}
+		ties := float64(j - i)
+		tieCorrection += ties*ties*ties - ties
+		i = j
+	}
+
+	n := n1 + n2
+	u = rankSumA - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - tieCorrection/(n*(n-1))))
+	if sigma == 0 {
+		return u, 1
+	}
+
+	z := math.Max(math.Abs(u-mean)-0.5, 0) / sigma
+	return u, math.Erfc(z / math.Sqrt2)
+}
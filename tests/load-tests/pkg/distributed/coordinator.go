@@ -0,0 +1,225 @@
+package distributed
+
+import "context"
+import "encoding/json"
+import "errors"
+import "fmt"
+import "net/http"
+import "sync"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+// Options of the 'coordinate' command
+type CoordinatorOptions struct {
+	Concurrency   int
+	Listen        string
+	OutputDir     string
+	Timeout       time.Duration
+	Workers       int
+	WorkerTimeout time.Duration
+}
+
+// Hands out user threads to workers and stores measurements and errors
+// they send using logging, so the run has one merged result set
+type Coordinator struct {
+	assignments []Assignment
+	registered  int
+	generations map[int]int       // generation of the current assignment of every worker
+	sequences   map[int]int       // sequence of the last batch received from every worker
+	lastSeen    map[int]time.Time // when every worker sent something last time
+	done        map[int]bool
+	closed      bool // results are not accepted anymore
+	// Worker that did not send anything (not even empty batch) for this
+	// long is considered gone and its assignment is given to the next
+	// worker that registers, e.g. the same worker restarted
+	workerTimeout time.Duration
+	lock          sync.Mutex
+	finished      chan struct{}
+	server        *http.Server
+}
+
+// Create coordinator splitting given number of user threads between workers
+func NewCoordinator(concurrency, workers int) *Coordinator {
+	return &Coordinator{
+		assignments:   Partition(concurrency, workers),
+		generations:   map[int]int{},
+		sequences:     map[int]int{},
+		lastSeen:      map[int]time.Time{},
+		done:          map[int]bool{},
+		workerTimeout: time.Minute,
+		finished:      make(chan struct{}),
+	}
+}
+
+// HTTP handler serving the coordinator endpoints
+func (c *Coordinator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(RegisterPath, c.handleRegister)
+	mux.HandleFunc(BatchPath, func(w http.ResponseWriter, r *http.Request) { c.handleBatch(w, r, false) })
+	mux.HandleFunc(DonePath, func(w http.ResponseWriter, r *http.Request) { c.handleBatch(w, r, true) })
+	return mux
+}
+
+func (c *Coordinator) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		http.Error(w, "coordinator is stopped", http.StatusServiceUnavailable)
+		return
+	}
+	var assignment Assignment
+	if c.registered < len(c.assignments) {
+		assignment = c.assignments[c.registered]
+		c.registered++
+	} else if gone := c.goneWorker(); gone >= 0 {
+		assignment = c.assignments[gone]
+		logging.Logger.Warning("Worker %d did not send anything for %s, its assignment is reclaimed", gone, c.workerTimeout)
+	} else {
+		http.Error(w, fmt.Sprintf("all %d workers already registered", len(c.assignments)), http.StatusConflict)
+		return
+	}
+	c.generations[assignment.Worker]++
+	assignment.Generation = c.generations[assignment.Worker]
+	c.sequences[assignment.Worker] = 0
+	c.lastSeen[assignment.Worker] = time.Now()
+
+	logging.Logger.Info("Worker %d from %s registered, assigned %d threads from %d", assignment.Worker, r.RemoteAddr, assignment.Concurrency, assignment.ThreadOffset)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(assignment); err != nil {
+		logging.Logger.Error("Failed to send assignment to worker %d: %v", assignment.Worker, err)
+	}
+}
+
+func (c *Coordinator) handleBatch(w http.ResponseWriter, r *http.Request, last bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	batch := Batch{}
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		http.Error(w, fmt.Sprintf("invalid batch: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Results are recorded under the lock, so once Stop marks the coordinator
+	// closed, nothing is recorded after measurements are stopped
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		http.Error(w, "coordinator is stopped", http.StatusServiceUnavailable)
+		return
+	}
+	if batch.Worker < 0 || batch.Worker >= c.registered {
+		http.Error(w, fmt.Sprintf("unknown worker %d", batch.Worker), http.StatusBadRequest)
+		return
+	}
+	if batch.Generation != c.generations[batch.Worker] {
+		http.Error(w, fmt.Sprintf("assignment of worker %d was reclaimed by another worker", batch.Worker), http.StatusConflict)
+		return
+	}
+	c.lastSeen[batch.Worker] = time.Now()
+	if c.done[batch.Worker] {
+		// Worker did not get response to its last batch and sends it again
+		if last && batch.Sequence == c.sequences[batch.Worker] {
+			return
+		}
+		http.Error(w, fmt.Sprintf("worker %d already finished", batch.Worker), http.StatusConflict)
+		return
+	}
+	if batch.Sequence <= c.sequences[batch.Worker] {
+		logging.Logger.Debug("Worker %d sent batch %d again, ignoring it", batch.Worker, batch.Sequence)
+		return
+	}
+	c.sequences[batch.Worker] = batch.Sequence
+
+	for _, m := range batch.Measurements {
+		logging.RecordMeasurement(m.entry())
+	}
+	for _, e := range batch.Errors {
+		logging.RecordError(e)
+	}
+	logging.Logger.Debug("Worker %d sent %d measurements and %d errors", batch.Worker, len(batch.Measurements), len(batch.Errors))
+
+	if last {
+		c.done[batch.Worker] = true
+		logging.Logger.Info("Worker %d finished, %d of %d workers done", batch.Worker, len(c.done), len(c.assignments))
+		if len(c.done) == len(c.assignments) {
+			close(c.finished)
+		}
+	}
+}
+
+// Worker gone for longer than the worker timeout that did not finish, -1 if there is none
+func (c *Coordinator) goneWorker() int {
+	for _, assignment := range c.assignments {
+		if !c.done[assignment.Worker] && time.Since(c.lastSeen[assignment.Worker]) > c.workerTimeout {
+			return assignment.Worker
+		}
+	}
+	return -1
+}
+
+// Serve coordinator endpoints on given address (e.g. ":8080")
+func (c *Coordinator) Start(address string) {
+	c.server = &http.Server{Addr: address, Handler: c.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		logging.Logger.Info("Coordinator listening on %s for %d workers", address, len(c.assignments))
+		if err := c.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Logger.Error("Coordinator server failed: %v", err)
+		}
+	}()
+}
+
+// Wait until all workers finish or timeout passes
+func (c *Coordinator) Wait(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-c.finished:
+		return nil
+	case <-timer.C:
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		return fmt.Errorf("Only %d of %d workers finished in %s", len(c.done), len(c.assignments), timeout)
+	}
+}
+
+// Stop serving coordinator endpoints and accepting results, so measurements
+// can be stopped even when some worker is still sending
+func (c *Coordinator) Stop() {
+	if c.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := c.server.Shutdown(ctx); err != nil {
+			logging.Logger.Error("Failed to stop coordinator server: %v", err)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.closed = true
+}
+
+// Run coordinator until all workers finish, merged results are written to the output directory
+func Coordinate(opts CoordinatorOptions) error {
+	if opts.Workers < 1 || opts.Concurrency < opts.Workers {
+		return fmt.Errorf("Need at least one worker and at least one thread per worker, got %d workers and %d threads", opts.Workers, opts.Concurrency)
+	}
+
+	logging.MeasurementsStart(opts.OutputDir)
+	c := NewCoordinator(opts.Concurrency, opts.Workers)
+	if opts.WorkerTimeout > 0 {
+		c.workerTimeout = opts.WorkerTimeout
+	}
+	c.Start(opts.Listen)
+	err := c.Wait(opts.Timeout)
+	c.Stop()
+	logging.MeasurementsStop()
+	return err
+}
@@ -0,0 +1,113 @@
+package distributed
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "fmt"
+import "io"
+import "net/http"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+// Endpoints of the coordinator
+const (
+	RegisterPath = "/register" // worker asks for its assignment
+	BatchPath    = "/batch"    // worker sends measurements and errors
+	DonePath     = "/done"     // worker sends last measurements and errors and finishes
+)
+
+// Part of user threads assigned to one worker
+type Assignment struct {
+	Worker       int `json:"worker"`
+	ThreadOffset int `json:"thread_offset"`
+	Concurrency  int `json:"concurrency"`
+	// Bumped whenever the assignment is handed out again, so batches of a
+	// worker whose assignment was reclaimed by another worker are rejected
+	Generation int `json:"generation"`
+}
+
+// Split given number of user threads between workers as evenly as possible
+func Partition(concurrency, workers int) []Assignment {
+	assignments := []Assignment{}
+	offset := 0
+	for worker := 0; worker < workers; worker++ {
+		count := concurrency / workers
+		if worker < concurrency%workers {
+			count++
+		}
+		assignments = append(assignments, Assignment{Worker: worker, ThreadOffset: offset, Concurrency: count})
+		offset += count
+	}
+	return assignments
+}
+
+// Measurement as sent from worker to coordinator
+type Measurement struct {
+	Timestamp  time.Time     `json:"timestamp"`
+	Metric     string        `json:"metric"`
+	Duration   time.Duration `json:"duration"`
+	Parameters string        `json:"parameters"`
+	Error      string        `json:"error,omitempty"` // empty when measured function passed
+	Iteration  string        `json:"iteration,omitempty"`
+}
+
+func measurementFromEntry(entry logging.MeasurementEntry) Measurement {
+	m := Measurement{
+		Timestamp:  entry.Timestamp,
+		Metric:     entry.Metric,
+		Duration:   entry.Duration,
+		Parameters: entry.Parameters,
+		Iteration:  entry.Iteration,
+	}
+	if entry.Error != nil {
+		m.Error = entry.Error.Error()
+	}
+	return m
+}
+
+func (m Measurement) entry() logging.MeasurementEntry {
+	entry := logging.MeasurementEntry{
+		Timestamp:  m.Timestamp,
+		Metric:     m.Metric,
+		Duration:   m.Duration,
+		Parameters: m.Parameters,
+		Iteration:  m.Iteration,
+	}
+	if m.Error != "" {
+		entry.Error = errors.New(m.Error)
+	}
+	return entry
+}
+
+// Measurements and errors sent from worker to coordinator
+type Batch struct {
+	Worker     int `json:"worker"`
+	Generation int `json:"generation"`
+	// Increasing number of the batch, coordinator ignores batches it already
+	// got, so worker can resend a batch when it did not get the response
+	Sequence     int                  `json:"sequence"`
+	Measurements []Measurement        `json:"measurements"`
+	Errors       []logging.ErrorEntry `json:"errors"`
+}
+
+// Send request with JSON payload and decode JSON response if any is expected
+func post(client *http.Client, url string, payload, response interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Request to %s failed with %s: %s", url, resp.Status, bytes.TrimSpace(body))
+	}
+	if response == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -0,0 +1,137 @@
+package distributed
+
+import "errors"
+import "net/http"
+import "net/http/httptest"
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+import "github.com/stretchr/testify/assert"
+import "github.com/stretchr/testify/require"
+
+func TestPartition(t *testing.T) {
+	assert.Equal(t, []Assignment{
+		{Worker: 0, ThreadOffset: 0, Concurrency: 4},
+		{Worker: 1, ThreadOffset: 4, Concurrency: 3},
+		{Worker: 2, ThreadOffset: 7, Concurrency: 3},
+	}, Partition(10, 3))
+	assert.Equal(t, []Assignment{{Worker: 0, ThreadOffset: 0, Concurrency: 2}}, Partition(2, 1))
+}
+
+func TestMeasurementRoundTrip(t *testing.T) {
+	entry := logging.MeasurementEntry{Timestamp: time.Now(), Metric: "journey.HandleUser", Duration: time.Second, Parameters: "x", Error: errors.New("boom"), Iteration: "thread-3"}
+	back := measurementFromEntry(entry).entry()
+	assert.Equal(t, "boom", back.Error.Error())
+	assert.Equal(t, entry.Iteration, back.Iteration)
+
+	entry.Error = nil
+	assert.Nil(t, measurementFromEntry(entry).entry().Error)
+}
+
+func TestCoordinator(t *testing.T) {
+	dir := t.TempDir()
+	logging.MeasurementsStart(dir)
+
+	c := NewCoordinator(5, 2)
+	server := httptest.NewServer(c.Handler())
+	defer server.Close()
+
+	w1, err := Register(server.URL, 0)
+	require.NoError(t, err)
+	w2, err := Register(server.URL+"/", 0)
+	require.NoError(t, err)
+	assert.Equal(t, Assignment{Worker: 0, ThreadOffset: 0, Concurrency: 3, Generation: 1}, w1.Assignment())
+	assert.Equal(t, Assignment{Worker: 1, ThreadOffset: 3, Concurrency: 2, Generation: 1}, w2.Assignment())
+
+	_, err = Register(server.URL, 0)
+	assert.ErrorContains(t, err, "all 2 workers already registered")
+
+	now := time.Now()
+	w1.pending.Measurements = []Measurement{{Timestamp: now, Metric: "journey.HandleUser", Duration: time.Second, Iteration: "thread-0"}}
+	w1.pending.Errors = []logging.ErrorEntry{{Timestamp: now, Code: 10, Message: "FAIL(10): no user"}}
+	require.NoError(t, w1.send(BatchPath))
+	assert.Empty(t, w1.pending.Measurements)
+	assert.Nil(t, w1.unsent)
+
+	// Batch resent because its response got lost is not recorded again
+	resent := Batch{Worker: 0, Generation: 1, Sequence: 1, Measurements: []Measurement{{Timestamp: now, Metric: "journey.HandleUser"}}}
+	require.NoError(t, post(http.DefaultClient, server.URL+BatchPath, resent, nil))
+
+	w2.pending.Measurements = []Measurement{{Timestamp: now, Metric: "journey.HandleUser", Duration: 2 * time.Second, Error: "failed", Iteration: "thread-3"}}
+	require.NoError(t, w1.Stop())
+	assert.ErrorContains(t, c.Wait(10*time.Millisecond), "Only 1 of 2 workers finished")
+	require.NoError(t, w2.Stop())
+	assert.NoError(t, c.Wait(time.Second))
+
+	// Finishing again is fine, finished worker can not send more
+	require.NoError(t, post(http.DefaultClient, server.URL+DonePath, Batch{Worker: 1, Generation: 1, Sequence: w2.sequence}, nil))
+	assert.ErrorContains(t, post(http.DefaultClient, server.URL+BatchPath, Batch{Worker: 1, Generation: 1, Sequence: 99}, nil), "already finished")
+	assert.ErrorContains(t, post(http.DefaultClient, server.URL+BatchPath, Batch{Worker: 5}, nil), "unknown worker")
+
+	// Nothing is recorded once the coordinator is stopped
+	c.Stop()
+	assert.ErrorContains(t, post(http.DefaultClient, server.URL+BatchPath, Batch{Worker: 0, Generation: 1, Sequence: 99}, nil), "coordinator is stopped")
+
+	logging.MeasurementsStop()
+	timings, err := os.ReadFile(filepath.Join(dir, "load-test-timings.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(timings), "journey.HandleUser"))
+	assert.Contains(t, string(timings), ",failed,thread-3\n")
+	failures, err := os.ReadFile(filepath.Join(dir, "load-test-errors.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(failures), "FAIL(10): no user")
+}
+
+func TestWorkerKeepsUnsentBatch(t *testing.T) {
+	c := NewCoordinator(1, 1)
+	server := httptest.NewServer(c.Handler())
+	w, err := Register(server.URL, 0)
+	require.NoError(t, err)
+	server.Close()
+
+	w.pending.Measurements = []Measurement{{Metric: "journey.HandleUser"}}
+	assert.Error(t, w.send(BatchPath))
+	w.pending.Measurements = []Measurement{{Metric: "journey.HandleApplication"}}
+	assert.Error(t, w.send(BatchPath))
+
+	// Unsent batch is kept with its sequence, newer measurements wait for the next batch
+	require.NotNil(t, w.unsent)
+	assert.Equal(t, 1, w.unsent.Sequence)
+	assert.Equal(t, "journey.HandleUser", w.unsent.Measurements[0].Metric)
+	assert.Len(t, w.pending.Measurements, 1)
+	assert.True(t, w.hasPending())
+}
+
+func TestReclaimAssignment(t *testing.T) {
+	c := NewCoordinator(2, 1)
+	c.workerTimeout = 200 * time.Millisecond
+	server := httptest.NewServer(c.Handler())
+	defer server.Close()
+
+	gone, err := Register(server.URL, 0)
+	require.NoError(t, err)
+	_, err = Register(server.URL, 0)
+	assert.ErrorContains(t, err, "all 1 workers already registered")
+
+	// Empty batches keep the worker alive
+	time.Sleep(120 * time.Millisecond)
+	require.NoError(t, gone.send(BatchPath))
+	time.Sleep(120 * time.Millisecond)
+	_, err = Register(server.URL, 0)
+	assert.ErrorContains(t, err, "all 1 workers already registered")
+
+	// Restarted worker takes over assignment of the gone one
+	time.Sleep(150 * time.Millisecond)
+	restarted, err := Register(server.URL, 0)
+	require.NoError(t, err)
+	assert.Equal(t, Assignment{Worker: 0, ThreadOffset: 0, Concurrency: 2, Generation: 2}, restarted.Assignment())
+	assert.ErrorContains(t, gone.send(BatchPath), "reclaimed by another worker")
+
+	require.NoError(t, restarted.finish())
+	assert.NoError(t, c.Wait(time.Second))
+}
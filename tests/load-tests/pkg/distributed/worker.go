@@ -0,0 +1,159 @@
+package distributed
+
+import "fmt"
+import "net/http"
+import "strings"
+import "sync"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+// Runs part of user threads assigned by the coordinator and sends it
+// measurements and errors
+type Worker struct {
+	url        string
+	client     *http.Client
+	assignment Assignment
+	pending    Batch
+	unsent     *Batch // batch sent last time without success, it is resent with the same sequence
+	unsentPath string
+	sequence   int
+	finished   bool
+	lock       sync.Mutex
+	stop       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// Register with coordinator on given URL (e.g. "http://coordinator:8080"),
+// retrying for a while so workers can be started before the coordinator
+func Register(url string, retryFor time.Duration) (*Worker, error) {
+	w := &Worker{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: time.Minute},
+		stop:   make(chan struct{}),
+	}
+
+	deadline := time.Now().Add(retryFor)
+	for {
+		err := post(w.client, w.url+RegisterPath, struct{}{}, &w.assignment)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Failed to register with coordinator %s: %v", url, err)
+		}
+		logging.Logger.Debug("Coordinator %s not ready: %v", url, err)
+		time.Sleep(5 * time.Second)
+	}
+
+	w.pending = w.newBatch()
+	return w, nil
+}
+
+// User threads assigned to this worker
+func (w *Worker) Assignment() Assignment {
+	return w.assignment
+}
+
+// Collect measurements and errors and send them to coordinator every
+// interval, even when there is nothing to send so coordinator knows the
+// worker is alive. Has to be called before logging.MeasurementsStart.
+func (w *Worker) Start(interval time.Duration) {
+	logging.OnMeasurement(func(entry logging.MeasurementEntry) {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		w.pending.Measurements = append(w.pending.Measurements, measurementFromEntry(entry))
+	})
+	logging.OnError(func(entry logging.ErrorEntry) {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		w.pending.Errors = append(w.pending.Errors, entry)
+	})
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.send(BatchPath); err != nil {
+					logging.Logger.Warning("Failed to send measurements to coordinator, will retry: %v", err)
+				}
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (w *Worker) newBatch() Batch {
+	return Batch{Worker: w.assignment.Worker, Generation: w.assignment.Generation}
+}
+
+// Send pending measurements and errors. Batch that failed to be sent is sent
+// again with the same sequence (and to the same path) first, so coordinator
+// can drop it if it already got it and just the response was lost.
+func (w *Worker) send(path string) error {
+	w.lock.Lock()
+	if w.unsent == nil {
+		w.sequence++
+		batch := w.pending
+		batch.Sequence = w.sequence
+		w.pending = w.newBatch()
+		w.unsent = &batch
+		w.unsentPath = path
+	}
+	batch := *w.unsent
+	path = w.unsentPath
+	w.lock.Unlock()
+
+	err := post(w.client, w.url+path, batch, nil)
+	if err == nil {
+		w.lock.Lock()
+		w.unsent = nil
+		w.finished = w.finished || path == DonePath
+		w.lock.Unlock()
+	}
+	return err
+}
+
+// Whether there are measurements or errors not sent yet
+func (w *Worker) hasPending() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.unsent != nil || len(w.pending.Measurements) > 0 || len(w.pending.Errors) > 0
+}
+
+// Send everything left and finish with the coordinator
+func (w *Worker) finish() error {
+	for w.hasPending() {
+		if err := w.send(BatchPath); err != nil {
+			return err
+		}
+	}
+	w.lock.Lock()
+	finished := w.finished
+	w.lock.Unlock()
+	if finished {
+		return nil
+	}
+	return w.send(DonePath)
+}
+
+// Stop periodic sending and send everything left to coordinator, call after logging.MeasurementsStop
+func (w *Worker) Stop() error {
+	close(w.stop)
+	w.wg.Wait()
+
+	var err error
+	for attempt := 1; attempt <= 5; attempt++ {
+		if err = w.finish(); err == nil {
+			return nil
+		}
+		logging.Logger.Warning("Failed to finish with coordinator (attempt %d): %v", attempt, err)
+		time.Sleep(5 * time.Second)
+	}
+	return err
+}
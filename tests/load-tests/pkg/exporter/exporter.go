@@ -0,0 +1,95 @@
+package exporter
+
+import "context"
+import "errors"
+import "net/http"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import stats "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/stats"
+
+import prometheus "github.com/prometheus/client_golang/prometheus"
+import promhttp "github.com/prometheus/client_golang/prometheus/promhttp"
+
+// Returns number of running user, per application and per component journey threads
+type ActiveThreadsFunc func() (users, applications, components int)
+
+// Exposes load test measurements in Prometheus format
+type Exporter struct {
+	Registry  *prometheus.Registry
+	durations *prometheus.HistogramVec
+	counts    *prometheus.CounterVec
+	server    *http.Server
+}
+
+// Create exporter with its own registry, metrics are not served until Start is called
+func New(activeThreads ActiveThreadsFunc) *Exporter {
+	e := &Exporter{
+		Registry: prometheus.NewRegistry(),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "loadtest_measurement_duration_seconds",
+			Help:    "Duration of measured load test functions",
+			Buckets: stats.HistogramBuckets,
+		}, []string{"function", "outcome"}),
+		counts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loadtest_measurements_total",
+			Help: "Number of calls of measured load test functions",
+		}, []string{"function", "outcome"}),
+	}
+	e.Registry.MustRegister(e.durations, e.counts)
+
+	levels := map[string]func() int{
+		"user":        func() int { u, _, _ := activeThreads(); return u },
+		"application": func() int { _, a, _ := activeThreads(); return a },
+		"component":   func() int { _, _, c := activeThreads(); return c },
+	}
+	for level, fn := range levels {
+		fn := fn
+		e.Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "loadtest_active_threads",
+			Help:        "Number of running journey threads",
+			ConstLabels: prometheus.Labels{"level": level},
+		}, func() float64 { return float64(fn()) }))
+	}
+
+	return e
+}
+
+// Record one measurement
+func (e *Exporter) Observe(entry logging.MeasurementEntry) {
+	outcome := "pass"
+	if entry.Error != nil {
+		outcome = "fail"
+	}
+	function := stats.ShortMetricName(entry.Metric)
+	e.durations.WithLabelValues(function, outcome).Observe(entry.Duration.Seconds())
+	e.counts.WithLabelValues(function, outcome).Inc()
+}
+
+// Serve metrics on "/metrics" of given address (e.g. ":9090") and record all measurements
+func (e *Exporter) Start(address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(e.Registry, promhttp.HandlerOpts{}))
+	e.server = &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	logging.OnMeasurement(e.Observe)
+
+	go func() {
+		logging.Logger.Info("Serving metrics on %s/metrics", address)
+		if err := e.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Logger.Error("Metrics server failed: %v", err)
+		}
+	}()
+}
+
+// Stop serving metrics
+func (e *Exporter) Stop() {
+	if e.server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.server.Shutdown(ctx); err != nil {
+		logging.Logger.Error("Failed to stop metrics server: %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package exporter
+
+import "errors"
+import "strings"
+import "testing"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+import testutil "github.com/prometheus/client_golang/prometheus/testutil"
+import "github.com/stretchr/testify/assert"
+
+func TestExporter(t *testing.T) {
+	e := New(func() (int, int, int) { return 3, 2, 1 })
+	e.Observe(logging.MeasurementEntry{Metric: "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/journey.HandleUser", Duration: 2 * time.Second})
+	e.Observe(logging.MeasurementEntry{Metric: "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/journey.HandleUser", Duration: time.Second, Error: errors.New("failed")})
+
+	expected := `
+# HELP loadtest_measurements_total Number of calls of measured load test functions
+# TYPE loadtest_measurements_total counter
+loadtest_measurements_total{function="journey.HandleUser",outcome="fail"} 1
+loadtest_measurements_total{function="journey.HandleUser",outcome="pass"} 1
+# HELP loadtest_active_threads Number of running journey threads
+# TYPE loadtest_active_threads gauge
+loadtest_active_threads{level="application"} 2
+loadtest_active_threads{level="component"} 1
+loadtest_active_threads{level="user"} 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(e.Registry, strings.NewReader(expected), "loadtest_measurements_total", "loadtest_active_threads"))
+	assert.Equal(t, 2, testutil.CollectAndCount(e.durations))
+}
@@ -0,0 +1,163 @@
+package failures
+
+import "context"
+import "errors"
+import "fmt"
+import "net/http"
+import "regexp"
+import "strings"
+
+import clienterrors "github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+
+import github "github.com/google/go-github/v44/github"
+import k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+import wait "k8s.io/apimachinery/pkg/util/wait"
+
+// Sources of underlying errors
+const (
+	SourceKubernetes = "kubernetes"
+	SourceGitHub     = "github"
+	SourceTekton     = "tekton"
+	SourceTimeout    = "timeout"
+	SourceOther      = "other"
+)
+
+// Classified underlying error of a failure
+type Cause struct {
+	Source   string
+	Reason   string
+	Resource string // example resource the error is about, if known
+}
+
+// Cause as one string used to group failures, e.g. "tekton: task build-container failed"
+func (c Cause) String() string {
+	if c.Reason == "" {
+		return c.Source
+	}
+	return fmt.Sprintf("%s: %s", c.Source, c.Reason)
+}
+
+// Messages of errors that lost their type by being formatted with "%v",
+// checked in order when the error can not be classified by its type
+var messagePatterns = []struct {
+	pattern *regexp.Regexp
+	source  string
+	reason  string
+}{
+	{regexp.MustCompile(`(?i)timed out|timeout|context deadline exceeded`), SourceTimeout, "timed out"},
+	{regexp.MustCompile(`(?i)api rate limit|secondary rate limit|abuse`), SourceGitHub, "RateLimit"},
+	{regexp.MustCompile(`(?i)github\.com.*: (\d{3})`), SourceGitHub, "HTTP $1"},
+	{regexp.MustCompile(`PipelineRun (\S+|for .+?) failed`), SourceTekton, "PipelineRun failed"},
+	{regexp.MustCompile(`(?i)too many requests|rate limiter`), SourceKubernetes, "TooManyRequests"},
+	{regexp.MustCompile(`(?i)already exists`), SourceKubernetes, "AlreadyExists"},
+	{regexp.MustCompile(`(?i)forbidden`), SourceKubernetes, "Forbidden"},
+	{regexp.MustCompile(`(?i)unauthorized`), SourceKubernetes, "Unauthorized"},
+	{regexp.MustCompile(`(?i)the object has been modified`), SourceKubernetes, "Conflict"},
+	{regexp.MustCompile(`(?i)not found`), SourceKubernetes, "NotFound"},
+	{regexp.MustCompile(`(?i)connection refused|connection reset|no such host|EOF`), SourceKubernetes, "ConnectionError"},
+}
+
+// Classify underlying error of a failure by its type, or by its message
+// when the type was lost
+func Classify(err error) Cause {
+	if err == nil {
+		return Cause{Source: SourceOther}
+	}
+
+	var pipelineFailed *clienterrors.PipelineFailedError
+	if errors.As(err, &pipelineFailed) {
+		cause := Cause{Source: SourceTekton, Reason: "PipelineRun failed", Resource: pipelineFailed.Namespace + "/" + pipelineFailed.Name}
+		if pipelineFailed.FailedTaskRun != "" {
+			task := strings.TrimPrefix(pipelineFailed.FailedTaskRun, pipelineFailed.Name+"-")
+			cause.Reason = fmt.Sprintf("task %s failed", task)
+		} else if pipelineFailed.Reason != "" {
+			cause.Reason = pipelineFailed.Reason
+		}
+		return cause
+	}
+
+	var timeout *clienterrors.TimeoutError
+	if errors.As(err, &timeout) {
+		cause := Cause{Source: SourceTimeout, Reason: "timed out", Resource: timeout.Operation}
+		if clienterrors.IsNotFound(err) {
+			cause.Reason = "timed out, resource not found"
+		}
+		return cause
+	}
+	if errors.Is(err, context.DeadlineExceeded) || wait.Interrupted(err) {
+		return Cause{Source: SourceTimeout, Reason: "timed out"}
+	}
+
+	var notFound *clienterrors.NotFoundError
+	if errors.As(err, &notFound) {
+		return Cause{Source: SourceKubernetes, Reason: notFound.Kind + " NotFound", Resource: joinResource(notFound.Namespace, notFound.Name)}
+	}
+
+	var apiStatus k8sErrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		status := apiStatus.Status()
+		cause := Cause{Source: SourceKubernetes, Reason: string(status.Reason)}
+		if cause.Reason == "" {
+			cause.Reason = fmt.Sprintf("HTTP %d", status.Code)
+		}
+		if status.Details != nil && status.Details.Name != "" {
+			cause.Resource = strings.TrimPrefix(status.Details.Kind+"/"+status.Details.Name, "/")
+		}
+		return cause
+	}
+
+	var rateLimit *github.RateLimitError
+	if errors.As(err, &rateLimit) {
+		return Cause{Source: SourceGitHub, Reason: "RateLimit", Resource: requestPath(rateLimit.Response)}
+	}
+	var abuseRateLimit *github.AbuseRateLimitError
+	if errors.As(err, &abuseRateLimit) {
+		return Cause{Source: SourceGitHub, Reason: "RateLimit", Resource: requestPath(abuseRateLimit.Response)}
+	}
+	var githubError *github.ErrorResponse
+	if errors.As(err, &githubError) {
+		cause := Cause{Source: SourceGitHub, Resource: requestPath(githubError.Response)}
+		if githubError.Response != nil {
+			cause.Reason = fmt.Sprintf("HTTP %d", githubError.Response.StatusCode)
+		}
+		return cause
+	}
+
+	var precondition *clienterrors.PreconditionError
+	if errors.As(err, &precondition) {
+		return Cause{Source: SourceOther, Reason: "precondition not met"}
+	}
+
+	message := err.Error()
+	for _, p := range messagePatterns {
+		if match := p.pattern.FindStringSubmatchIndex(message); match != nil {
+			reason := string(p.pattern.ExpandString(nil, p.reason, message, match))
+			return Cause{Source: p.source, Reason: reason}
+		}
+	}
+	return Cause{Source: SourceOther, Reason: "unclassified"}
+}
+
+// Classify first error among parameters of a failure message
+func ClassifyParams(params ...interface{}) Cause {
+	for _, param := range params {
+		if err, ok := param.(error); ok {
+			return Classify(err)
+		}
+	}
+	return Cause{Source: SourceOther, Reason: "no underlying error"}
+}
+
+func joinResource(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+func requestPath(resp *http.Response) string {
+	if resp == nil || resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+	return resp.Request.URL.Path
+}
@@ -0,0 +1,121 @@
+package failures
+
+import "fmt"
+
+// Categories of journey steps failures belong to
+const (
+	CategoryUser            = "user provisioning"
+	CategoryRepoForking     = "repo forking"
+	CategoryApplication     = "application"
+	CategoryTestScenario    = "integration test scenario"
+	CategoryComponent       = "component creation"
+	CategoryPullRequest     = "PaC pull request"
+	CategoryBuild           = "build"
+	CategorySnapshot        = "snapshot"
+	CategoryIntegrationTest = "integration test"
+	CategoryRelease         = "release"
+	CategoryCollection      = "data collection"
+	CategoryUnknown         = "unknown"
+)
+
+// Error code of a load test failure as written to load-test-errors.csv
+type Code int
+
+// Load test failure codes, keep them unique and documented in Registry
+const (
+	UserProvisioning             Code = 10
+	UserFrameworkForComponent    Code = 11
+	UserFrameworkForApplication  Code = 12
+	RepoForking                  Code = 20
+	ApplicationCreation          Code = 30
+	ApplicationValidation        Code = 31
+	TestScenarioCreation         Code = 40
+	TestScenarioValidation       Code = 41
+	ComponentCreation            Code = 60
+	ComponentValidation          Code = 61
+	PullRequestNumber            Code = 62
+	PullRequestCleanup           Code = 63
+	ImagePullSecrets             Code = 64
+	BuildPipelineRunCreation     Code = 70
+	BuildPipelineRunFailed       Code = 71
+	BuildPipelineRunSigning      Code = 72
+	PersistentVolumeCollection   Code = 75
+	PersistentVolumeLookup       Code = 76
+	SnapshotCreation             Code = 80
+	SnapshotName                 Code = 81
+	TestPipelineRunCreation      Code = 82
+	TestPipelineRunFailed        Code = 83
+	ReleaseNamespaceCreation     Code = 90
+	ReleasePlanCreation          Code = 91
+	ReleasePlanAdmissionCreation Code = 92
+	ReleasePlanValidation        Code = 93
+	ReleasePlanMissing           Code = 94
+	ReleaseCreation              Code = 95
+	ReleasePipelineRunCreation   Code = 96
+	ReleasePipelineRunFailed     Code = 97
+	ReleaseFailed                Code = 98
+	CollectionDirectory          Code = 100
+	PodLogsCollection            Code = 101
+	ResourcesCollection          Code = 102
+)
+
+// Description of failure code
+type Definition struct {
+	Name        string
+	Category    string
+	Description string
+}
+
+// All known failure codes
+var Registry = map[Code]Definition{
+	UserProvisioning:             {"UserProvisioning", CategoryUser, "user could not be created or its framework initialized"},
+	UserFrameworkForComponent:    {"UserFrameworkForComponent", CategoryUser, "framework for per component thread could not be initialized"},
+	UserFrameworkForApplication:  {"UserFrameworkForApplication", CategoryUser, "framework for per application thread could not be initialized"},
+	RepoForking:                  {"RepoForking", CategoryRepoForking, "component repository could not be forked for the user"},
+	ApplicationCreation:          {"ApplicationCreation", CategoryApplication, "Application could not be created"},
+	ApplicationValidation:        {"ApplicationValidation", CategoryApplication, "Application did not become ready"},
+	TestScenarioCreation:         {"TestScenarioCreation", CategoryTestScenario, "IntegrationTestScenario could not be created"},
+	TestScenarioValidation:       {"TestScenarioValidation", CategoryTestScenario, "IntegrationTestScenario did not become ready"},
+	ComponentCreation:            {"ComponentCreation", CategoryComponent, "Component could not be created"},
+	ComponentValidation:          {"ComponentValidation", CategoryComponent, "Component did not get PaC pull request or become ready"},
+	PullRequestNumber:            {"PullRequestNumber", CategoryPullRequest, "PaC pull request number could not be determined"},
+	PullRequestCleanup:           {"PullRequestCleanup", CategoryPullRequest, "PaC pull request could not be merged or its pipelines cleaned up with repo templating"},
+	ImagePullSecrets:             {"ImagePullSecrets", CategoryComponent, "pipeline service account could not be configured with image pull secrets"},
+	BuildPipelineRunCreation:     {"BuildPipelineRunCreation", CategoryBuild, "build PipelineRun was not created"},
+	BuildPipelineRunFailed:       {"BuildPipelineRunFailed", CategoryBuild, "build PipelineRun failed or did not finish"},
+	BuildPipelineRunSigning:      {"BuildPipelineRunSigning", CategoryBuild, "build PipelineRun was not signed by Chains"},
+	PersistentVolumeCollection:   {"PersistentVolumeCollection", CategoryCollection, "PersistentVolumeClaims could not be collected"},
+	PersistentVolumeLookup:       {"PersistentVolumeLookup", CategoryCollection, "PersistentVolume of a claim could not be read"},
+	SnapshotCreation:             {"SnapshotCreation", CategorySnapshot, "Snapshot was not created after build"},
+	SnapshotName:                 {"SnapshotName", CategorySnapshot, "Snapshot name could not be determined"},
+	TestPipelineRunCreation:      {"TestPipelineRunCreation", CategoryIntegrationTest, "integration test PipelineRun was not created"},
+	TestPipelineRunFailed:        {"TestPipelineRunFailed", CategoryIntegrationTest, "integration test PipelineRun failed or did not finish"},
+	ReleaseNamespaceCreation:     {"ReleaseNamespaceCreation", CategoryRelease, "managed namespace for release could not be prepared"},
+	ReleasePlanCreation:          {"ReleasePlanCreation", CategoryRelease, "ReleasePlan could not be created"},
+	ReleasePlanAdmissionCreation: {"ReleasePlanAdmissionCreation", CategoryRelease, "ReleasePlanAdmission could not be created"},
+	ReleasePlanValidation:        {"ReleasePlanValidation", CategoryRelease, "ReleasePlan was not matched with ReleasePlanAdmission"},
+	ReleasePlanMissing:           {"ReleasePlanMissing", CategoryRelease, "ReleasePlan of the application was not created before release"},
+	ReleaseCreation:              {"ReleaseCreation", CategoryRelease, "Release could not be created"},
+	ReleasePipelineRunCreation:   {"ReleasePipelineRunCreation", CategoryRelease, "release PipelineRun was not created"},
+	ReleasePipelineRunFailed:     {"ReleasePipelineRunFailed", CategoryRelease, "release PipelineRun failed or did not finish"},
+	ReleaseFailed:                {"ReleaseFailed", CategoryRelease, "Release did not succeed"},
+	CollectionDirectory:          {"CollectionDirectory", CategoryCollection, "directory for collected data could not be created"},
+	PodLogsCollection:            {"PodLogsCollection", CategoryCollection, "pod logs could not be collected"},
+	ResourcesCollection:          {"ResourcesCollection", CategoryCollection, "resource JSONs could not be collected"},
+}
+
+// Name of the code, e.g. "BuildPipelineRunFailed"
+func (c Code) String() string {
+	if def, ok := Registry[c]; ok {
+		return def.Name
+	}
+	return fmt.Sprintf("Code%d", int(c))
+}
+
+// Category of journey step the code belongs to
+func (c Code) Category() string {
+	if def, ok := Registry[c]; ok {
+		return def.Category
+	}
+	return CategoryUnknown
+}
@@ -0,0 +1,146 @@
+package failures
+
+import "context"
+import "errors"
+import "fmt"
+import "net/http"
+import "net/url"
+import "strings"
+import "testing"
+import "time"
+
+import clienterrors "github.com/konflux-ci/e2e-tests/pkg/clients/clienterrors"
+
+import "github.com/stretchr/testify/assert"
+
+import github "github.com/google/go-github/v44/github"
+import k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+import schema "k8s.io/apimachinery/pkg/runtime/schema"
+import corev1 "k8s.io/api/core/v1"
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+import apis "knative.dev/pkg/apis"
+
+func TestRegistry(t *testing.T) {
+	names := map[string]bool{}
+	for code, def := range Registry {
+		assert.NotEmpty(t, def.Name, code)
+		assert.NotEmpty(t, def.Category, code)
+		assert.NotEmpty(t, def.Description, code)
+		assert.False(t, names[def.Name], "duplicate name %s", def.Name)
+		names[def.Name] = true
+	}
+	assert.Equal(t, "BuildPipelineRunFailed", BuildPipelineRunFailed.String())
+	assert.Equal(t, CategoryBuild, BuildPipelineRunFailed.Category())
+	assert.Equal(t, "Code7", Code(7).String())
+	assert.Equal(t, CategoryUnknown, Code(7).Category())
+}
+
+func TestClassify(t *testing.T) {
+	prURL, _ := url.Parse("https://api.github.com/repos/org/repo/forks")
+	githubResponse := &http.Response{StatusCode: 404, Request: &http.Request{URL: prURL}}
+
+	tests := []struct {
+		err   error
+		cause Cause
+	}{
+		{
+			fmt.Errorf("build failed: %w", &clienterrors.PipelineFailedError{Namespace: "ns", Name: "comp-on-push-abcde", FailedTaskRun: "comp-on-push-abcde-build-container"}),
+			Cause{Source: SourceTekton, Reason: "task build-container failed", Resource: "ns/comp-on-push-abcde"},
+		},
+		{
+			&clienterrors.PipelineFailedError{Namespace: "ns", Name: "pr", Reason: "PipelineRunTimeout"},
+			Cause{Source: SourceTekton, Reason: "PipelineRunTimeout", Resource: "ns/pr"},
+		},
+		{
+			clienterrors.Timeout("PipelineRun of Component ns/comp to finish", time.Minute, "", clienterrors.NotFound("PipelineRun", "ns", "comp", "")),
+			Cause{Source: SourceTimeout, Reason: "timed out, resource not found", Resource: "PipelineRun of Component ns/comp to finish"},
+		},
+		{
+			fmt.Errorf("waiting: %w", context.DeadlineExceeded),
+			Cause{Source: SourceTimeout, Reason: "timed out"},
+		},
+		{
+			clienterrors.NotFound("Snapshot", "ns", "snap", ""),
+			Cause{Source: SourceKubernetes, Reason: "Snapshot NotFound", Resource: "ns/snap"},
+		},
+		{
+			k8sErrors.NewAlreadyExists(schema.GroupResource{Resource: "applications"}, "app-1"),
+			Cause{Source: SourceKubernetes, Reason: "AlreadyExists", Resource: "applications/app-1"},
+		},
+		{
+			k8sErrors.NewTooManyRequests("slow down", 1),
+			Cause{Source: SourceKubernetes, Reason: "TooManyRequests"},
+		},
+		{
+			&github.ErrorResponse{Response: githubResponse, Message: "Not Found"},
+			Cause{Source: SourceGitHub, Reason: "HTTP 404", Resource: "/repos/org/repo/forks"},
+		},
+		{
+			&github.RateLimitError{Response: githubResponse},
+			Cause{Source: SourceGitHub, Reason: "RateLimit", Resource: "/repos/org/repo/forks"},
+		},
+		{
+			errors.New("Unable to create the Component comp: components.appstudio.redhat.com \"comp\" already exists"),
+			Cause{Source: SourceKubernetes, Reason: "AlreadyExists"},
+		},
+		{
+			errors.New("Unable to fork: API rate limit exceeded for user"),
+			Cause{Source: SourceGitHub, Reason: "RateLimit"},
+		},
+		{
+			errors.New("something odd"),
+			Cause{Source: SourceOther, Reason: "unclassified"},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.cause, Classify(test.err), test.err.Error())
+	}
+
+	assert.Equal(t, "tekton: task build-container failed", Classify(tests[0].err).String())
+
+	// Build PipelineRun failure as reported by HandlePipelineRun
+	pr := &pipeline.PipelineRun{ObjectMeta: metav1.ObjectMeta{Namespace: "user-0001-tenant", Name: "comp-on-push-x7k2p"}}
+	pr.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Failed", Message: "Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 2"})
+	failure := clienterrors.PipelineFailed(pr)
+	failure.FailedTaskRun = "comp-on-push-x7k2p-build-container"
+	cause := ClassifyParams(failure)
+	assert.Equal(t, "tekton: task build-container failed", cause.String())
+	assert.Equal(t, "user-0001-tenant/comp-on-push-x7k2p", cause.Resource)
+
+	// The same failure once formatted into FAIL message, and the message of older load tests
+	message := fmt.Sprintf("FAIL(%d): Build Pipeline Run failed run: %v", BuildPipelineRunFailed, failure)
+	assert.Equal(t, Cause{Source: SourceTekton, Reason: "PipelineRun failed"}, Classify(errors.New(message)))
+	message = "PipelineRun for component comp in namespace user-0001-tenant failed: &{Type:Succeeded Status:False Severity: LastTransitionTime:{Inner:2024-05-01 10:00:00 +0000 UTC} Reason:Failed Message:Tasks Completed: 3 (Failed: 1, Cancelled 0), Skipped: 2}"
+	assert.Equal(t, Cause{Source: SourceTekton, Reason: "PipelineRun failed"}, Classify(errors.New(message)))
+	assert.Equal(t, Cause{Source: SourceOther, Reason: "no underlying error"}, ClassifyParams("comp", 5))
+	assert.Equal(t, SourceTimeout, ClassifyParams("comp", context.DeadlineExceeded).Source)
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	assert.Equal(t, 0, c.Summary().Total)
+
+	for i := 0; i < 5; i++ {
+		c.Add(BuildPipelineRunFailed, "tekton: task build-container failed", fmt.Sprintf("ns/pr-%d", i))
+	}
+	c.Add(BuildPipelineRunFailed, "timeout: timed out", "")
+	c.Add(RepoForking, "github: RateLimit", "/repos/org/repo/forks")
+	c.Add(RepoForking, "github: RateLimit", "/repos/org/repo/forks")
+
+	s := c.Summary()
+	assert.Equal(t, 8, s.Total)
+	assert.Equal(t, map[string]int{CategoryBuild: 6, CategoryRepoForking: 2}, s.Categories)
+	assert.Equal(t, BuildPipelineRunFailed, s.Codes[0].Code)
+	assert.Equal(t, 6, s.Codes[0].Count)
+	assert.Equal(t, "tekton: task build-container failed", s.Codes[0].Causes[0].Cause)
+	assert.Equal(t, []string{"ns/pr-0", "ns/pr-1", "ns/pr-2"}, s.Codes[0].Causes[0].Examples)
+	assert.Equal(t, []string{"/repos/org/repo/forks"}, s.TopCauses[1].Examples)
+	assert.Len(t, s.TopCauses, 3)
+
+	out := &strings.Builder{}
+	assert.NoError(t, s.Write(out))
+	assert.Contains(t, out.String(), "8 failures")
+	assert.Contains(t, out.String(), "BuildPipelineRunFailed")
+	assert.Contains(t, out.String(), "github: RateLimit")
+}
@@ -0,0 +1,146 @@
+package failures
+
+import "fmt"
+import "io"
+import "sort"
+import "sync"
+import "text/tabwriter"
+
+// How many causes and example resources to keep in the summary
+const (
+	TopCauses       = 10
+	ExamplesPerItem = 3
+)
+
+// Number of failures with the same cause
+type CauseStats struct {
+	Cause    string   `json:"cause"`
+	Count    int      `json:"count"`
+	Examples []string `json:"examples,omitempty"` // resources the cause was seen with
+}
+
+// Number of failures with the same code
+type CodeStats struct {
+	Code     Code         `json:"code"`
+	Name     string       `json:"name"`
+	Category string       `json:"category"`
+	Count    int          `json:"count"`
+	Causes   []CauseStats `json:"causes"`
+}
+
+// Failures of the run grouped by category, code and cause, part of load-test-summary.json
+type Summary struct {
+	Total      int            `json:"total"`
+	Categories map[string]int `json:"categories"`
+	Codes      []CodeStats    `json:"codes"`
+	TopCauses  []CauseStats   `json:"top_causes"`
+}
+
+type causeRecord struct {
+	count    int
+	examples []string
+}
+
+func (r *causeRecord) add(resource string) {
+	r.count++
+	if resource == "" || len(r.examples) >= ExamplesPerItem {
+		return
+	}
+	for _, example := range r.examples {
+		if example == resource {
+			return
+		}
+	}
+	r.examples = append(r.examples, resource)
+}
+
+// Collects failures as they happen
+type Collector struct {
+	mu     sync.Mutex
+	codes  map[Code]map[string]*causeRecord
+	causes map[string]*causeRecord
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		codes:  map[Code]map[string]*causeRecord{},
+		causes: map[string]*causeRecord{},
+	}
+}
+
+// Record one failure with given code and cause (Cause.String()) seen with given resource
+func (c *Collector) Add(code Code, cause, resource string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.codes[code] == nil {
+		c.codes[code] = map[string]*causeRecord{}
+	}
+	for _, records := range []map[string]*causeRecord{c.codes[code], c.causes} {
+		if records[cause] == nil {
+			records[cause] = &causeRecord{}
+		}
+		records[cause].add(resource)
+	}
+}
+
+func sortedCauses(records map[string]*causeRecord, limit int) []CauseStats {
+	causes := []CauseStats{}
+	for cause, record := range records {
+		causes = append(causes, CauseStats{Cause: cause, Count: record.count, Examples: append([]string{}, record.examples...)})
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if causes[i].Count != causes[j].Count {
+			return causes[i].Count > causes[j].Count
+		}
+		return causes[i].Cause < causes[j].Cause
+	})
+	if limit > 0 && len(causes) > limit {
+		causes = causes[:limit]
+	}
+	return causes
+}
+
+// Summary of failures collected so far
+func (c *Collector) Summary() *Summary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &Summary{Categories: map[string]int{}, Codes: []CodeStats{}}
+	for code, records := range c.codes {
+		stats := CodeStats{Code: code, Name: code.String(), Category: code.Category(), Causes: sortedCauses(records, 0)}
+		for _, record := range records {
+			stats.Count += record.count
+		}
+		s.Codes = append(s.Codes, stats)
+		s.Categories[stats.Category] += stats.Count
+		s.Total += stats.Count
+	}
+	sort.Slice(s.Codes, func(i, j int) bool {
+		if s.Codes[i].Count != s.Codes[j].Count {
+			return s.Codes[i].Count > s.Codes[j].Count
+		}
+		return s.Codes[i].Code < s.Codes[j].Code
+	})
+	s.TopCauses = sortedCauses(c.causes, TopCauses)
+	return s
+}
+
+// Write human readable failure summary
+func (s *Summary) Write(out io.Writer) error {
+	fmt.Fprintf(out, "%d failures\n", s.Total)
+	if s.Total == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\nCODE\tNAME\tCATEGORY\tCOUNT\tTOP CAUSE")
+	for _, c := range s.Codes {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\n", c.Code, c.Name, c.Category, c.Count, c.Causes[0].Cause)
+	}
+	fmt.Fprintln(w, "\nCAUSE\tCOUNT\tEXAMPLES")
+	for _, c := range s.TopCauses {
+		fmt.Fprintf(w, "%s\t%d\t%v\n", c.Cause, c.Count, c.Examples)
+	}
+	return w.Flush()
+}
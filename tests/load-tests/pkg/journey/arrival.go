@@ -0,0 +1,103 @@
+package journey
+
+import "fmt"
+import "strconv"
+import "sync"
+import "time"
+
+import options "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/options"
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import loadtestutils "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/loadtestutils"
+
+// Name of the measurement of how late was user journey started compared to its schedule
+const ArrivalStartLagMetric = "journey.ArrivalStartLag"
+
+// GitHub do not allow more than 3 running forks in parallel, so fork one by one
+var forkingLock sync.Mutex
+
+// Start user journey threads according to the arrival profile (open model)
+// instead of fixed number of threads. At most '--max-in-flight' journeys run
+// at once (unlimited when 0), when reached, new arrivals wait for a free slot
+// and their start lag grows, which shows the saturation point.
+func SetupArrivals(fn func(*MainContext), opts *options.Opts) (string, error) {
+	schedule := opts.Arrival().Schedule()
+	logging.Logger.Info("Scheduled %d user journeys with %s arrival profile", len(schedule), opts.ArrivalProfile)
+
+	var stageUsers []loadtestutils.User
+	var err error
+	if opts.Stage {
+		stageUsers, err = loadtestutils.LoadStageUsers("users.json")
+		if err != nil {
+			logging.Logger.Fatal("Failed to load Stage users: %v", err)
+		}
+		if len(stageUsers) < len(schedule) {
+			return "", fmt.Errorf("Arrival profile schedules %d user journeys, but there are only %d Stage users", len(schedule), len(stageUsers))
+		}
+	}
+
+	var inFlight chan struct{}
+	if opts.MaxInFlight > 0 {
+		inFlight = make(chan struct{}, opts.MaxInFlight)
+	}
+
+	threadsWG := &sync.WaitGroup{}
+	start := time.Now()
+
+	for threadIndex, offset := range schedule {
+		scheduled := start.Add(offset)
+		time.Sleep(time.Until(scheduled))
+		if inFlight != nil {
+			inFlight <- struct{}{}
+		}
+		lag := time.Since(scheduled)
+
+		logging.Logger.Info("Initiating thread %d with start lag %s", threadIndex, lag)
+		logging.LogMeasurement(ArrivalStartLagMetric, map[string]string{"threadIndex": strconv.Itoa(threadIndex), "scheduled": offset.String()}, lag, "", nil)
+
+		threadCtx := &MainContext{
+			ThreadsWG:   threadsWG,
+			ThreadIndex: threadIndex,
+			Opts:        opts,
+			StageUsers:  &stageUsers,
+			Username:    "",
+			Namespace:   "",
+		}
+		MainContexts = append(MainContexts, threadCtx)
+
+		threadsWG.Add(1)
+		go func() {
+			if inFlight != nil {
+				defer func() { <-inFlight }()
+			}
+			runActive(&activeUserThreads, func() { arrivalThread(fn, threadCtx) })
+		}()
+	}
+
+	threadsWG.Wait()
+
+	return "", nil
+}
+
+// Create user and fork repository for the journey thread, then run the journey
+func arrivalThread(fn func(*MainContext), threadCtx *MainContext) {
+	var err error
+
+	_, err = logging.Measure(HandleUser, threadCtx)
+	if err != nil {
+		logging.Logger.Error("Thread failed: %v", err)
+		threadCtx.ThreadsWG.Done()
+		return
+	}
+
+	forkingLock.Lock()
+	_, err = logging.Measure(HandleRepoForking, threadCtx)
+	forkingLock.Unlock()
+	if err != nil {
+		logging.Logger.Error("Thread failed: %v", err)
+		threadCtx.ThreadsWG.Done()
+		return
+	}
+
+	// Journey function marks the thread done
+	fn(threadCtx)
+}
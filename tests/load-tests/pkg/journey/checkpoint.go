@@ -0,0 +1,302 @@
+package journey
+
+import "encoding/json"
+import "fmt"
+import "os"
+import "path/filepath"
+import "sort"
+import "sync"
+import "sync/atomic"
+import "time"
+
+import options "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/options"
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import loadtestutils "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/loadtestutils"
+
+// Version of the load-test-checkpoint.json format, bump it when making incompatible changes
+const CheckpointVersion = 1
+
+// Name of the checkpoint file in the output directory
+const CheckpointFile = "load-test-checkpoint.json"
+
+// Component created by the journey
+type ComponentCheckpoint struct {
+	Name               string `json:"name"`
+	SnapshotName       string `json:"snapshot_name,omitempty"`
+	ReleaseName        string `json:"release_name,omitempty"`
+	MergeRequestNumber int    `json:"merge_request_number,omitempty"`
+}
+
+// Application created by the journey
+type ApplicationCheckpoint struct {
+	Name                        string                 `json:"name"`
+	IntegrationTestScenarioName string                 `json:"integration_test_scenario_name,omitempty"`
+	ReleasePlanName             string                 `json:"release_plan_name,omitempty"`
+	ReleaseManagedNamespace     string                 `json:"release_managed_namespace,omitempty"`
+	Components                  []*ComponentCheckpoint `json:"components"`
+}
+
+// User journey thread
+type UserCheckpoint struct {
+	ThreadIndex         int                      `json:"thread_index"`
+	Username            string                   `json:"username"`
+	Namespace           string                   `json:"namespace"`
+	ComponentRepoUrl    string                   `json:"component_repo_url,omitempty"`
+	CompletedIterations int                      `json:"completed_iterations"`
+	Applications        []*ApplicationCheckpoint `json:"applications"`
+}
+
+// Content of load-test-checkpoint.json
+type Checkpoint struct {
+	Version int       `json:"version"`
+	Saved   time.Time `json:"saved"`
+	// Time left until '--journey-duration' passes when the checkpoint was saved
+	JourneyRemaining time.Duration `json:"journey_remaining"`
+	// Number of records written to load-test-timings.csv and load-test-errors.csv
+	MeasurementsOffset int `json:"measurements_offset"`
+	ErrorsOffset       int `json:"errors_offset"`
+	// Resources of the users were purged, so there is nothing left to resume
+	Purged bool              `json:"purged,omitempty"`
+	Users  []*UserCheckpoint `json:"users"`
+}
+
+// State of the run recorded by journey steps as they create resources
+var checkpoint = &Checkpoint{Version: CheckpointVersion}
+var checkpointLock sync.Mutex
+var checkpointStop chan struct{}
+var checkpointWG sync.WaitGroup
+var checkpointOpts atomic.Pointer[options.Opts] // set by StartCheckpoints, recorded changes are saved right away
+var checkpointSaveLock sync.Mutex
+
+func checkpointUser(threadIndex int) *UserCheckpoint {
+	for _, user := range checkpoint.Users {
+		if user.ThreadIndex == threadIndex {
+			return user
+		}
+	}
+	user := &UserCheckpoint{ThreadIndex: threadIndex, Applications: []*ApplicationCheckpoint{}}
+	checkpoint.Users = append(checkpoint.Users, user)
+	sort.Slice(checkpoint.Users, func(i, j int) bool { return checkpoint.Users[i].ThreadIndex < checkpoint.Users[j].ThreadIndex })
+	return user
+}
+
+func checkpointApplication(ctx *PerApplicationContext) *ApplicationCheckpoint {
+	user := checkpointUser(ctx.ParentContext.ThreadIndex)
+	for _, app := range user.Applications {
+		if app.Name == ctx.ApplicationName {
+			return app
+		}
+	}
+	app := &ApplicationCheckpoint{Name: ctx.ApplicationName, Components: []*ComponentCheckpoint{}}
+	user.Applications = append(user.Applications, app)
+	return app
+}
+
+// Record user thread state to the checkpoint
+func recordUser(ctx *MainContext) {
+	defer saveRecorded()
+	checkpointLock.Lock()
+	defer checkpointLock.Unlock()
+	user := checkpointUser(ctx.ThreadIndex)
+	user.Username = ctx.Username
+	user.Namespace = ctx.Namespace
+	user.ComponentRepoUrl = ctx.ComponentRepoUrl
+}
+
+// Record application state to the checkpoint
+func recordApplication(ctx *PerApplicationContext) {
+	defer saveRecorded()
+	checkpointLock.Lock()
+	defer checkpointLock.Unlock()
+	app := checkpointApplication(ctx)
+	app.IntegrationTestScenarioName = ctx.IntegrationTestScenarioName
+	app.ReleasePlanName = ctx.ReleasePlanName
+	app.ReleaseManagedNamespace = ctx.ReleaseManagedNamespace
+}
+
+// Record component state to the checkpoint
+func recordComponent(ctx *PerComponentContext) {
+	defer saveRecorded()
+	checkpointLock.Lock()
+	defer checkpointLock.Unlock()
+	app := checkpointApplication(ctx.ParentContext)
+	var comp *ComponentCheckpoint
+	for _, c := range app.Components {
+		if c.Name == ctx.ComponentName {
+			comp = c
+			break
+		}
+	}
+	if comp == nil {
+		comp = &ComponentCheckpoint{Name: ctx.ComponentName}
+		app.Components = append(app.Components, comp)
+	}
+	comp.SnapshotName = ctx.SnapshotName
+	comp.ReleaseName = ctx.ReleaseName
+	comp.MergeRequestNumber = ctx.MergeRequestNumber
+}
+
+// Save the checkpoint right after a resource is recorded, so resources
+// created since the last periodic save are not lost when the run is killed
+func saveRecorded() {
+	opts := checkpointOpts.Load()
+	if opts == nil {
+		return
+	}
+	if err := SaveCheckpoint(opts); err != nil {
+		logging.Logger.Error("Failed to save checkpoint: %v", err)
+	}
+}
+
+// Mark one more journey iteration of the user thread as completed, so it is not repeated on resume
+func CompleteIteration(ctx *MainContext) {
+	checkpointLock.Lock()
+	defer checkpointLock.Unlock()
+	ctx.CompletedIterations++
+	checkpointUser(ctx.ThreadIndex).CompletedIterations = ctx.CompletedIterations
+}
+
+// Write the checkpoint to the output directory
+func SaveCheckpoint(opts *options.Opts) error {
+	checkpointSaveLock.Lock()
+	defer checkpointSaveLock.Unlock()
+
+	checkpointLock.Lock()
+	checkpoint.Saved = time.Now().UTC()
+	checkpoint.JourneyRemaining = time.Until(opts.JourneyUntil)
+	checkpoint.MeasurementsOffset, checkpoint.ErrorsOffset = logging.MeasurementsOffsets()
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	checkpointLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Write to temporary file first so the checkpoint is never half written
+	path := filepath.Join(opts.OutputDir, CheckpointFile)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Load checkpoint written by SaveCheckpoint
+func LoadCheckpoint(directory string) (*Checkpoint, error) {
+	data, err := os.ReadFile(filepath.Clean(filepath.Join(directory, CheckpointFile)))
+	if err != nil {
+		return nil, err
+	}
+	loaded := &Checkpoint{}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		return nil, err
+	}
+	if loaded.Version != CheckpointVersion {
+		return nil, fmt.Errorf("Checkpoint version %d is not supported, expected %d", loaded.Version, CheckpointVersion)
+	}
+	return loaded, nil
+}
+
+// Continue recording to the loaded checkpoint and shift '--journey-duration'
+// deadline so the run takes as long as it would without the interruption
+func ResumeCheckpoint(loaded *Checkpoint, opts *options.Opts) {
+	checkpointLock.Lock()
+	defer checkpointLock.Unlock()
+	checkpoint = loaded
+	opts.JourneyUntil = time.Now().UTC().Add(loaded.JourneyRemaining)
+}
+
+// Create user thread contexts from the checkpoint, frameworks are initialized later by HandleUser
+func contextsFromCheckpoint(threadsWG *sync.WaitGroup, opts *options.Opts, stageUsers *[]loadtestutils.User) []*MainContext {
+	checkpointLock.Lock()
+	defer checkpointLock.Unlock()
+
+	contexts := []*MainContext{}
+	for _, user := range checkpoint.Users {
+		threadCtx := &MainContext{
+			ThreadsWG:           threadsWG,
+			ThreadIndex:         user.ThreadIndex,
+			CompletedIterations: user.CompletedIterations,
+			Opts:                opts,
+			StageUsers:          stageUsers,
+			Username:            user.Username,
+			Namespace:           user.Namespace,
+			ComponentRepoUrl:    user.ComponentRepoUrl,
+		}
+		for _, app := range user.Applications {
+			perApplicationCtx := &PerApplicationContext{
+				Opts:                        opts,
+				ParentContext:               threadCtx,
+				ApplicationName:             app.Name,
+				IntegrationTestScenarioName: app.IntegrationTestScenarioName,
+				ReleasePlanName:             app.ReleasePlanName,
+				ReleaseManagedNamespace:     app.ReleaseManagedNamespace,
+			}
+			for _, comp := range app.Components {
+				perApplicationCtx.PerComponentContexts = append(perApplicationCtx.PerComponentContexts, &PerComponentContext{
+					Opts:               opts,
+					ParentContext:      perApplicationCtx,
+					ComponentName:      comp.Name,
+					SnapshotName:       comp.SnapshotName,
+					ReleaseName:        comp.ReleaseName,
+					MergeRequestNumber: comp.MergeRequestNumber,
+				})
+			}
+			threadCtx.PerApplicationContexts = append(threadCtx.PerApplicationContexts, perApplicationCtx)
+		}
+		contexts = append(contexts, threadCtx)
+	}
+	return contexts
+}
+
+// Whether the user thread should not start another journey iteration
+// because it was resumed after '--journey-duration' already passed
+func IterationsDone(ctx *MainContext) bool {
+	return ctx.CompletedIterations > 0 && time.Now().UTC().After(ctx.Opts.JourneyUntil)
+}
+
+// Save the checkpoint whenever a resource is recorded and periodically
+// (unless interval is 0) until StopCheckpoints is called
+func StartCheckpoints(opts *options.Opts, interval time.Duration) {
+	checkpointOpts.Store(opts)
+	if interval <= 0 {
+		return
+	}
+	checkpointStop = make(chan struct{})
+	checkpointWG.Add(1)
+	go func() {
+		defer checkpointWG.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := SaveCheckpoint(opts); err != nil {
+					logging.Logger.Error("Failed to save checkpoint: %v", err)
+				}
+			case <-checkpointStop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop saving the checkpoint and save it for the last time, has to be
+// called before Purge so the run can be resumed when purging is interrupted
+func StopCheckpoints(opts *options.Opts) {
+	checkpointOpts.Store(nil)
+	if checkpointStop != nil {
+		close(checkpointStop)
+		checkpointWG.Wait()
+		checkpointStop = nil
+	}
+	if err := SaveCheckpoint(opts); err != nil {
+		logging.Logger.Error("Failed to save checkpoint: %v", err)
+	}
+}
+
+// Mark resources in the checkpoint as purged and save it
+func MarkPurged(opts *options.Opts) error {
+	checkpointLock.Lock()
+	checkpoint.Purged = true
+	checkpointLock.Unlock()
+	return SaveCheckpoint(opts)
+}
@@ -0,0 +1,120 @@
+package journey
+
+import "os"
+import "path/filepath"
+import "strings"
+import "sync"
+import "testing"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import options "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/options"
+
+import "github.com/stretchr/testify/assert"
+import "github.com/stretchr/testify/require"
+
+func TestCheckpointResume(t *testing.T) {
+	checkpoint = &Checkpoint{Version: CheckpointVersion}
+	dir := t.TempDir()
+	opts := &options.Opts{OutputDir: dir, JourneyUntil: time.Now().UTC().Add(time.Hour)}
+
+	userCtx := &MainContext{ThreadIndex: 1, Opts: opts, Username: "user-1", Namespace: "user-1-tenant", ComponentRepoUrl: "https://github.com/org/fork"}
+	appCtx := &PerApplicationContext{ParentContext: userCtx, ApplicationName: "app-a", IntegrationTestScenarioName: "its-a"}
+	compCtx := &PerComponentContext{ParentContext: appCtx, ComponentName: "comp-0"}
+	StartCheckpoints(opts, 0)
+	recordUser(userCtx)
+	saved, err := LoadCheckpoint(dir)
+	require.NoError(t, err)
+	require.Len(t, saved.Users, 1)
+	assert.Equal(t, "user-1", saved.Users[0].Username)
+	recordUser(&MainContext{ThreadIndex: 0, Opts: opts, Username: "user-0"})
+	recordApplication(appCtx)
+	recordComponent(compCtx)
+	compCtx.SnapshotName = "snap-0"
+	recordComponent(compCtx)
+	CompleteIteration(userCtx)
+	StopCheckpoints(opts)
+
+	loaded, err := LoadCheckpoint(dir)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour, loaded.JourneyRemaining, float64(time.Minute))
+	require.Len(t, loaded.Users, 2)
+	assert.Equal(t, "user-0", loaded.Users[0].Username)
+	assert.Equal(t, 1, loaded.Users[1].CompletedIterations)
+
+	resumedOpts := &options.Opts{OutputDir: dir}
+	ResumeCheckpoint(loaded, resumedOpts)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), resumedOpts.JourneyUntil, time.Minute)
+
+	contexts := contextsFromCheckpoint(&sync.WaitGroup{}, resumedOpts, nil)
+	require.Len(t, contexts, 2)
+	resumed := contexts[1]
+	assert.Equal(t, "https://github.com/org/fork", resumed.ComponentRepoUrl)
+	assert.Equal(t, 1, resumed.CompletedIterations)
+	assert.False(t, IterationsDone(resumed))
+	assert.Equal(t, "its-a", resumed.PerApplicationContexts[0].IntegrationTestScenarioName)
+	assert.Equal(t, "snap-0", resumed.PerApplicationContexts[0].PerComponentContexts[0].SnapshotName)
+	assert.Equal(t, "thread-1/app-a/comp-0", resumed.PerApplicationContexts[0].PerComponentContexts[0].IterationKey())
+
+	resumedOpts.JourneyUntil = time.Now().Add(-time.Minute)
+	assert.True(t, IterationsDone(resumed))
+
+	require.NoError(t, MarkPurged(resumedOpts))
+	loaded, err = LoadCheckpoint(dir)
+	require.NoError(t, err)
+	assert.True(t, loaded.Purged)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CheckpointFile), []byte(`{"version": 99}`), 0600))
+	_, err = LoadCheckpoint(dir)
+	assert.ErrorContains(t, err, "version 99 is not supported")
+}
+
+func TestPurgeResumedWithoutUsers(t *testing.T) {
+	checkpoint = &Checkpoint{Version: CheckpointVersion}
+	dir := t.TempDir()
+	opts := &options.Opts{OutputDir: dir, Purge: true, PurgeOnly: true, JourneyUntil: time.Now().UTC().Add(time.Hour)}
+	StartCheckpoints(opts, 0)
+	StopCheckpoints(opts)
+
+	loaded, err := LoadCheckpoint(dir)
+	require.NoError(t, err)
+	resumedOpts := &options.Opts{OutputDir: dir, Purge: true, PurgeOnly: true}
+	ResumeCheckpoint(loaded, resumedOpts)
+	MainContexts = contextsFromCheckpoint(&sync.WaitGroup{}, resumedOpts, nil)
+	defer func() { MainContexts = nil }()
+	assert.Empty(t, MainContexts)
+	assert.NoError(t, Purge())
+}
+
+func TestMeasurementsResume(t *testing.T) {
+	dir := t.TempDir()
+	timings := strings.Join([]string{
+		"2024-01-01T00:00:00Z,journey.HandleUser,1.000000,,<nil>,thread-0",
+		"2024-01-01T00:00:01Z,journey.HandleUser,2.000000,,failed,thread-1",
+		"2024-01-01T00:00:02Z,journey.HandleUser,3.000000,,<nil>",
+	}, "\n") + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "load-test-timings.csv"), []byte(timings), 0600))
+
+	require.NoError(t, logging.MeasurementsResume(dir, 2, 0))
+	measurements, errors := logging.MeasurementsOffsets()
+	assert.Equal(t, 2, measurements)
+	assert.Equal(t, 0, errors)
+
+	logging.LogMeasurement("journey.HandleUser", map[string]string{}, 4*time.Second, "", nil)
+	logging.MeasurementsStop()
+
+	data, err := os.ReadFile(filepath.Join(dir, "load-test-timings.csv"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[1], "2024-01-01T00:00:01Z"))
+	assert.Contains(t, lines[2], "journey.HandleUser,4.000000")
+
+	summary := logging.Summary()
+	assert.Equal(t, 3, summary.Metrics["journey.HandleUser"].Samples)
+	assert.Equal(t, 1, summary.Metrics["journey.HandleUser"].Errors)
+	assert.Equal(t, 1, summary.KPI.FailedIterations)
+	assert.Equal(t, 1, summary.KPI.IncompleteIterations)
+
+	assert.ErrorContains(t, logging.MeasurementsResume(dir, 10, 0), "has 3 records, expected at least 10")
+}
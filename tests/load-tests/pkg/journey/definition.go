@@ -0,0 +1,294 @@
+package journey
+
+import "encoding/json"
+import "fmt"
+import "os"
+import "path/filepath"
+import "reflect"
+import "time"
+
+import options "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/options"
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+
+import yaml "sigs.k8s.io/yaml"
+
+// Special steps starting per application and per component threads
+const (
+	ApplicationsStep = "applications"
+	ComponentsStep   = "components"
+)
+
+// Steps that can be used on user level of journey definition
+var UserSteps = map[string]func(*MainContext) error{
+	"HandlePersistentVolumeClaim": HandlePersistentVolumeClaim,
+}
+
+// Steps that can be used on application level of journey definition
+var ApplicationSteps = map[string]func(*PerApplicationContext) error{
+	"HandleNewFrameworkForApp":      HandleNewFrameworkForApp,
+	"HandleApplication":             HandleApplication,
+	"HandleIntegrationTestScenario": HandleIntegrationTestScenario,
+	"HandleReleaseSetup":            HandleReleaseSetup,
+}
+
+// Steps that can be used on component level of journey definition
+var ComponentSteps = map[string]func(*PerComponentContext) error{
+	"HandleNewFrameworkForComp":    HandleNewFrameworkForComp,
+	"HandleComponent":              HandleComponent,
+	"HandlePipelineRun":            HandlePipelineRun,
+	"HandleTest":                   HandleTest,
+	"HandleRelease":                HandleRelease,
+	"HandlePerComponentCollection": HandlePerComponentCollection,
+}
+
+// One step of a journey definition
+type StepDefinition struct {
+	// Name of the step, one of UserSteps, ApplicationSteps or ComponentSteps
+	// depending on the level, or ApplicationsStep (on user level) and
+	// ComponentsStep (on application level) to run the nested level
+	Step string `json:"step"`
+	// How many times to run the step, defaults to 1 except for ApplicationsStep
+	// which defaults to '--journey-repeats'. Repeating stops once '--journey-duration' passes.
+	Repeat int `json:"repeat,omitempty"`
+	// Failure of optional step is logged, but the journey continues
+	Optional bool `json:"optional,omitempty"`
+	// Run the step even when some previous required step failed, e.g. to collect data
+	Always bool `json:"always,omitempty"`
+	// How long to wait after every run of the step, e.g. "30s"
+	ThinkTime string `json:"thinkTime,omitempty"`
+	// Options (Opts field names, e.g. "WaitPipelines") overridden for the step
+	// and for everything it starts (e.g. "ComponentsCount" for ComponentsStep).
+	// Durations are given as strings, e.g. "30s", or as nanoseconds.
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	thinkTime time.Duration
+}
+
+// Journey definition loaded from '--journey-file'
+type Definition struct {
+	User        []StepDefinition `json:"user"`
+	Application []StepDefinition `json:"application"`
+	Component   []StepDefinition `json:"component"`
+}
+
+// Load journey definition from YAML file and check it
+func LoadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read journey definition %s: %v", path, err)
+	}
+	def := &Definition{}
+	if err := yaml.UnmarshalStrict(data, def); err != nil {
+		return nil, fmt.Errorf("Failed to parse journey definition %s: %v", path, err)
+	}
+	if err := def.Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid journey definition %s: %v", path, err)
+	}
+	return def, nil
+}
+
+// Check all steps exist and their parameters are valid options
+func (d *Definition) Validate() error {
+	levels := []struct {
+		name    string
+		steps   []StepDefinition
+		known   func(string) bool
+		nested  string
+		nesting bool
+	}{
+		{"user", d.User, func(s string) bool { _, ok := UserSteps[s]; return ok }, ApplicationsStep, len(d.Application) > 0},
+		{"application", d.Application, func(s string) bool { _, ok := ApplicationSteps[s]; return ok }, ComponentsStep, len(d.Component) > 0},
+		{"component", d.Component, func(s string) bool { _, ok := ComponentSteps[s]; return ok }, "", false},
+	}
+
+	for _, level := range levels {
+		nestedUsed := false
+		for i := range level.steps {
+			step := &level.steps[i]
+			if step.Step == level.nested && level.nested != "" {
+				nestedUsed = true
+			} else if !level.known(step.Step) {
+				return fmt.Errorf("unknown %s step %q", level.name, step.Step)
+			}
+			if step.Repeat < 0 {
+				return fmt.Errorf("%s step %s has negative repeat", level.name, step.Step)
+			}
+			if step.ThinkTime != "" {
+				parsed, err := time.ParseDuration(step.ThinkTime)
+				if err != nil {
+					return fmt.Errorf("%s step %s has invalid think time: %v", level.name, step.Step, err)
+				}
+				step.thinkTime = parsed
+			}
+			if _, err := applyParams(&options.Opts{}, step.Params); err != nil {
+				return fmt.Errorf("%s step %s has invalid params: %v", level.name, step.Step, err)
+			}
+		}
+		if level.nesting && !nestedUsed {
+			return fmt.Errorf("%s level has no %q step, so its nested level would never run", level.name, level.nested)
+		}
+	}
+	return nil
+}
+
+// Return copy of options with given options (Opts field names) overridden
+func applyParams(opts *options.Opts, params map[string]interface{}) (*options.Opts, error) {
+	if len(params) == 0 {
+		return opts, nil
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range params {
+		if _, ok := fields[key]; !ok {
+			return nil, fmt.Errorf("unknown option %q", key)
+		}
+		if field, ok := reflect.TypeOf(*opts).FieldByName(key); ok && field.Type == reflect.TypeOf(time.Duration(0)) {
+			if text, ok := value.(string); ok {
+				parsed, err := time.ParseDuration(text)
+				if err != nil {
+					return nil, fmt.Errorf("option %q: %v", key, err)
+				}
+				value = parsed
+			}
+		}
+		fields[key] = value
+	}
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	patched := &options.Opts{}
+	if err := json.Unmarshal(data, patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// How many times to run the step
+func (s *StepDefinition) repeats(opts *options.Opts) int {
+	if s.Repeat > 0 {
+		return s.Repeat
+	}
+	if s.Step == ApplicationsStep {
+		return opts.JourneyRepeats
+	}
+	return 1
+}
+
+// Run steps of one level. Step is run by given function with options
+// patched by step params. Once some required step fails, only steps marked
+// as always are run.
+func runSteps(what string, steps []StepDefinition, opts *options.Opts, run func(step *StepDefinition, opts *options.Opts) error) {
+	failed := false
+	for i := range steps {
+		step := &steps[i]
+		if failed && !step.Always {
+			continue
+		}
+
+		stepOpts, err := applyParams(opts, step.Params)
+		if err != nil {
+			logging.Logger.Error("%s step %s failed to apply params: %v", what, step.Step, err)
+			failed = true
+			continue
+		}
+
+		for repeat := 1; repeat <= step.repeats(stepOpts); repeat++ {
+			err = run(step, stepOpts)
+			if step.thinkTime > 0 {
+				time.Sleep(step.thinkTime)
+			}
+			if err != nil || time.Now().UTC().After(opts.JourneyUntil) {
+				break
+			}
+		}
+
+		if err != nil {
+			if step.Optional {
+				logging.Logger.Warning("%s optional step %s failed: %v", what, step.Step, err)
+			} else {
+				logging.Logger.Error("%s step %s failed: %v", what, step.Step, err)
+				failed = true
+			}
+		}
+	}
+}
+
+// User journey thread running steps of the definition, to be passed to Setup or SetupArrivals
+func (d *Definition) UserThread(threadCtx *MainContext) {
+	defer threadCtx.ThreadsWG.Done()
+
+	applicationsRuns := 0
+	runSteps("User thread", d.User, threadCtx.Opts, func(step *StepDefinition, opts *options.Opts) error {
+		// Skip journey iterations completed before resume
+		if step.Step == ApplicationsStep {
+			applicationsRuns++
+			if applicationsRuns <= threadCtx.CompletedIterations || IterationsDone(threadCtx) {
+				return nil
+			}
+		}
+
+		stepCtx := *threadCtx
+		stepCtx.Opts = opts
+		defer func() {
+			stepCtx.Opts = threadCtx.Opts
+			*threadCtx = stepCtx
+		}()
+
+		if step.Step == ApplicationsStep {
+			_, err := logging.Measure(PerApplicationSetup, d.applicationThread, &stepCtx)
+			if err == nil {
+				CompleteIteration(&stepCtx)
+			}
+			return err
+		}
+		_, err := logging.Measure(UserSteps[step.Step], &stepCtx)
+		return err
+	})
+}
+
+// Per application thread running application steps of the definition
+func (d *Definition) applicationThread(perApplicationCtx *PerApplicationContext) {
+	defer perApplicationCtx.PerApplicationWG.Done()
+
+	runSteps("Per application thread", d.Application, perApplicationCtx.Opts, func(step *StepDefinition, opts *options.Opts) error {
+		stepCtx := *perApplicationCtx
+		stepCtx.Opts = opts
+		defer func() {
+			stepCtx.Opts = perApplicationCtx.Opts
+			*perApplicationCtx = stepCtx
+		}()
+
+		if step.Step == ComponentsStep {
+			_, err := logging.Measure(PerComponentSetup, d.componentThread, &stepCtx)
+			return err
+		}
+		_, err := logging.Measure(ApplicationSteps[step.Step], &stepCtx)
+		return err
+	})
+}
+
+// Per component thread running component steps of the definition
+func (d *Definition) componentThread(perComponentCtx *PerComponentContext) {
+	defer perComponentCtx.PerComponentWG.Done()
+
+	runSteps("Per component thread", d.Component, perComponentCtx.Opts, func(step *StepDefinition, opts *options.Opts) error {
+		stepCtx := *perComponentCtx
+		stepCtx.Opts = opts
+		defer func() {
+			stepCtx.Opts = perComponentCtx.Opts
+			*perComponentCtx = stepCtx
+		}()
+
+		_, err := logging.Measure(ComponentSteps[step.Step], &stepCtx)
+		return err
+	})
+}
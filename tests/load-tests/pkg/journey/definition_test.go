@@ -0,0 +1,134 @@
+package journey
+
+import "errors"
+import "os"
+import "path/filepath"
+import "testing"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import options "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/options"
+
+import "github.com/stretchr/testify/assert"
+import "github.com/stretchr/testify/require"
+
+func TestLoadDefinition(t *testing.T) {
+	for _, name := range []string{"default.yaml", "build-only.yaml"} {
+		_, err := LoadDefinition(filepath.Join("..", "..", "journeys", name))
+		assert.NoError(t, err, name)
+	}
+
+	invalid := map[string]string{
+		"user:\n- step: HandleUnknown\n":                                                        `unknown user step "HandleUnknown"`,
+		"user:\n- step: applications\n  thinkTime: soon\n":                                      "invalid think time",
+		"user:\n- step: applications\n  params:\n    Unknown: 1\n":                              `unknown option "Unknown"`,
+		"user:\n- step: applications\n  params:\n    ComponentsCount: many\n":                   "invalid params",
+		"user:\n- step: HandlePersistentVolumeClaim\napplication:\n- step: HandleApplication\n": `user level has no "applications" step`,
+		"users:\n- step: applications\n":                                                        "Failed to parse",
+	}
+	for content, expected := range invalid {
+		path := filepath.Join(t.TempDir(), "journey.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		_, err := LoadDefinition(path)
+		assert.ErrorContains(t, err, expected, content)
+	}
+}
+
+func TestApplyParams(t *testing.T) {
+	opts := &options.Opts{ComponentsCount: 1, WaitPipelines: false, QuayRepo: "repo"}
+
+	same, err := applyParams(opts, nil)
+	require.NoError(t, err)
+	assert.Same(t, opts, same)
+
+	patched, err := applyParams(opts, map[string]interface{}{"ComponentsCount": 3, "WaitPipelines": true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, patched.ComponentsCount)
+	assert.True(t, patched.WaitPipelines)
+	assert.Equal(t, "repo", patched.QuayRepo)
+	assert.Equal(t, 1, opts.ComponentsCount)
+
+	durations, err := applyParams(opts, map[string]interface{}{"CheckpointInterval": "90s", "ChaosInterval": 1000})
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, durations.CheckpointInterval)
+	assert.Equal(t, time.Microsecond, durations.ChaosInterval)
+
+	_, err = applyParams(opts, map[string]interface{}{"CheckpointInterval": "soon"})
+	assert.ErrorContains(t, err, "CheckpointInterval")
+}
+
+func TestRunStepsRepeatsWithStepParams(t *testing.T) {
+	opts := &options.Opts{JourneyRepeats: 1, JourneyUntil: time.Now().Add(time.Hour)}
+	steps := []StepDefinition{{Step: ApplicationsStep, Params: map[string]interface{}{"JourneyRepeats": 3}}}
+
+	runs := 0
+	runSteps("Test thread", steps, opts, func(step *StepDefinition, stepOpts *options.Opts) error {
+		runs++
+		return nil
+	})
+
+	assert.Equal(t, 3, runs)
+}
+
+func TestRunSteps(t *testing.T) {
+	opts := &options.Opts{JourneyRepeats: 2, JourneyUntil: time.Now().Add(time.Hour), WaitPipelines: false}
+	steps := []StepDefinition{
+		{Step: ApplicationsStep},
+		{Step: "optional", Optional: true},
+		{Step: "required", Repeat: 3, Params: map[string]interface{}{"WaitPipelines": true}},
+		{Step: "skipped"},
+		{Step: "always", Always: true},
+	}
+
+	ran := []string{}
+	runSteps("Test thread", steps, opts, func(step *StepDefinition, stepOpts *options.Opts) error {
+		ran = append(ran, step.Step)
+		switch step.Step {
+		case "optional":
+			return errors.New("optional failed")
+		case "required":
+			assert.True(t, stepOpts.WaitPipelines)
+			return errors.New("required failed")
+		}
+		assert.False(t, stepOpts.WaitPipelines)
+		return nil
+	})
+
+	assert.Equal(t, []string{ApplicationsStep, ApplicationsStep, "optional", "required", "always"}, ran)
+}
+
+func TestApplicationThreadStepParams(t *testing.T) {
+	logging.MeasurementsStart(t.TempDir())
+	defer logging.MeasurementsStop()
+
+	opts := &options.Opts{ApplicationsCount: 3, ComponentsCount: 2, JourneyUntil: time.Now().Add(time.Hour)}
+	ApplicationSteps["test-app-step"] = func(ctx *PerApplicationContext) error {
+		assert.True(t, ctx.Opts.WaitPipelines)
+		assert.False(t, ctx.ParentContext.Opts.WaitPipelines)
+		return nil
+	}
+	ComponentSteps["test-comp-step"] = func(ctx *PerComponentContext) error {
+		assert.True(t, ctx.Opts.WaitPipelines)
+		assert.Equal(t, "pinned", ctx.Opts.ComponentRepoRevision)
+		assert.Empty(t, ctx.ParentContext.Opts.ComponentRepoRevision)
+		return nil
+	}
+	defer delete(ApplicationSteps, "test-app-step")
+	defer delete(ComponentSteps, "test-comp-step")
+	d := &Definition{
+		Application: []StepDefinition{
+			{Step: "test-app-step", Params: map[string]interface{}{"WaitPipelines": true}},
+			{Step: ComponentsStep, Params: map[string]interface{}{"WaitPipelines": true}},
+		},
+		Component: []StepDefinition{{Step: "test-comp-step", Params: map[string]interface{}{"ComponentRepoRevision": "pinned"}}},
+	}
+
+	userCtx := &MainContext{Opts: opts, Username: "user-1"}
+	_, err := PerApplicationSetup(d.applicationThread, userCtx)
+	assert.NoError(t, err)
+	require.Len(t, userCtx.PerApplicationContexts, 3)
+	for _, appCtx := range userCtx.PerApplicationContexts {
+		assert.Same(t, opts, appCtx.Opts)
+		assert.Len(t, appCtx.PerComponentContexts, 2)
+	}
+}
@@ -4,6 +4,7 @@ import "fmt"
 import "time"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 
 import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
 import utils "github.com/konflux-ci/e2e-tests/pkg/utils"
@@ -39,7 +40,8 @@ func HandleApplication(ctx *PerApplicationContext) error {
 
 	logging.Logger.Debug("Creating application %s in namespace %s", ctx.ApplicationName, ctx.ParentContext.Namespace)
 
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		createApplication,
 		ctx.Framework,
 		ctx.ParentContext.Namespace,
@@ -47,17 +49,20 @@ func HandleApplication(ctx *PerApplicationContext) error {
 		ctx.ApplicationName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(30, "Application failed creation: %v", err)
+		return logging.Logger.Fail(failures.ApplicationCreation, "Application failed creation: %v", err)
 	}
 
-	_, err = logging.Measure(
+	recordApplication(ctx)
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		validateApplication,
 		ctx.Framework,
 		ctx.ApplicationName,
 		ctx.ParentContext.Namespace,
 	)
 	if err != nil {
-		return logging.Logger.Fail(31, "Application failed validation: %v", err)
+		return logging.Logger.Fail(failures.ApplicationValidation, "Application failed validation: %v", err)
 	}
 
 	return nil
@@ -7,6 +7,7 @@ import "path/filepath"
 import "encoding/json"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 
 import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
 
@@ -181,25 +182,25 @@ func HandlePerComponentCollection(ctx *PerComponentContext) error {
 	var err error
 
 	journeyCounterStr := fmt.Sprintf("%d", ctx.ParentContext.ParentContext.JourneyRepeatsCounter)
-	dirPath := getDirName(ctx.ParentContext.ParentContext.Opts.OutputDir, ctx.ParentContext.ParentContext.Namespace, journeyCounterStr)
+	dirPath := getDirName(ctx.Opts.OutputDir, ctx.ParentContext.ParentContext.Namespace, journeyCounterStr)
 	err = createDir(dirPath)
 	if err != nil {
-		return logging.Logger.Fail(100, "Failed to create dir: %v", err)
+		return logging.Logger.Fail(failures.CollectionDirectory, "Failed to create dir: %v", err)
 	}
 
 	err = collectPodLogs(ctx.Framework, dirPath, ctx.ParentContext.ParentContext.Namespace, ctx.ComponentName)
 	if err != nil {
-		return logging.Logger.Fail(101, "Failed to collect pod logs: %v", err)
+		return logging.Logger.Fail(failures.PodLogsCollection, "Failed to collect pod logs: %v", err)
 	}
 
 	err = collectPipelineRunJSONs(ctx.Framework, dirPath, ctx.ParentContext.ParentContext.Namespace, ctx.ParentContext.ApplicationName, ctx.ComponentName)
 	if err != nil {
-		return logging.Logger.Fail(102, "Failed to collect pipeline run JSONs: %v", err)
+		return logging.Logger.Fail(failures.ResourcesCollection, "Failed to collect pipeline run JSONs: %v", err)
 	}
 
 	err = collectApplicationComponentJSONs(ctx.Framework, dirPath, ctx.ParentContext.ParentContext.Namespace, ctx.ParentContext.ApplicationName, ctx.ComponentName)
 	if err != nil {
-		return logging.Logger.Fail(102, "Failed to collect Application and Component JSONs: %v", err)
+		return logging.Logger.Fail(failures.ResourcesCollection, "Failed to collect Application and Component JSONs: %v", err)
 	}
 
 	return nil
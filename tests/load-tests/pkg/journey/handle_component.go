@@ -8,12 +8,15 @@ import (
 	"strings"
 	"time"
 
+	failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 	logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
 
 	constants "github.com/konflux-ci/e2e-tests/pkg/constants"
 
 	framework "github.com/konflux-ci/e2e-tests/pkg/framework"
 
+	has "github.com/konflux-ci/e2e-tests/pkg/clients/has"
+
 	utils "github.com/konflux-ci/e2e-tests/pkg/utils"
 
 	appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
@@ -83,34 +86,18 @@ func getPaCPull(annotations map[string]string) (string, error) {
 }
 
 func createComponent(f *framework.Framework, namespace, name, repoUrl, repoRevision, containerContext, containerFile, buildPipelineSelector, appName string, mintmakerDisabled bool) error {
-	// Prepare annotations to add to component
-	annotationsMap := constants.DefaultDockerBuildPipelineBundleAnnotation
-	if buildPipelineSelector != "" {
-		// Custom build pipeline selector
-		annotationsMap["build.appstudio.openshift.io/pipeline"] = fmt.Sprintf(`{"name": "docker-build", "bundle": "%s"}`, buildPipelineSelector)
-	}
+	// An empty buildPipelineSelector selects the "latest" bundle
+	builder := has.NewComponentBuilder(name, appName, namespace).
+		WithGitSource(repoUrl, repoRevision).
+		WithContext(containerContext).
+		WithDockerfile(containerFile).
+		WithBuildPipeline(constants.DockerBuild, buildPipelineSelector)
 	if mintmakerDisabled {
 		// Stop Mintmaker creating update PRs for your component
-		for key, value := range constants.ComponentMintmakerDisabledAnnotation {
-			annotationsMap[key] = value
-		}
+		builder.WithMintmakerDisabled()
 	}
 
-	componentObj := appstudioApi.ComponentSpec{
-		ComponentName: name,
-		Source: appstudioApi.ComponentSource{
-			ComponentSourceUnion: appstudioApi.ComponentSourceUnion{
-				GitSource: &appstudioApi.GitSource{
-					URL:           repoUrl,
-					Revision:      repoRevision,
-					Context:       containerContext,
-					DockerfileURL: containerFile,
-				},
-			},
-		},
-	}
-
-	_, err := f.AsKubeDeveloper.HasController.CreateComponent(componentObj, namespace, "", "", appName, false, annotationsMap)
+	_, err := f.AsKubeDeveloper.HasController.CreateComponentFromBuilder(builder)
 	if err != nil {
 		return fmt.Errorf("Unable to create the Component %s: %v", name, err)
 	}
@@ -280,23 +267,26 @@ func HandleComponent(ctx *PerComponentContext) error {
 	logging.Logger.Debug("Creating component %s in namespace %s", ctx.ComponentName, ctx.ParentContext.ParentContext.Namespace)
 
 	// Create component
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		createComponent,
 		ctx.Framework,
 		ctx.ParentContext.ParentContext.Namespace,
 		ctx.ComponentName,
 		ctx.ParentContext.ParentContext.ComponentRepoUrl,
-		ctx.ParentContext.ParentContext.Opts.ComponentRepoRevision,
-		ctx.ParentContext.ParentContext.Opts.ComponentContainerContext,
-		ctx.ParentContext.ParentContext.Opts.ComponentContainerFile,
-		ctx.ParentContext.ParentContext.Opts.BuildPipelineSelectorBundle,
+		ctx.Opts.ComponentRepoRevision,
+		ctx.Opts.ComponentContainerContext,
+		ctx.Opts.ComponentContainerFile,
+		ctx.Opts.BuildPipelineSelectorBundle,
 		ctx.ParentContext.ApplicationName,
-		ctx.ParentContext.ParentContext.Opts.PipelineMintmakerDisabled,
+		ctx.Opts.PipelineMintmakerDisabled,
 	)
 	if err != nil {
-		return logging.Logger.Fail(60, "Component failed creation: %v", err)
+		return logging.Logger.Fail(failures.ComponentCreation, "Component failed creation: %v", err)
 	}
 
+	recordComponent(ctx)
+
 	var pullIface interface{}
 	pullIface, err = logging.Measure(
 		getPaCPullNumber,
@@ -305,27 +295,27 @@ func HandleComponent(ctx *PerComponentContext) error {
 		ctx.ComponentName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(61, "Component failed validation: %v", err)
+		return logging.Logger.Fail(failures.ComponentValidation, "Component failed validation: %v", err)
 	}
 
 	// Get merge request number
 	var ok bool
 	ctx.MergeRequestNumber, ok = pullIface.(int)
 	if !ok {
-		return logging.Logger.Fail(62, "Type assertion failed on pull: %+v", pullIface)
+		return logging.Logger.Fail(failures.PullRequestNumber, "Type assertion failed on pull: %+v", pullIface)
 	}
 
 	// If this is supposed to be a multi-arch build, we do not care about
 	// current build, we just merge the PR, update pipelines and trigger
 	// actual multi-arch build
-	if ctx.ParentContext.ParentContext.Opts.PipelineRepoTemplating {
+	if ctx.Opts.PipelineRepoTemplating {
 		// Placeholders for template multi-arch PaC pipeline files
 		placeholders := &map[string]string{
 			"NAMESPACE":   ctx.ParentContext.ParentContext.Namespace,
-			"QUAY_REPO":   ctx.ParentContext.ParentContext.Opts.QuayRepo,
+			"QUAY_REPO":   ctx.Opts.QuayRepo,
 			"APPLICATION": ctx.ParentContext.ApplicationName,
 			"COMPONENT":   ctx.ComponentName,
-			"BRANCH":      ctx.ParentContext.ParentContext.Opts.ComponentRepoRevision,
+			"BRANCH":      ctx.Opts.ComponentRepoRevision,
 			"REPOURL":     ctx.ParentContext.ParentContext.ComponentRepoUrl,
 		}
 
@@ -337,30 +327,31 @@ func HandleComponent(ctx *PerComponentContext) error {
 			ctx.ParentContext.ApplicationName,
 			ctx.ComponentName,
 			ctx.ParentContext.ParentContext.ComponentRepoUrl,
-			ctx.ParentContext.ParentContext.Opts.ComponentRepoRevision,
+			ctx.Opts.ComponentRepoRevision,
 			ctx.MergeRequestNumber,
 			placeholders,
 		)
 		if err != nil {
-			return logging.Logger.Fail(63, "Repo-templating workflow component cleanup failed: %v", err)
+			return logging.Logger.Fail(failures.PullRequestCleanup, "Repo-templating workflow component cleanup failed: %v", err)
 		}
 
 	}
 
 	// Configure imagePullSecrets needed for component build task images
-	if len(ctx.ParentContext.ParentContext.Opts.PipelineImagePullSecrets) > 0 {
+	if len(ctx.Opts.PipelineImagePullSecrets) > 0 {
 		_, err = logging.Measure(
 			configurePipelineImagePullSecrets,
 			ctx.Framework,
 			ctx.ParentContext.ParentContext.Namespace,
 			ctx.ComponentName,
-			ctx.ParentContext.ParentContext.Opts.PipelineImagePullSecrets,
+			ctx.Opts.PipelineImagePullSecrets,
 		)
 		if err != nil {
-			return logging.Logger.Fail(64, "Failed to configure pipeline imagePullSecrets: %v", err)
+			return logging.Logger.Fail(failures.ImagePullSecrets, "Failed to configure pipeline imagePullSecrets: %v", err)
 		}
 	}
 
+	recordComponent(ctx)
 
 	return nil
 }
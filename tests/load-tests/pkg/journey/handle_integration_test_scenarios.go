@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 	logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
 
 	framework "github.com/konflux-ci/e2e-tests/pkg/framework"
@@ -70,21 +71,23 @@ func HandleIntegrationTestScenario(ctx *PerApplicationContext) error {
 	name := fmt.Sprintf("%s-its-%s", ctx.ParentContext.Username, util.GenerateRandomString(5))
 	logging.Logger.Debug("Creating integration test scenario %s for application %s in namespace %s", name, ctx.ApplicationName, ctx.ParentContext.Namespace)
 
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		createIntegrationTestScenario,
 		ctx.Framework,
 		ctx.ParentContext.Namespace,
 		name,
 		ctx.ApplicationName,
-		ctx.ParentContext.Opts.TestScenarioGitURL,
-		ctx.ParentContext.Opts.TestScenarioRevision,
-		ctx.ParentContext.Opts.TestScenarioPathInRepo,
+		ctx.Opts.TestScenarioGitURL,
+		ctx.Opts.TestScenarioRevision,
+		ctx.Opts.TestScenarioPathInRepo,
 	)
 	if err != nil {
-		return logging.Logger.Fail(40, "Integration test scenario failed creation: %v", err)
+		return logging.Logger.Fail(failures.TestScenarioCreation, "Integration test scenario failed creation: %v", err)
 	}
 
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		validateIntegrationTestScenario,
 		ctx.Framework,
 		ctx.ParentContext.Namespace,
@@ -92,10 +95,12 @@ func HandleIntegrationTestScenario(ctx *PerApplicationContext) error {
 		ctx.ApplicationName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(41, "Integration test scenario failed validation: %v", err)
+		return logging.Logger.Fail(failures.TestScenarioValidation, "Integration test scenario failed validation: %v", err)
 	}
 
 	ctx.IntegrationTestScenarioName = name
 
+	recordApplication(ctx)
+
 	return nil
 }
@@ -4,6 +4,7 @@ import "context"
 import "fmt"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 
 import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
 import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,7 +17,7 @@ func collectPersistentVolumeClaims(f *framework.Framework, namespace string) err
 	for _, pvc := range pvcs.Items {
 		pv, err := f.AsKubeAdmin.TektonController.KubeInterface().CoreV1().PersistentVolumes().Get(context.Background(), pvc.Spec.VolumeName, metav1.GetOptions{})
 		if err != nil {
-			_ = logging.Logger.Fail(76, "Error getting PV: %v\n", err)
+			_ = logging.Logger.Fail(failures.PersistentVolumeLookup, "Error getting PV: %v\n", err)
 			continue
 		}
 		waittime := (pv.ObjectMeta.CreationTimestamp.Time).Sub(pvc.ObjectMeta.CreationTimestamp.Time)
@@ -43,7 +44,7 @@ func HandlePersistentVolumeClaim(ctx *MainContext) error {
 		ctx.Namespace,
 	)
 	if err != nil {
-		return logging.Logger.Fail(75, "Collecting persistent volume claim failed: %v", err)
+		return logging.Logger.Fail(failures.PersistentVolumeCollection, "Collecting persistent volume claim failed: %v", err)
 	}
 
 	return nil
@@ -5,9 +5,11 @@ import "strings"
 import "time"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 
 import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
 import utils "github.com/konflux-ci/e2e-tests/pkg/utils"
+import tekton "github.com/konflux-ci/e2e-tests/pkg/utils/tekton"
 import pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 
 func validatePipelineRunCreation(f *framework.Framework, namespace, appName, compName string) error {
@@ -52,7 +54,7 @@ func validatePipelineRunCondition(f *framework.Framework, namespace, appName, co
 				return false, fmt.Errorf("PipelineRun for component %s in namespace %s is in error state: %+v", compName, namespace, condition)
 			}
 			if condition.Type == "Succeeded" && condition.Status == "False" {
-				return false, fmt.Errorf("PipelineRun for component %s in namespace %s failed: %+v", compName, namespace, condition)
+				return false, tekton.NewPipelineFailedError(f.AsKubeDeveloper.HasController.KubeRest(), f.AsKubeDeveloper.HasController.KubeInterface(), pr, false)
 			}
 			if condition.Type == "Succeeded" && condition.Status == "True" {
 				return true, nil
@@ -103,7 +105,7 @@ func validatePipelineRunSignature(f *framework.Framework, namespace, appName, co
 }
 
 func HandlePipelineRun(ctx *PerComponentContext) error {
-	if !ctx.ParentContext.ParentContext.Opts.WaitPipelines {
+	if !ctx.Opts.WaitPipelines {
 		return nil
 	}
 
@@ -111,7 +113,8 @@ func HandlePipelineRun(ctx *PerComponentContext) error {
 
 	logging.Logger.Debug("Creating build pipeline run for component %s in namespace %s", ctx.ComponentName, ctx.ParentContext.ParentContext.Namespace)
 
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		validatePipelineRunCreation,
 		ctx.Framework,
 		ctx.ParentContext.ParentContext.Namespace,
@@ -119,10 +122,11 @@ func HandlePipelineRun(ctx *PerComponentContext) error {
 		ctx.ComponentName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(70, "Build Pipeline Run failed creation: %v", err)
+		return logging.Logger.Fail(failures.BuildPipelineRunCreation, "Build Pipeline Run failed creation: %v", err)
 	}
 
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		validatePipelineRunCondition,
 		ctx.Framework,
 		ctx.ParentContext.ParentContext.Namespace,
@@ -130,10 +134,11 @@ func HandlePipelineRun(ctx *PerComponentContext) error {
 		ctx.ComponentName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(71, "Build Pipeline Run failed run: %v", err)
+		return logging.Logger.Fail(failures.BuildPipelineRunFailed, "Build Pipeline Run failed run: %v", err)
 	}
 
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		validatePipelineRunSignature,
 		ctx.Framework,
 		ctx.ParentContext.ParentContext.Namespace,
@@ -141,7 +146,7 @@ func HandlePipelineRun(ctx *PerComponentContext) error {
 		ctx.ComponentName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(72, "Build Pipeline Run failed signing: %v", err)
+		return logging.Logger.Fail(failures.BuildPipelineRunSigning, "Build Pipeline Run failed signing: %v", err)
 	}
 
 	return nil
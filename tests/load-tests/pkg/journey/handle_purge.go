@@ -1,11 +1,14 @@
 package journey
 
+import "errors"
 import "fmt"
 import "time"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
 
+import common "github.com/konflux-ci/e2e-tests/pkg/clients/common"
 import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
+import releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
 
 func purgeStage(f *framework.Framework, namespace string) error {
 	var err error
@@ -30,23 +33,55 @@ func purgeStage(f *framework.Framework, namespace string) error {
 		return fmt.Errorf("Error when deleting MPC secrets in namespace %s: %v", namespace, err)
 	}
 
+	err = common.DeleteAllResources[releaseApi.Release](f.AsKubeDeveloper.ReleaseController.KubeRest(), namespace, common.DeleteAllOptions{})
+	if err != nil {
+		return fmt.Errorf("Error when deleting releases in namespace %s: %v", namespace, err)
+	}
+
+	err = common.DeleteAllResources[releaseApi.ReleasePlan](f.AsKubeDeveloper.ReleaseController.KubeRest(), namespace, common.DeleteAllOptions{})
+	if err != nil {
+		return fmt.Errorf("Error when deleting release plans in namespace %s: %v", namespace, err)
+	}
+
+	err = common.DeleteAllResources[releaseApi.ReleasePlanAdmission](f.AsKubeDeveloper.ReleaseController.KubeRest(), namespace, common.DeleteAllOptions{})
+	if err != nil {
+		return fmt.Errorf("Error when deleting release plan admissions in namespace %s: %v", namespace, err)
+	}
+
 	logging.Logger.Debug("Finished purging namespace %s", namespace)
 	return nil
 }
 
-func purgeCi(f *framework.Framework, username string) error {
-	var err error
+// Delete user signup and release managed namespaces, keeps deleting when
+// some deletion fails and returns all the errors
+func purgeCi(f *framework.Framework, username string, managedNamespaces []string) error {
+	errs := []error{}
 
-	_, err = f.SandboxController.DeleteUserSignup(username)
+	_, err := f.SandboxController.DeleteUserSignup(username)
 	if err != nil {
-		return fmt.Errorf("Error when deleting user signup %s: %v", username, err)
+		errs = append(errs, fmt.Errorf("Error when deleting user signup %s: %v", username, err))
+	}
+
+	for _, namespace := range managedNamespaces {
+		err = f.AsKubeAdmin.CommonController.DeleteNamespace(namespace)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Error when deleting release managed namespace %s: %v", namespace, err))
+		}
 	}
 
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
 	logging.Logger.Debug("Finished purging user %s", username)
 	return nil
 }
 
 func Purge() error {
+	// No user was created yet, e.g. when resuming from a checkpoint saved before the first user
+	if len(MainContexts) == 0 {
+		logging.Logger.Info("No users to purge resources of")
+		return nil
+	}
 	if !MainContexts[0].Opts.Purge {
 		return nil
 	}
@@ -61,7 +96,13 @@ func Purge() error {
 				errCounter++
 			}
 		} else {
-			err := purgeCi(ctx.Framework, ctx.Username)
+			managedNamespaces := []string{}
+			for _, appCtx := range ctx.PerApplicationContexts {
+				if appCtx.ReleaseManagedNamespace != "" {
+					managedNamespaces = append(managedNamespaces, appCtx.ReleaseManagedNamespace)
+				}
+			}
+			err := purgeCi(ctx.Framework, ctx.Username, managedNamespaces)
 			if err != nil {
 				logging.Logger.Error("Error when purging CI: %v", err)
 				errCounter++
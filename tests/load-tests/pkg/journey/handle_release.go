@@ -0,0 +1,337 @@
+package journey
+
+import "fmt"
+import "time"
+
+import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
+
+import ecp "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
+import utils "github.com/konflux-ci/e2e-tests/pkg/utils"
+import util "github.com/devfile/library/v2/pkg/util"
+import releaseApi "github.com/konflux-ci/release-service/api/v1alpha1"
+import tektonutils "github.com/konflux-ci/release-service/tekton/utils"
+import k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+import meta "k8s.io/apimachinery/pkg/api/meta"
+
+// Client used for resources in the managed namespace. On Stage we do not
+// have admin access, so the managed namespace is the user namespace.
+func managedClient(f *framework.Framework, stage bool) *framework.ControllerHub {
+	if stage {
+		return f.AsKubeDeveloper
+	}
+	return f.AsKubeAdmin
+}
+
+func createReleaseManagedNamespace(f *framework.Framework, stage bool, managedNamespace, serviceAccountName, policyName string) error {
+	hub := managedClient(f, stage)
+
+	if !stage {
+		_, err := hub.CommonController.CreateTestNamespace(managedNamespace)
+		if err != nil {
+			return fmt.Errorf("Unable to create managed namespace %s: %v", managedNamespace, err)
+		}
+	}
+
+	sa, err := hub.CommonController.CreateServiceAccount(serviceAccountName, managedNamespace, nil, nil)
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return fmt.Errorf("Unable to create release service account %s in namespace %s: %v", serviceAccountName, managedNamespace, err)
+	}
+	if err == nil {
+		_, err = hub.ReleaseController.CreateReleasePipelineRoleBindingForServiceAccount(managedNamespace, sa)
+		if err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return fmt.Errorf("Unable to create release role binding for service account %s in namespace %s: %v", serviceAccountName, managedNamespace, err)
+		}
+	}
+
+	// Policy without any sources, so release pipeline verification does not depend on the build
+	policySpec := ecp.EnterpriseContractPolicySpec{
+		Description: "Load test policy without any rules",
+		Sources:     []ecp.Source{{Name: "load-test"}},
+	}
+	_, err = hub.TektonController.CreateEnterpriseContractPolicy(policyName, managedNamespace, policySpec)
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return fmt.Errorf("Unable to create enterprise contract policy %s in namespace %s: %v", policyName, managedNamespace, err)
+	}
+
+	return nil
+}
+
+func createReleasePlan(f *framework.Framework, namespace, name, appName, managedNamespace string) error {
+	_, err := f.AsKubeDeveloper.ReleaseController.CreateReleasePlan(name, namespace, appName, managedNamespace, "false", nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Unable to create the Release Plan %s: %v", name, err)
+	}
+	return nil
+}
+
+func createReleasePlanAdmission(f *framework.Framework, stage bool, namespace, name, appName, managedNamespace, serviceAccountName, policyName, pipelineUrl, pipelineRevision, pipelinePath string) error {
+	pipelineRef := &tektonutils.PipelineRef{
+		Resolver: "git",
+		Params: []tektonutils.Param{
+			{Name: "url", Value: pipelineUrl},
+			{Name: "revision", Value: pipelineRevision},
+			{Name: "pathInRepo", Value: pipelinePath},
+		},
+	}
+	_, err := managedClient(f, stage).ReleaseController.CreateReleasePlanAdmission(name, managedNamespace, "", namespace, policyName, serviceAccountName, []string{appName}, false, pipelineRef, nil)
+	if err != nil {
+		return fmt.Errorf("Unable to create the Release Plan Admission %s: %v", name, err)
+	}
+	return nil
+}
+
+func validateReleasePlan(f *framework.Framework, namespace, name string) error {
+	interval := time.Second * 10
+	timeout := time.Minute * 5
+
+	// TODO It would be much better to watch this resource for a condition
+	err := utils.WaitUntilWithInterval(func() (done bool, err error) {
+		releasePlan, err := f.AsKubeDeveloper.ReleaseController.GetReleasePlan(name, namespace)
+		if err != nil {
+			logging.Logger.Debug("Unable to get release plan %s in namespace %s: %v", name, namespace, err)
+			return false, nil
+		}
+
+		condition := meta.FindStatusCondition(releasePlan.Status.Conditions, releaseApi.MatchedConditionType.String())
+		if condition == nil || condition.Status != "True" {
+			logging.Logger.Trace("Release plan %s in namespace %s is not matched yet", name, namespace)
+			return false, nil
+		}
+		return true, nil
+	}, interval, timeout)
+
+	return err
+}
+
+func createRelease(f *framework.Framework, namespace, name, snapName, releasePlanName string) error {
+	_, err := f.AsKubeDeveloper.ReleaseController.CreateRelease(name, namespace, snapName, releasePlanName)
+	if err != nil {
+		return fmt.Errorf("Unable to create the Release %s: %v", name, err)
+	}
+	return nil
+}
+
+func validateReleasePipelineRunCreation(f *framework.Framework, stage bool, namespace, name, managedNamespace string) error {
+	release, err := f.AsKubeDeveloper.ReleaseController.GetRelease(name, "", namespace)
+	if err != nil {
+		return fmt.Errorf("Unable to get the Release %s: %v", name, err)
+	}
+	_, err = managedClient(f, stage).ReleaseController.WaitForReleasePipelineToGetStarted(release, managedNamespace)
+	return err
+}
+
+func validateReleasePipelineRunCondition(f *framework.Framework, stage bool, namespace, name, managedNamespace string) error {
+	release, err := f.AsKubeDeveloper.ReleaseController.GetRelease(name, "", namespace)
+	if err != nil {
+		return fmt.Errorf("Unable to get the Release %s: %v", name, err)
+	}
+	return managedClient(f, stage).ReleaseController.WaitForReleasePipelineToBeFinished(release, managedNamespace)
+}
+
+func validateReleaseCondition(f *framework.Framework, namespace, name string) error {
+	interval := time.Second * 10
+	timeout := time.Minute * 10
+
+	// TODO It would be much better to watch this resource for a condition
+	err := utils.WaitUntilWithInterval(func() (done bool, err error) {
+		release, err := f.AsKubeDeveloper.ReleaseController.GetRelease(name, "", namespace)
+		if err != nil {
+			logging.Logger.Debug("Unable to get release %s in namespace %s: %v", name, namespace, err)
+			return false, nil
+		}
+
+		if release.IsReleased() {
+			return true, nil
+		}
+		if release.HasReleaseFinished() {
+			messages, _ := f.AsKubeDeveloper.ReleaseController.GetReleaseConditionStatusMessages(name, namespace)
+			return false, fmt.Errorf("Release %s in namespace %s failed: %v", name, namespace, messages)
+		}
+
+		logging.Logger.Trace("Still waiting for release %s in namespace %s", name, namespace)
+		return false, nil
+	}, interval, timeout)
+
+	return err
+}
+
+// Create ReleasePlan and ReleasePlanAdmission (with managed namespace) for the application
+func HandleReleaseSetup(ctx *PerApplicationContext) error {
+	opts := ctx.Opts
+	if !opts.Release {
+		return nil
+	}
+
+	var err error
+
+	managedNamespace := ctx.ParentContext.Namespace
+	if !opts.Stage {
+		managedNamespace = ctx.ApplicationName + "-managed"
+	}
+	releasePlanName := ctx.ApplicationName + "-rp"
+	releasePlanAdmissionName := ctx.ApplicationName + "-rpa"
+	policyName := ctx.ApplicationName + "-policy"
+
+	logging.Logger.Debug("Creating release plan %s for application %s in namespace %s with managed namespace %s", releasePlanName, ctx.ApplicationName, ctx.ParentContext.Namespace, managedNamespace)
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		createReleaseManagedNamespace,
+		ctx.Framework,
+		opts.Stage,
+		managedNamespace,
+		opts.ReleaseServiceAccount,
+		policyName,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleaseNamespaceCreation, "Release managed namespace failed creation: %v", err)
+	}
+
+	// Record the managed namespace right away, so it gets purged even if the rest of the setup fails
+	ctx.ReleaseManagedNamespace = managedNamespace
+	recordApplication(ctx)
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		createReleasePlan,
+		ctx.Framework,
+		ctx.ParentContext.Namespace,
+		releasePlanName,
+		ctx.ApplicationName,
+		managedNamespace,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleasePlanCreation, "Release plan failed creation: %v", err)
+	}
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		createReleasePlanAdmission,
+		ctx.Framework,
+		opts.Stage,
+		ctx.ParentContext.Namespace,
+		releasePlanAdmissionName,
+		ctx.ApplicationName,
+		managedNamespace,
+		opts.ReleaseServiceAccount,
+		policyName,
+		opts.ReleasePipelineUrl,
+		opts.ReleasePipelineRevision,
+		opts.ReleasePipelinePath,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleasePlanAdmissionCreation, "Release plan admission failed creation: %v", err)
+	}
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		validateReleasePlan,
+		ctx.Framework,
+		ctx.ParentContext.Namespace,
+		releasePlanName,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleasePlanValidation, "Release plan failed validation: %v", err)
+	}
+
+	ctx.ReleasePlanName = releasePlanName
+
+	recordApplication(ctx)
+
+	return nil
+}
+
+// Create Release of the component Snapshot and wait for the release PipelineRun
+func HandleRelease(ctx *PerComponentContext) error {
+	opts := ctx.Opts
+	if !opts.Release || !opts.WaitPipelines {
+		return nil
+	}
+	if ctx.ParentContext.ReleasePlanName == "" {
+		return logging.Logger.Fail(failures.ReleasePlanMissing, "Release plan for application %s was not created", ctx.ParentContext.ApplicationName)
+	}
+
+	var err error
+	var ok bool
+
+	namespace := ctx.ParentContext.ParentContext.Namespace
+
+	// Snapshot is known when we waited for integration tests
+	if ctx.SnapshotName == "" {
+		result1, err1 := logging.MeasureIn(
+			ctx.IterationKey(),
+			validateSnapshotCreation,
+			ctx.Framework,
+			namespace,
+			ctx.ComponentName,
+		)
+		if err1 != nil {
+			return logging.Logger.Fail(failures.SnapshotCreation, "Snapshot failed creation: %v", err1)
+		}
+		ctx.SnapshotName, ok = result1.(string)
+		if !ok {
+			return logging.Logger.Fail(failures.SnapshotName, "Snapshot name type assertion failed")
+		}
+	}
+
+	name := fmt.Sprintf("%s-rel-%s", ctx.ComponentName, util.GenerateRandomString(5))
+	logging.Logger.Debug("Creating release %s of snapshot %s in namespace %s", name, ctx.SnapshotName, namespace)
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		createRelease,
+		ctx.Framework,
+		namespace,
+		name,
+		ctx.SnapshotName,
+		ctx.ParentContext.ReleasePlanName,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleaseCreation, "Release failed creation: %v", err)
+	}
+
+	ctx.ReleaseName = name
+
+	recordComponent(ctx)
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		validateReleasePipelineRunCreation,
+		ctx.Framework,
+		opts.Stage,
+		namespace,
+		name,
+		ctx.ParentContext.ReleaseManagedNamespace,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleasePipelineRunCreation, "Release Pipeline Run failed creation: %v", err)
+	}
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		validateReleasePipelineRunCondition,
+		ctx.Framework,
+		opts.Stage,
+		namespace,
+		name,
+		ctx.ParentContext.ReleaseManagedNamespace,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleasePipelineRunFailed, "Release Pipeline Run failed run: %v", err)
+	}
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
+		validateReleaseCondition,
+		ctx.Framework,
+		namespace,
+		name,
+	)
+	if err != nil {
+		return logging.Logger.Fail(failures.ReleaseFailed, "Release failed: %v", err)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import "strings"
 import "regexp"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 
 import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
 import github "github.com/google/go-github/v44/github"
@@ -169,10 +170,12 @@ func HandleRepoForking(ctx *MainContext) error {
 		ctx.Username,
 	)
 	if err != nil {
-		return logging.Logger.Fail(80, "Repo forking failed: %v", err)
+		return logging.Logger.Fail(failures.RepoForking, "Repo forking failed: %v", err)
 	}
 
 	ctx.ComponentRepoUrl = forkUrl
 
+	recordUser(ctx)
+
 	return nil
 }
@@ -5,10 +5,12 @@ import "strings"
 import "time"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 
 import appstudioApi "github.com/konflux-ci/application-api/api/v1alpha1"
 import framework "github.com/konflux-ci/e2e-tests/pkg/framework"
 import utils "github.com/konflux-ci/e2e-tests/pkg/utils"
+import tekton "github.com/konflux-ci/e2e-tests/pkg/utils/tekton"
 import pipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 
 func validateSnapshotCreation(f *framework.Framework, namespace, compName string) (string, error) {
@@ -70,6 +72,9 @@ func validateTestPipelineRunCondition(f *framework.Framework, namespace, itsName
 			if (strings.HasPrefix(string(condition.Type), "Error") || strings.HasSuffix(string(condition.Type), "Error")) && condition.Status == "True" {
 				return false, fmt.Errorf("PipelineRun for integration test pipeline %s in namespace %s is in error state: %+v", snapName, namespace, condition)
 			}
+			if condition.Type == "Succeeded" && condition.Status == "False" {
+				return false, tekton.NewPipelineFailedError(f.AsKubeDeveloper.IntegrationController.KubeRest(), f.AsKubeDeveloper.IntegrationController.KubeInterface(), pr, false)
+			}
 			if condition.Type == "Succeeded" && condition.Status == "True" {
 				return true, nil
 			}
@@ -83,7 +88,7 @@ func validateTestPipelineRunCondition(f *framework.Framework, namespace, itsName
 }
 
 func HandleTest(ctx *PerComponentContext) error {
-	if !ctx.ParentContext.ParentContext.Opts.WaitPipelines || !ctx.ParentContext.ParentContext.Opts.WaitIntegrationTestsPipelines {
+	if !ctx.Opts.WaitPipelines || !ctx.Opts.WaitIntegrationTestsPipelines {
 		return nil
 	}
 
@@ -92,21 +97,25 @@ func HandleTest(ctx *PerComponentContext) error {
 
 	logging.Logger.Debug("Creating test pipeline run for component %s in namespace %s", ctx.ComponentName, ctx.ParentContext.ParentContext.Namespace)
 
-	result1, err1 := logging.Measure(
+	result1, err1 := logging.MeasureIn(
+		ctx.IterationKey(),
 		validateSnapshotCreation,
 		ctx.Framework,
 		ctx.ParentContext.ParentContext.Namespace,
 		ctx.ComponentName,
 	)
 	if err1 != nil {
-		return logging.Logger.Fail(80, "Snapshot failed creation: %v", err1)
+		return logging.Logger.Fail(failures.SnapshotCreation, "Snapshot failed creation: %v", err1)
 	}
 	ctx.SnapshotName, ok = result1.(string)
 	if !ok {
-		return logging.Logger.Fail(81, "Snapshot name type assertion failed")
+		return logging.Logger.Fail(failures.SnapshotName, "Snapshot name type assertion failed")
 	}
 
-	_, err = logging.Measure(
+	recordComponent(ctx)
+
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		validateTestPipelineRunCreation,
 		ctx.Framework,
 		ctx.ParentContext.ParentContext.Namespace,
@@ -114,10 +123,11 @@ func HandleTest(ctx *PerComponentContext) error {
 		ctx.SnapshotName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(82, "Test Pipeline Run failed creation: %v", err)
+		return logging.Logger.Fail(failures.TestPipelineRunCreation, "Test Pipeline Run failed creation: %v", err)
 	}
 
-	_, err = logging.Measure(
+	_, err = logging.MeasureIn(
+		ctx.IterationKey(),
 		validateTestPipelineRunCondition,
 		ctx.Framework,
 		ctx.ParentContext.ParentContext.Namespace,
@@ -125,7 +135,7 @@ func HandleTest(ctx *PerComponentContext) error {
 		ctx.SnapshotName,
 	)
 	if err != nil {
-		return logging.Logger.Fail(83, "Test Pipeline Run failed run: %v", err)
+		return logging.Logger.Fail(failures.TestPipelineRunFailed, "Test Pipeline Run failed run: %v", err)
 	}
 
 	return nil
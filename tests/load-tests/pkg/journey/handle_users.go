@@ -4,6 +4,7 @@ import "fmt"
 import "time"
 
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
 
 import "github.com/konflux-ci/e2e-tests/pkg/framework"
 import "github.com/konflux-ci/e2e-tests/pkg/utils"
@@ -29,11 +30,13 @@ func HandleUser(ctx *MainContext) error {
 	}
 
 	if err != nil {
-		return logging.Logger.Fail(10, "Unable to provision user %s: %v", ctx.Username, err)
+		return logging.Logger.Fail(failures.UserProvisioning, "Unable to provision user %s: %v", ctx.Username, err)
 	}
 
 	ctx.Namespace = ctx.Framework.UserNamespace
 
+	recordUser(ctx)
+
 	return nil
 }
 
@@ -41,7 +44,7 @@ func HandleNewFrameworkForComp(ctx *PerComponentContext) error {
 	var err error
 
 	// TODO This framework generation code is duplicate to above
-	if ctx.ParentContext.ParentContext.Opts.Stage {
+	if ctx.Opts.Stage {
 		user := (*ctx.ParentContext.ParentContext.StageUsers)[ctx.ParentContext.ParentContext.ThreadIndex]
 		ctx.Framework, err = framework.NewFrameworkWithTimeout(
 			ctx.ParentContext.ParentContext.Username,
@@ -56,7 +59,7 @@ func HandleNewFrameworkForComp(ctx *PerComponentContext) error {
 	}
 
 	if err != nil {
-		return logging.Logger.Fail(11, "Unable to provision framework for user %s: %v", ctx.ParentContext.ParentContext.Username, err)
+		return logging.Logger.Fail(failures.UserFrameworkForComponent, "Unable to provision framework for user %s: %v", ctx.ParentContext.ParentContext.Username, err)
 	}
 
 	return nil
@@ -66,7 +69,7 @@ func HandleNewFrameworkForApp(ctx *PerApplicationContext) error {
 	var err error
 
 	// TODO This framework generation code is duplicate to above
-	if ctx.ParentContext.Opts.Stage {
+	if ctx.Opts.Stage {
 		user := (*ctx.ParentContext.StageUsers)[ctx.ParentContext.ThreadIndex]
 		ctx.Framework, err = framework.NewFrameworkWithTimeout(
 			ctx.ParentContext.Username,
@@ -81,7 +84,7 @@ func HandleNewFrameworkForApp(ctx *PerApplicationContext) error {
 	}
 
 	if err != nil {
-		return logging.Logger.Fail(12, "Unable to provision framework for user %s: %v", ctx.ParentContext.Username, err)
+		return logging.Logger.Fail(failures.UserFrameworkForApplication, "Unable to provision framework for user %s: %v", ctx.ParentContext.Username, err)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package journey
 
 import "fmt"
 import "sync"
+import "sync/atomic"
 
 import options "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/options"
 import logging "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/logging"
@@ -13,11 +14,27 @@ import util "github.com/devfile/library/v2/pkg/util"
 // Pointers to all user journey thread contexts
 var MainContexts []*MainContext
 
+// Number of running user, per application and per component journey threads
+var activeUserThreads, activeApplicationThreads, activeComponentThreads atomic.Int64
+
+// Return number of running user, per application and per component journey threads
+func ActiveThreads() (users, applications, components int) {
+	return int(activeUserThreads.Load()), int(activeApplicationThreads.Load()), int(activeComponentThreads.Load())
+}
+
+// Run thread function while counting it as active
+func runActive(counter *atomic.Int64, fn func()) {
+	counter.Add(1)
+	defer counter.Add(-1)
+	fn()
+}
+
 // Struct to hold user journey thread data
 type MainContext struct {
 	ThreadsWG              *sync.WaitGroup
 	ThreadIndex            int
 	JourneyRepeatsCounter  int
+	CompletedIterations    int
 	Opts                   *options.Opts
 	StageUsers             *[]loadtestutils.User
 	Framework              *framework.Framework
@@ -27,6 +44,11 @@ type MainContext struct {
 	PerApplicationContexts []*PerApplicationContext
 }
 
+// Identifies user journey iteration in measurements
+func (ctx *MainContext) IterationKey() string {
+	return fmt.Sprintf("thread-%d", ctx.ThreadIndex)
+}
+
 // Just to create user
 func initUserThread(threadCtx *MainContext) {
 	defer threadCtx.ThreadsWG.Done()
@@ -45,7 +67,6 @@ func initUserThread(threadCtx *MainContext) {
 // TODO split this to two functions and get PurgeOnly code out
 func Setup(fn func(*MainContext), opts *options.Opts) (string, error) {
 	threadsWG := &sync.WaitGroup{}
-	threadsWG.Add(opts.Concurrency)
 
 	var stageUsers []loadtestutils.User
 	var err error
@@ -56,8 +77,14 @@ func Setup(fn func(*MainContext), opts *options.Opts) (string, error) {
 		}
 	}
 
+	// When resuming, continue with user threads from the checkpoint
+	if opts.Resume {
+		MainContexts = contextsFromCheckpoint(threadsWG, opts, &stageUsers)
+		logging.Logger.Info("Resuming %d threads from checkpoint", len(MainContexts))
+	}
+
 	// Initialize all user thread contexts
-	for threadIndex := 0; threadIndex < opts.Concurrency; threadIndex++ {
+	for threadIndex := opts.ThreadOffset; threadIndex < opts.ThreadOffset+opts.Concurrency && !opts.Resume; threadIndex++ {
 		logging.Logger.Info("Initiating thread %d", threadIndex)
 
 		threadCtx := &MainContext{
@@ -73,6 +100,7 @@ func Setup(fn func(*MainContext), opts *options.Opts) (string, error) {
 	}
 
 	// Create all users (if necessary) and initialize their frameworks
+	threadsWG.Add(len(MainContexts))
 	for _, threadCtx := range MainContexts {
 		go initUserThread(threadCtx)
 	}
@@ -87,17 +115,20 @@ func Setup(fn func(*MainContext), opts *options.Opts) (string, error) {
 
 	// Fork repositories sequentially as GitHub do not allow more than 3 running forks in parallel anyway
 	for _, threadCtx := range MainContexts {
+		if threadCtx.ComponentRepoUrl != "" {
+			continue // already forked before resume
+		}
 		_, err = logging.Measure(HandleRepoForking, threadCtx)
 		if err != nil {
 			return "", err
 		}
 	}
 
-	threadsWG.Add(opts.Concurrency)
+	threadsWG.Add(len(MainContexts))
 
 	// Run actual user thread function
 	for _, threadCtx := range MainContexts {
-		go fn(threadCtx)
+		go runActive(&activeUserThreads, func() { fn(threadCtx) })
 	}
 
 	threadsWG.Wait()
@@ -109,13 +140,21 @@ func Setup(fn func(*MainContext), opts *options.Opts) (string, error) {
 type PerApplicationContext struct {
 	PerApplicationWG            *sync.WaitGroup
 	ApplicationIndex            int
+	Opts                        *options.Opts // options of the user thread, overridden by application step params
 	Framework                   *framework.Framework
 	ParentContext               *MainContext
 	ApplicationName             string
 	IntegrationTestScenarioName string
+	ReleasePlanName             string
+	ReleaseManagedNamespace     string
 	PerComponentContexts        []*PerComponentContext
 }
 
+// Identifies application journey iteration in measurements
+func (ctx *PerApplicationContext) IterationKey() string {
+	return ctx.ParentContext.IterationKey() + "/" + ctx.ApplicationName
+}
+
 // Start all the threads to process all applications per user
 func PerApplicationSetup(fn func(*PerApplicationContext), parentContext *MainContext) (string, error) {
 	perApplicationWG := &sync.WaitGroup{}
@@ -127,13 +166,14 @@ func PerApplicationSetup(fn func(*PerApplicationContext), parentContext *MainCon
 		perApplicationCtx := &PerApplicationContext{
 			PerApplicationWG: perApplicationWG,
 			ApplicationIndex: applicationIndex,
+			Opts:             parentContext.Opts,
 			ParentContext:    parentContext,
 			ApplicationName:  fmt.Sprintf("%s-app-%s", parentContext.Username, util.GenerateRandomString(5)),
 		}
 
 		parentContext.PerApplicationContexts = append(parentContext.PerApplicationContexts, perApplicationCtx)
 
-		go fn(perApplicationCtx)
+		go runActive(&activeApplicationThreads, func() { fn(perApplicationCtx) })
 	}
 
 	perApplicationWG.Wait()
@@ -145,31 +185,39 @@ func PerApplicationSetup(fn func(*PerApplicationContext), parentContext *MainCon
 type PerComponentContext struct {
 	PerComponentWG     *sync.WaitGroup
 	ComponentIndex     int
+	Opts               *options.Opts // options of the application thread, overridden by component step params
 	Framework          *framework.Framework
 	ParentContext      *PerApplicationContext
 	ComponentName      string
 	SnapshotName       string
+	ReleaseName        string
 	MergeRequestNumber int
 }
 
+// Identifies component journey iteration in measurements
+func (ctx *PerComponentContext) IterationKey() string {
+	return ctx.ParentContext.IterationKey() + "/" + ctx.ComponentName
+}
+
 // Start all the threads to process all components per application
 func PerComponentSetup(fn func(*PerComponentContext), parentContext *PerApplicationContext) (string, error) {
 	perComponentWG := &sync.WaitGroup{}
-	perComponentWG.Add(parentContext.ParentContext.Opts.ComponentsCount)
+	perComponentWG.Add(parentContext.Opts.ComponentsCount)
 
-	for componentIndex := 0; componentIndex < parentContext.ParentContext.Opts.ComponentsCount; componentIndex++ {
+	for componentIndex := 0; componentIndex < parentContext.Opts.ComponentsCount; componentIndex++ {
 		logging.Logger.Info("Initiating per component thread %d-%d-%d", parentContext.ParentContext.ThreadIndex, parentContext.ApplicationIndex, componentIndex)
 
 		perComponentCtx := &PerComponentContext{
 			PerComponentWG: perComponentWG,
 			ComponentIndex: componentIndex,
+			Opts:           parentContext.Opts,
 			ParentContext:  parentContext,
 			ComponentName:  fmt.Sprintf("%s-comp-%d", parentContext.ApplicationName, componentIndex),
 		}
 
 		parentContext.PerComponentContexts = append(parentContext.PerComponentContexts, perComponentCtx)
 
-		go fn(perComponentCtx)
+		go runActive(&activeComponentThreads, func() { fn(perComponentCtx) })
 	}
 
 	perComponentWG.Wait()
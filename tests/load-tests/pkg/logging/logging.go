@@ -3,6 +3,8 @@ package logging
 import "fmt"
 import "time"
 
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
+
 import klog "k8s.io/klog/v2"
 
 var TRACE int = 0
@@ -61,13 +63,18 @@ func (l *logger) Fatal(msg string, params ...interface{}) {
 }
 
 // Log test failure with error code to CSV file so we can compile a statistic later
-func (l *logger) Fail(errCode int, msg string, params ...interface{}) error {
+// together with category of the code and classified cause of the first error among params
+func (l *logger) Fail(errCode failures.Code, msg string, params ...interface{}) error {
 	errorMessage := fmt.Sprintf("FAIL(%d): %s", errCode, msg)
 	klog.Infof(errorMessage, params...)
+	cause := failures.ClassifyParams(params...)
 	data := ErrorEntry{
 		Timestamp: time.Now(),
-		Code:      errCode,
+		Code:      int(errCode),
 		Message:   fmt.Sprintf(errorMessage, params...),
+		Category:  errCode.Category(),
+		Cause:     cause.String(),
+		Resource:  cause.Resource,
 	}
 	errorsQueue <- data
 	return fmt.Errorf(errorMessage, params...)
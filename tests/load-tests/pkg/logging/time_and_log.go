@@ -10,15 +10,28 @@ import "time"
 import "os"
 import "encoding/csv"
 import "sync"
+import "sync/atomic"
+import "strconv"
+
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
+import stats "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/stats"
 
 var measurementsQueue chan MeasurementEntry // channel to send measurements to
 var errorsQueue chan ErrorEntry // chanel to send failures to
 
 var measurementsOutput string // path to CSV where to save measurements
 var errorsOutput string // path to CSV where to save measurements
+var summaryOutput string // path to JSON where to save statistics of measurements
+
+var collector *stats.Collector // statistics of measurements computed on the fly
+var failureCollector *failures.Collector // failures grouped by code and cause
+var measurementObservers []func(MeasurementEntry) // functions called with every measurement
+var errorObservers []func(ErrorEntry) // functions called with every failure
 
 var writerWaitGroup sync.WaitGroup
 
+var measurementsWritten, errorsWritten atomic.Int64 // number of records written to CSV files
+
 var batchSize int // when we accumulate this many of records, we dump them to CSV (this is to batch writest to the file, possibly make it faster)
 
 // Represents the data about measurement we want to store to CSV
@@ -28,11 +41,18 @@ type MeasurementEntry struct {
 	Duration   time.Duration
 	Parameters string
 	Error      error
+	Iteration  string // journey iteration the measurement belongs to
+}
+
+// Implemented by journey contexts so measurements of functions called with
+// them are attributed to their journey iteration, e.g. "thread-1/app-x/comp-y"
+type Iteration interface {
+	IterationKey() string
 }
 
 // Helper function to convert struct to slice of string which is needed when converting to CSV
 func (e *MeasurementEntry) GetSliceOfStrings() []string {
-	return []string{e.Timestamp.Format(time.RFC3339Nano), e.Metric, fmt.Sprintf("%f", e.Duration.Seconds()), e.Parameters, fmt.Sprintf("%v", e.Error)}
+	return []string{e.Timestamp.Format(time.RFC3339Nano), e.Metric, fmt.Sprintf("%f", e.Duration.Seconds()), e.Parameters, fmt.Sprintf("%v", e.Error), e.Iteration}
 }
 
 // Represents the data about failure we want to store to CSV
@@ -40,14 +60,50 @@ type ErrorEntry struct {
 	Timestamp time.Time
 	Code      int
 	Message   string
+	Category  string // category of the code, see failures.Registry
+	Cause     string // classified underlying error, see failures.Classify
+	Resource  string // resource the underlying error is about, if known
 }
 
 // Helper function to convert struct to slice of string which is needed when converting to CSV
 func (e *ErrorEntry) GetSliceOfStrings() []string {
-	return []string{e.Timestamp.Format(time.RFC3339Nano), fmt.Sprintf("%d", e.Code), e.Message}
+	return []string{e.Timestamp.Format(time.RFC3339Nano), fmt.Sprintf("%d", e.Code), e.Message, e.Category, e.Cause, e.Resource}
+}
+
+// Example of the failure for the summary, resource or beginning of the message if not known
+func (e *ErrorEntry) example() string {
+	if e.Resource != "" {
+		return e.Resource
+	}
+	if len(e.Message) > 200 {
+		return e.Message[:200] + "..."
+	}
+	return e.Message
 }
 
 
+// Register function to be called with every measurement, e.g. to export it.
+// It is called from the goroutine processing measurements so it should be
+// fast. Observers have to be registered before MeasurementsStart.
+func OnMeasurement(fn func(MeasurementEntry)) {
+	measurementObservers = append(measurementObservers, fn)
+}
+
+// Register function to be called with every failure, same rules as for OnMeasurement apply
+func OnError(fn func(ErrorEntry)) {
+	errorObservers = append(errorObservers, fn)
+}
+
+// Store measurement taken elsewhere, e.g. received from distributed worker
+func RecordMeasurement(entry MeasurementEntry) {
+	measurementsQueue <- entry
+}
+
+// Store failure logged elsewhere, e.g. received from distributed worker
+func RecordError(entry ErrorEntry) {
+	errorsQueue <- entry
+}
+
 // Initialize channels and start functions that are processing records
 func MeasurementsStart(directory string) {
 	batchSize = 3
@@ -56,18 +112,121 @@ func MeasurementsStart(directory string) {
 
 	measurementsQueue = make(chan MeasurementEntry)
 	measurementsOutput = directory + "/load-test-timings.csv"
+	summaryOutput = directory + "/load-test-summary.json"
+	collector = stats.NewCollector()
 	go measurementsWriter()
 
+	failureCollector = failures.NewCollector()
+
 	errorsQueue = make(chan ErrorEntry)
 	errorsOutput = directory + "/load-test-errors.csv"
 	go errorsWriter()
 }
 
+// Same as MeasurementsStart, but continue previous run. Measurements and
+// errors CSV files are cut to given number of records (written before the
+// checkpoint we resume from) and kept measurements are added to statistics.
+func MeasurementsResume(directory string, measurementsOffset, errorsOffset int) error {
+	measurements, err := truncateCSV(directory+"/load-test-timings.csv", measurementsOffset)
+	if err != nil {
+		return fmt.Errorf("Failed to resume measurements: %v", err)
+	}
+	failed, err := truncateCSV(directory+"/load-test-errors.csv", errorsOffset)
+	if err != nil {
+		return fmt.Errorf("Failed to resume errors: %v", err)
+	}
+
+	MeasurementsStart(directory)
+
+	for _, record := range measurements {
+		timestamp, err1 := time.Parse(time.RFC3339Nano, record[0])
+		seconds, err2 := strconv.ParseFloat(record[2], 64)
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("Failed to parse measurement %v: %v %v", record, err1, err2)
+		}
+		iteration := ""
+		if len(record) > 5 {
+			iteration = record[5]
+		}
+		collector.Add(timestamp, record[1], iteration, time.Duration(seconds*float64(time.Second)), record[4] != "<nil>")
+	}
+	for _, record := range failed {
+		code, err := strconv.Atoi(record[1])
+		if err != nil || len(record) < 6 {
+			continue // written by older version without cause
+		}
+		entry := ErrorEntry{Message: record[2], Resource: record[5]}
+		failureCollector.Add(failures.Code(code), record[4], entry.example())
+	}
+	measurementsWritten.Store(int64(len(measurements)))
+	errorsWritten.Store(int64(errorsOffset))
+
+	return nil
+}
+
+// Keep only first given number of records in CSV file and return them
+func truncateCSV(path string, records int) ([][]string, error) {
+	path = filepath.Clean(path)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) && records == 0 {
+		return [][]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	all, err := reader.ReadAll()
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(all) < records {
+		return nil, fmt.Errorf("%s has %d records, expected at least %d", path, len(all), records)
+	}
+
+	kept := all[:records]
+	file, err = os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(kept); err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+// Number of measurements and errors written to CSV files so far
+func MeasurementsOffsets() (measurements, errors int) {
+	return int(measurementsWritten.Load()), int(errorsWritten.Load())
+}
+
 // Close channels and wait to ensure any remaining records are written to CSV
 func MeasurementsStop() {
 	close(measurementsQueue)
 	close(errorsQueue)
 	writerWaitGroup.Wait()
+
+	summary := collector.Summary()
+	summary.Failures = failureCollector.Summary()
+	Logger.Info("KPI mean: %f", summary.KPI.Mean)
+	Logger.Info("KPI errors: %d", summary.KPI.Errors)
+	if summary.Failures.Total > 0 {
+		report := &strings.Builder{}
+		_ = summary.Failures.Write(report)
+		Logger.Warning("Failure summary:\n%s", report.String())
+	}
+	err := stats.WriteSummary(summaryOutput, summary)
+	if err != nil {
+		Logger.Error("Error writing summary to JSON file: %v", err)
+	}
+}
+
+// Statistics of measurements collected so far
+func Summary() *stats.Summary {
+	return collector.Summary()
 }
 
 // Append slice to a CSV file
@@ -105,13 +264,19 @@ func measurementsWriter() {
 			break
 		}
 		batch = append(batch, event.GetSliceOfStrings())
+		collector.Add(event.Timestamp, event.Metric, event.Iteration, event.Duration, event.Error != nil)
+		for _, observer := range measurementObservers {
+			observer(event)
+		}
 		counter++
 		if len(batch) == batchSize {
 			err := writeToCSV(measurementsOutput, batch)
 			if err != nil {
 				Logger.Error("Error writing to CSV file: %v", err)
+			} else {
+				measurementsWritten.Add(int64(len(batch)))
 			}
-			batch = make([][]string, batchSize)
+			batch = make([][]string, 0, batchSize)
 		}
 	}
 
@@ -120,6 +285,8 @@ func measurementsWriter() {
 		err := writeToCSV(measurementsOutput, batch)
 		if err != nil {
 			Logger.Error("Error writing to CSV file: %v", err)
+		} else {
+			measurementsWritten.Add(int64(len(batch)))
 		}
 	}
 
@@ -141,13 +308,19 @@ func errorsWriter() {
 			break
 		}
 		batch = append(batch, event.GetSliceOfStrings())
+		failureCollector.Add(failures.Code(event.Code), event.Cause, event.example())
+		for _, observer := range errorObservers {
+			observer(event)
+		}
 		counter++
 		if len(batch) == batchSize {
 			err := writeToCSV(errorsOutput, batch)
 			if err != nil {
 				Logger.Error("Error writing to CSV file: %v", err)
+			} else {
+				errorsWritten.Add(int64(len(batch)))
 			}
-			batch = make([][]string, batchSize)
+			batch = make([][]string, 0, batchSize)
 		}
 	}
 
@@ -156,6 +329,8 @@ func errorsWriter() {
 		err := writeToCSV(errorsOutput, batch)
 		if err != nil {
 			Logger.Error("Error writing to CSV file: %v", err)
+		} else {
+			errorsWritten.Add(int64(len(batch)))
 		}
 	}
 
@@ -166,6 +341,20 @@ func errorsWriter() {
 // This only returns first (data) and last (error) returned value. Maybe this
 // can be generalized completely, but it is good enough for our needs.
 func Measure(fn interface{}, params ...interface{}) (interface{}, error) {
+	// Attribute measurement to journey iteration of a context passed as a parameter
+	iteration := ""
+	for _, param := range params {
+		if i, ok := param.(Iteration); ok {
+			iteration = i.IterationKey()
+			break
+		}
+	}
+	return MeasureIn(iteration, fn, params...)
+}
+
+// Same as Measure, but attribute the measurement to given journey iteration,
+// used when measured function is not called with the journey context
+func MeasureIn(iteration string, fn interface{}, params ...interface{}) (interface{}, error) {
 	funcValue := reflect.ValueOf(fn)
 
 	// Construct arguments for the function call
@@ -204,7 +393,7 @@ func Measure(fn interface{}, params ...interface{}) (interface{}, error) {
 
 	defer func() {
 		elapsed := time.Since(startTime)
-		LogMeasurement(funcName, paramsStorable, elapsed, fmt.Sprintf("%+v", resultInterValue), errInterValue)
+		logMeasurement(iteration, funcName, paramsStorable, elapsed, fmt.Sprintf("%+v", resultInterValue), errInterValue)
 	}()
 
 	// Call the function with provided arguments
@@ -225,6 +414,10 @@ func Measure(fn interface{}, params ...interface{}) (interface{}, error) {
 
 // Store given measurement
 func LogMeasurement(metric string, params map[string]string, elapsed time.Duration, result string, err error) {
+	logMeasurement("", metric, params, elapsed, result, err)
+}
+
+func logMeasurement(iteration string, metric string, params map[string]string, elapsed time.Duration, result string, err error) {
 	// Extract parameter keys into a slice so we can sort them
 	var paramsKeys []string
 	for k := range params {
@@ -252,6 +445,7 @@ func LogMeasurement(metric string, params map[string]string, elapsed time.Durati
 		Duration:   elapsed,
 		Parameters: params_string,
 		Error:      err,
+		Iteration:  iteration,
 	}
 	measurementsQueue <- data
 }
@@ -0,0 +1,29 @@
+package logging
+
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/stretchr/testify/assert"
+import "github.com/stretchr/testify/require"
+
+func TestMeasurementsWrittenInBatches(t *testing.T) {
+	dir := t.TempDir()
+	MeasurementsStart(dir)
+	for i := 0; i < 7; i++ {
+		LogMeasurement("journey.HandleUser", map[string]string{}, time.Second, "", nil)
+	}
+	MeasurementsStop()
+
+	data, err := os.ReadFile(filepath.Join(dir, "load-test-timings.csv"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 7)
+	for _, line := range lines {
+		assert.Contains(t, line, "journey.HandleUser")
+	}
+	measurements, _ := MeasurementsOffsets()
+	assert.Equal(t, 7, measurements)
+}
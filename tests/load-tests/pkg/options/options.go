@@ -5,23 +5,38 @@ import "fmt"
 import "os"
 import "time"
 
+import arrival "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/arrival"
+import chaos "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/chaos"
+
 // Struct to hold command line options
 type Opts struct {
 	ApplicationsCount             int
+	ArrivalPeakRate               float64
+	ArrivalProfile                string
+	ArrivalRate                   float64
+	ArrivalStep                   time.Duration
 	BuildPipelineSelectorBundle   string
+	ChaosDuration                 time.Duration
+	ChaosFaults                   []string
+	ChaosInterval                 time.Duration
+	CheckpointInterval            time.Duration
 	ComponentContainerContext     string
 	ComponentContainerFile        string
 	ComponentRepoRevision         string
 	ComponentRepoUrl              string
 	ComponentsCount               int
 	Concurrency                   int
+	Coordinator                   string
 	FailFast                      bool
 	JourneyDuration               string
+	JourneyFile                   string
 	JourneyRepeats                int
 	JourneyUntil                  time.Time
 	LogDebug                      bool
 	LogTrace                      bool
 	LogInfo                       bool
+	MaxInFlight                   int
+	MetricsAddress                string
 	OutputDir                     string
 	PipelineMintmakerDisabled     bool
 	PipelineRepoTemplating        bool
@@ -29,10 +44,17 @@ type Opts struct {
 	Purge                         bool
 	PurgeOnly                     bool
 	QuayRepo                      string
+	Release                       bool
+	ReleasePipelinePath           string
+	ReleasePipelineRevision       string
+	ReleasePipelineUrl            string
+	ReleaseServiceAccount         string
+	Resume                        bool
 	Stage                         bool
 	TestScenarioGitURL            string
 	TestScenarioPathInRepo        string
 	TestScenarioRevision          string
+	ThreadOffset                  int
 	UsernamePrefix                string
 	WaitIntegrationTestsPipelines bool
 	WaitPipelines                 bool
@@ -47,6 +69,39 @@ func (o *Opts) ProcessOptions() error {
 	}
 	o.JourneyUntil = time.Now().UTC().Add(parsed)
 
+	// Check arrival profile when running open model
+	if o.ArrivalProfile != "" {
+		if err := o.Arrival().Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Check faults to inject, we need admin access to controllers for that
+	if len(o.ChaosFaults) > 0 {
+		if o.Stage {
+			return fmt.Errorf("Fault injection is not possible on Stage")
+		}
+		if _, err := chaos.ParseFaults(o.ChaosFaults); err != nil {
+			return err
+		}
+		if o.ChaosInterval <= 0 || o.ChaosDuration <= 0 {
+			return fmt.Errorf("Both '--chaos-interval' and '--chaos-duration' have to be positive")
+		}
+	}
+
+	// Worker runs fixed part of users assigned by the coordinator
+	if o.Coordinator != "" && (o.ArrivalProfile != "" || o.Resume) {
+		return fmt.Errorf("Options '--arrival-profile' and '--resume' can not be used with '--coordinator'")
+	}
+	if o.ThreadOffset < 0 {
+		return fmt.Errorf("Option '--thread-offset' can not be negative")
+	}
+
+	// Release needs the Snapshot of the finished build pipeline
+	if o.Release && !o.WaitPipelines {
+		return fmt.Errorf("Option '--release' needs '--waitpipelines'")
+	}
+
 	// Option '--purge-only' implies '--purge'
 	if o.PurgeOnly {
 		o.Purge = true
@@ -66,3 +121,16 @@ func (o *Opts) ProcessOptions() error {
 
 	return nil
 }
+
+// Arrival profile of the open model run given by '--arrival-*' options, it
+// lasts for '--journey-duration'
+func (o *Opts) Arrival() *arrival.Profile {
+	duration, _ := time.ParseDuration(o.JourneyDuration)
+	return &arrival.Profile{
+		Kind:     o.ArrivalProfile,
+		Rate:     o.ArrivalRate,
+		PeakRate: o.ArrivalPeakRate,
+		Step:     o.ArrivalStep,
+		Duration: duration,
+	}
+}
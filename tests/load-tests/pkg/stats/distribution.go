@@ -0,0 +1,156 @@
+package stats
+
+import "math"
+import "sort"
+import "strconv"
+
+// Relative accuracy of the quantiles, i.e. p95 of 100s is between 99s and 101s
+const sketchAccuracy = 0.01
+
+// Upper bounds (in seconds) of the histogram buckets reported for every metric
+var HistogramBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+// Summary of durations (in seconds) of a metric
+type DurationStats struct {
+	Samples   int      `json:"samples"`
+	Min       float64  `json:"min,omitempty"`
+	Mean      float64  `json:"mean,omitempty"`
+	Max       float64  `json:"max,omitempty"`
+	P50       float64  `json:"p50,omitempty"`
+	P90       float64  `json:"p90,omitempty"`
+	P95       float64  `json:"p95,omitempty"`
+	P99       float64  `json:"p99,omitempty"`
+	Histogram []Bucket `json:"histogram,omitempty"`
+}
+
+// Cumulative histogram bucket, i.e. number of samples lower or equal to Le ("+Inf" for all samples)
+type Bucket struct {
+	Le    string `json:"le"`
+	Count int    `json:"count"`
+}
+
+// Streaming distribution of durations (in seconds). Quantiles are estimated
+// with a logarithmic sketch so memory does not grow with number of samples
+// and distributions from several load generators can be merged.
+type Distribution struct {
+	Count   int
+	Sum     float64
+	Min     float64
+	Max     float64
+	Zeros   int         // samples too small for the sketch
+	Sketch  map[int]int // sketch bucket index -> count
+	Buckets []int       // counts per HistogramBuckets, last one is +Inf
+}
+
+var sketchGamma = (1 + sketchAccuracy) / (1 - sketchAccuracy)
+var sketchLogGamma = math.Log(sketchGamma)
+
+func NewDistribution() *Distribution {
+	return &Distribution{
+		Sketch:  map[int]int{},
+		Buckets: make([]int, len(HistogramBuckets)+1),
+	}
+}
+
+// Add one sample
+func (d *Distribution) Add(value float64) {
+	if d.Count == 0 || value < d.Min {
+		d.Min = value
+	}
+	if d.Count == 0 || value > d.Max {
+		d.Max = value
+	}
+	d.Count++
+	d.Sum += value
+
+	if value < 1e-9 {
+		d.Zeros++
+	} else {
+		d.Sketch[int(math.Ceil(math.Log(value)/sketchLogGamma))]++
+	}
+
+	d.Buckets[sort.SearchFloat64s(HistogramBuckets, value)]++
+}
+
+// Merge samples of other distribution into this one
+func (d *Distribution) Merge(other *Distribution) {
+	if other == nil || other.Count == 0 {
+		return
+	}
+	if d.Count == 0 || other.Min < d.Min {
+		d.Min = other.Min
+	}
+	if d.Count == 0 || other.Max > d.Max {
+		d.Max = other.Max
+	}
+	d.Count += other.Count
+	d.Sum += other.Sum
+	d.Zeros += other.Zeros
+	for i, c := range other.Sketch {
+		d.Sketch[i] += c
+	}
+	for i, c := range other.Buckets {
+		d.Buckets[i] += c
+	}
+}
+
+// Estimate of given quantile (0 <= q <= 1)
+func (d *Distribution) Quantile(q float64) float64 {
+	if d.Count == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q*float64(d.Count))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	seen := d.Zeros
+	if rank < seen {
+		return d.Min
+	}
+
+	indexes := make([]int, 0, len(d.Sketch))
+	for i := range d.Sketch {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	for _, i := range indexes {
+		seen += d.Sketch[i]
+		if rank < seen {
+			value := 2 * math.Pow(sketchGamma, float64(i)) / (sketchGamma + 1)
+			return math.Max(d.Min, math.Min(d.Max, value))
+		}
+	}
+	return d.Max
+}
+
+// Compute summary of the distribution
+func (d *Distribution) Stats() DurationStats {
+	if d.Count == 0 {
+		return DurationStats{}
+	}
+
+	histogram := make([]Bucket, 0, len(d.Buckets))
+	cumulative := 0
+	for i, c := range d.Buckets {
+		cumulative += c
+		le := "+Inf"
+		if i < len(HistogramBuckets) {
+			le = strconv.FormatFloat(HistogramBuckets[i], 'f', -1, 64)
+		}
+		histogram = append(histogram, Bucket{Le: le, Count: cumulative})
+	}
+
+	return DurationStats{
+		Samples:   d.Count,
+		Min:       d.Min,
+		Mean:      d.Sum / float64(d.Count),
+		Max:       d.Max,
+		P50:       d.Quantile(0.50),
+		P90:       d.Quantile(0.90),
+		P95:       d.Quantile(0.95),
+		P99:       d.Quantile(0.99),
+		Histogram: histogram,
+	}
+}
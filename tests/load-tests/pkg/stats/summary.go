@@ -0,0 +1,319 @@
+package stats
+
+import "encoding/json"
+import "os"
+import "path/filepath"
+import "sort"
+import "strings"
+import "sync"
+import "time"
+
+import failures "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/failures"
+
+// Version of the load-test-summary.json format, bump it when making incompatible changes
+const SummaryVersion = 1
+
+// Metrics we care about that together form KPI metric duration
+var KPIMetrics = []string{
+	"HandleUser",
+	"createApplication",
+	"validateApplication",
+	"createIntegrationTestScenario",
+	"validateIntegrationTestScenario",
+	"createComponent",
+	"validatePipelineRunCreation",
+	"validatePipelineRunCondition",
+	"validatePipelineRunSignature",
+	"validateSnapshotCreation",
+	"validateTestPipelineRunCreation",
+	"validateTestPipelineRunCondition",
+}
+
+// Statistics of one metric
+type MetricStats struct {
+	Samples   int           `json:"samples"`
+	Errors    int           `json:"errors"`
+	ErrorRate float64       `json:"error_rate"`
+	Pass      DurationStats `json:"pass"`
+	Fail      DurationStats `json:"fail"`
+}
+
+// Statistics of the KPI, i.e. duration of the user journey
+type KPIStats struct {
+	// Sum of mean durations of passed KPI metrics, -1 when some KPI metric
+	// has no passed sample so the sum would only cover part of the journey
+	Mean float64 `json:"mean"`
+	// Number of failed KPI metric samples
+	Errors int `json:"errors"`
+	// Number of journey iterations (user, application and component) that passed all KPI metrics
+	Iterations int `json:"iterations"`
+	// Number of journey iterations with some KPI metric failed
+	FailedIterations int `json:"failed_iterations"`
+	// Number of journey iterations that did not fail, but did not measure all KPI metrics
+	IncompleteIterations int `json:"incomplete_iterations"`
+	// End-to-end durations of passed journey iterations
+	Duration DurationStats `json:"duration"`
+	// The same durations one by one, so runs can be compared with statistical tests
+	DurationSamples []float64 `json:"duration_samples,omitempty"`
+}
+
+// Content of load-test-summary.json
+type Summary struct {
+	Version   int                     `json:"version"`
+	Generated time.Time               `json:"generated"`
+	Started   time.Time               `json:"started"`
+	Ended     time.Time               `json:"ended"`
+	Metrics   map[string]*MetricStats `json:"metrics"`
+	KPI       KPIStats                `json:"kpi"`
+	Failures  *failures.Summary       `json:"failures,omitempty"`
+}
+
+// Durations of KPI metrics measured in one journey iteration
+type iterationRecord struct {
+	durations map[string]float64
+	failed    bool
+}
+
+// Collects measurements and computes their statistics on the fly
+type Collector struct {
+	mu         sync.Mutex
+	started    time.Time
+	ended      time.Time
+	pass       map[string]*Distribution
+	fail       map[string]*Distribution
+	iterations map[string]*iterationRecord
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		pass:       map[string]*Distribution{},
+		fail:       map[string]*Distribution{},
+		iterations: map[string]*iterationRecord{},
+	}
+}
+
+// Shorten full function name to package and function, e.g. "journey.HandleUser"
+func ShortMetricName(metric string) string {
+	return metric[strings.LastIndex(metric, "/")+1:]
+}
+
+// Return KPI metric the given metric is, if any
+func KPIMetric(metric string) (string, bool) {
+	for _, m := range KPIMetrics {
+		if metric == m || strings.HasSuffix(metric, "."+m) {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+// Add one measurement. Iteration identifies journey iteration the
+// measurement belongs to as a path, e.g. "thread-1/app-x/comp-y" with
+// measurements of a parent (e.g. "thread-1/app-x") shared by its children.
+func (c *Collector) Add(timestamp time.Time, metric string, iteration string, duration time.Duration, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.started.IsZero() || timestamp.Before(c.started) {
+		c.started = timestamp
+	}
+	if timestamp.After(c.ended) {
+		c.ended = timestamp
+	}
+
+	metric = ShortMetricName(metric)
+	distributions := c.pass
+	if failed {
+		distributions = c.fail
+	}
+	if distributions[metric] == nil {
+		distributions[metric] = NewDistribution()
+	}
+	distributions[metric].Add(duration.Seconds())
+
+	kpiMetric, isKPI := KPIMetric(metric)
+	if !isKPI || iteration == "" {
+		return
+	}
+	if c.iterations[iteration] == nil {
+		c.iterations[iteration] = &iterationRecord{durations: map[string]float64{}}
+	}
+	if failed {
+		c.iterations[iteration].failed = true
+	} else {
+		c.iterations[iteration].durations[kpiMetric] += duration.Seconds()
+	}
+}
+
+// Merge measurements collected by other collector into this one
+func (c *Collector) Merge(other *Collector) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !other.started.IsZero() && (c.started.IsZero() || other.started.Before(c.started)) {
+		c.started = other.started
+	}
+	if other.ended.After(c.ended) {
+		c.ended = other.ended
+	}
+	for _, pair := range [][2]map[string]*Distribution{{c.pass, other.pass}, {c.fail, other.fail}} {
+		for metric, d := range pair[1] {
+			if pair[0][metric] == nil {
+				pair[0][metric] = NewDistribution()
+			}
+			pair[0][metric].Merge(d)
+		}
+	}
+	for key, record := range other.iterations {
+		if c.iterations[key] == nil {
+			c.iterations[key] = &iterationRecord{durations: map[string]float64{}}
+		}
+		c.iterations[key].failed = c.iterations[key].failed || record.failed
+		for m, d := range record.durations {
+			c.iterations[key].durations[m] += d
+		}
+	}
+}
+
+// Compute statistics of all the measurements collected so far
+func (c *Collector) Summary() *Summary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	summary := &Summary{
+		Version:   SummaryVersion,
+		Generated: time.Now().UTC(),
+		Started:   c.started,
+		Ended:     c.ended,
+		Metrics:   map[string]*MetricStats{},
+	}
+
+	for _, distributions := range []map[string]*Distribution{c.pass, c.fail} {
+		for metric := range distributions {
+			if summary.Metrics[metric] != nil {
+				continue
+			}
+			ms := &MetricStats{}
+			if d := c.pass[metric]; d != nil {
+				ms.Pass = d.Stats()
+			}
+			if d := c.fail[metric]; d != nil {
+				ms.Fail = d.Stats()
+			}
+			ms.Samples = ms.Pass.Samples + ms.Fail.Samples
+			ms.Errors = ms.Fail.Samples
+			ms.ErrorRate = float64(ms.Errors) / float64(ms.Samples)
+			summary.Metrics[metric] = ms
+		}
+	}
+
+	// KPI as a sum of mean durations of KPI metrics
+	for _, m := range KPIMetrics {
+		passed := false
+		for metric, ms := range summary.Metrics {
+			if kpiMetric, ok := KPIMetric(metric); ok && kpiMetric == m {
+				summary.KPI.Errors += ms.Errors
+				if ms.Pass.Samples > 0 && summary.KPI.Mean != -1 {
+					summary.KPI.Mean += ms.Pass.Mean
+					passed = true
+				}
+			}
+		}
+		if !passed {
+			summary.KPI.Mean = -1
+		}
+	}
+
+	// KPI of every journey iteration
+	durations := NewDistribution()
+	for _, key := range c.leafIterations() {
+		merged := map[string]float64{}
+		failed := false
+		for _, ancestor := range iterationAncestors(key) {
+			record, ok := c.iterations[ancestor]
+			if !ok {
+				continue
+			}
+			failed = failed || record.failed
+			for m, d := range record.durations {
+				merged[m] += d
+			}
+		}
+		if failed {
+			summary.KPI.FailedIterations++
+			continue
+		}
+		if len(merged) < len(KPIMetrics) {
+			summary.KPI.IncompleteIterations++
+			continue
+		}
+		total := 0.0
+		for _, d := range merged {
+			total += d
+		}
+		durations.Add(total)
+		summary.KPI.DurationSamples = append(summary.KPI.DurationSamples, total)
+	}
+	summary.KPI.Iterations = durations.Count
+	summary.KPI.Duration = durations.Stats()
+
+	return summary
+}
+
+// Iterations that are not parents of other iterations
+func (c *Collector) leafIterations() []string {
+	keys := make([]string, 0, len(c.iterations))
+	for key := range c.iterations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	leaves := []string{}
+	for i, key := range keys {
+		isParent := false
+		for _, other := range keys[i+1:] {
+			if strings.HasPrefix(other, key+"/") {
+				isParent = true
+				break
+			}
+		}
+		if !isParent {
+			leaves = append(leaves, key)
+		}
+	}
+	return leaves
+}
+
+// Iteration itself and all its parents, e.g. "a", "a/b" and "a/b/c" for "a/b/c"
+func iterationAncestors(key string) []string {
+	parts := strings.Split(key, "/")
+	ancestors := make([]string, len(parts))
+	for i := range parts {
+		ancestors[i] = strings.Join(parts[:i+1], "/")
+	}
+	return ancestors
+}
+
+// Write summary as a pretty JSON
+func WriteSummary(path string, summary *Summary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), data, 0600)
+}
+
+// Load summary written by WriteSummary
+func LoadSummary(path string) (*Summary, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	summary := &Summary{}
+	if err := json.Unmarshal(data, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,81 @@
+package stats
+
+import "testing"
+import "time"
+
+import "github.com/stretchr/testify/assert"
+
+func TestDistribution(t *testing.T) {
+	d := NewDistribution()
+	for i := 1; i <= 100; i++ {
+		d.Add(float64(i))
+	}
+
+	s := d.Stats()
+	assert.Equal(t, 100, s.Samples)
+	assert.Equal(t, 1.0, s.Min)
+	assert.Equal(t, 50.5, s.Mean)
+	assert.Equal(t, 100.0, s.Max)
+	assert.InEpsilon(t, 50, s.P50, sketchAccuracy)
+	assert.InEpsilon(t, 90, s.P90, sketchAccuracy)
+	assert.InEpsilon(t, 95, s.P95, sketchAccuracy)
+	assert.InEpsilon(t, 99, s.P99, sketchAccuracy)
+	assert.Equal(t, Bucket{Le: "1", Count: 1}, s.Histogram[0])
+	assert.Equal(t, Bucket{Le: "60", Count: 60}, s.Histogram[4])
+	assert.Equal(t, Bucket{Le: "+Inf", Count: 100}, s.Histogram[len(s.Histogram)-1])
+
+	other := NewDistribution()
+	other.Add(0)
+	other.Add(1000)
+	d.Merge(other)
+	s = d.Stats()
+	assert.Equal(t, 102, s.Samples)
+	assert.Equal(t, 0.0, s.Min)
+	assert.Equal(t, 1000.0, s.Max)
+	assert.Equal(t, 0.0, d.Quantile(0))
+}
+
+func TestSummaryKPI(t *testing.T) {
+	c := NewCollector()
+	now := time.Now()
+	add := func(metric, iteration string, seconds int, failed bool) {
+		c.Add(now, "github.com/konflux-ci/e2e-tests/tests/load-tests/pkg/journey."+metric, iteration, time.Duration(seconds)*time.Second, failed)
+	}
+
+	add("HandleUser", "thread-0", 10, false)
+	for _, app := range []string{"thread-0/app-a", "thread-0/app-b", "thread-0/app-c"} {
+		add("createApplication", app, 1, false)
+		add("validateApplication", app, 1, false)
+		add("createIntegrationTestScenario", app, 1, false)
+		add("validateIntegrationTestScenario", app, 1, false)
+	}
+	for _, comp := range []string{"thread-0/app-a/comp-0", "thread-0/app-a/comp-1", "thread-0/app-b/comp-0"} {
+		for _, m := range KPIMetrics[5:] {
+			add(m, comp, 2, false)
+		}
+	}
+	// second component of app-a is slower
+	add("validatePipelineRunCondition", "thread-0/app-a/comp-1", 100, false)
+	// component of app-c failed
+	add("createComponent", "thread-0/app-c/comp-0", 5, true)
+	add("HandleRepoForking", "thread-0", 3, false)
+
+	summary := c.Summary()
+	assert.Equal(t, SummaryVersion, summary.Version)
+	assert.Equal(t, 1, summary.Metrics["journey.createComponent"].Errors)
+	assert.InDelta(t, 0.25, summary.Metrics["journey.createComponent"].ErrorRate, 1e-9)
+	assert.Equal(t, 1, summary.Metrics["journey.HandleRepoForking"].Samples)
+	assert.Equal(t, 1, summary.KPI.Errors)
+	// 10 + 4 * 1 + 6 * 2 + mean of validatePipelineRunCondition samples (2, 2, 100, 2)
+	assert.InDelta(t, 10+4+6*2+(2+2+100+2)/4.0, summary.KPI.Mean, 1e-9)
+
+	assert.Equal(t, 3, summary.KPI.Iterations)
+	assert.Equal(t, 1, summary.KPI.FailedIterations)
+	assert.Equal(t, 0, summary.KPI.IncompleteIterations)
+	assert.Equal(t, 10+4+7*2.0, summary.KPI.Duration.Min)
+	assert.Equal(t, 10+4+7*2.0+100, summary.KPI.Duration.Max)
+	assert.ElementsMatch(t, []float64{10 + 4 + 7*2.0, 10 + 4 + 7*2.0, 10 + 4 + 7*2.0 + 100}, summary.KPI.DurationSamples)
+
+	add("createComponent", "thread-1", 1, false)
+	assert.Equal(t, 1, c.Summary().KPI.IncompleteIterations)
+}
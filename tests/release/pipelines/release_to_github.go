@@ -18,6 +18,7 @@ import (
 
 	"github.com/devfile/library/v2/pkg/util"
 	"github.com/konflux-ci/e2e-tests/pkg/clients/github"
+	kubeCl "github.com/konflux-ci/e2e-tests/pkg/clients/kubernetes"
 	"github.com/konflux-ci/e2e-tests/pkg/constants"
 	"github.com/konflux-ci/e2e-tests/pkg/framework"
 	"github.com/konflux-ci/e2e-tests/pkg/utils"
@@ -50,6 +51,7 @@ var _ = framework.ReleasePipelinesSuiteDescribe("e2e tests for release-to-github
 	var err error
 	var devFw *framework.Framework
 	var managedFw *framework.Framework
+	var managedHub *framework.ControllerHub
 	var sampApplicationName = "samp-app-" + util.GenerateRandomString(4)
 	var sampComponentName = "samp-comp-" + util.GenerateRandomString(4)
 	var sampReleasePlanName = "samp-rp-" + util.GenerateRandomString(4)
@@ -69,6 +71,8 @@ var _ = framework.ReleasePipelinesSuiteDescribe("e2e tests for release-to-github
 			devFw = releasecommon.NewFramework(devWorkspace)
 			managedFw = releasecommon.NewFramework(managedWorkspace)
 			managedNamespace = managedFw.UserNamespace
+			managedHub, err = managedFw.ClusterHub(kubeCl.ManagedCluster)
+			Expect(err).NotTo(HaveOccurred())
 
 			githubUser := utils.GetEnv("GITHUB_USER", "redhat-appstudio-qe-bot")
 			githubToken := utils.GetEnv(constants.GITHUB_TOKEN_ENV, "")
@@ -148,7 +152,7 @@ var _ = framework.ReleasePipelinesSuiteDescribe("e2e tests for release-to-github
 
 			It("verifies the release pipelinerun is running and succeeds", func() {
 				Eventually(func() error {
-					pipelineRun, err = managedFw.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, releaseCR.GetName(), releaseCR.GetNamespace())
+					pipelineRun, err = managedHub.ReleaseController.GetPipelineRunInNamespace(managedNamespace, releaseCR.GetName(), releaseCR.GetNamespace())
 					if err != nil {
 						return fmt.Errorf("PipelineRun has not been created yet for release %s/%s", releaseCR.GetNamespace(), releaseCR.GetName())
 					}
@@ -164,7 +168,7 @@ var _ = framework.ReleasePipelinesSuiteDescribe("e2e tests for release-to-github
 						return nil
 					} else {
 						prLogs := ""
-						if prLogs, err = tekton.GetFailedPipelineRunLogs(managedFw.AsKubeAdmin.ReleaseController.KubeRest(), managedFw.AsKubeAdmin.ReleaseController.KubeInterface(), pipelineRun); err != nil {
+						if prLogs, err = tekton.GetFailedPipelineRunLogs(managedHub.ReleaseController.KubeRest(), managedHub.ReleaseController.KubeInterface(), pipelineRun); err != nil {
 							GinkgoWriter.Printf("failed to get PLR logs: %+v", err)
 							Expect(err).ShouldNot(HaveOccurred())
 							return nil
@@ -175,7 +179,7 @@ var _ = framework.ReleasePipelinesSuiteDescribe("e2e tests for release-to-github
 					}
 				}, releasecommon.BuildPipelineRunCompletionTimeout, releasecommon.DefaultInterval).Should(Succeed(), fmt.Sprintf("timed out when waiting for the release PipelineRun to be finished for the release %s/%s", releaseCR.GetName(), releaseCR.GetNamespace()))
 
-				releasePR, err = managedFw.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedFw.UserNamespace, releaseCR.GetName(), releaseCR.GetNamespace())
+				releasePR, err = managedHub.ReleaseController.GetPipelineRunInNamespace(managedFw.UserNamespace, releaseCR.GetName(), releaseCR.GetNamespace())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -191,7 +195,7 @@ var _ = framework.ReleasePipelinesSuiteDescribe("e2e tests for release-to-github
 			})
 
 			It("verifies if the Release exists in github repo", func() {
-				trReleasePr, err := managedFw.AsKubeAdmin.TektonController.GetTaskRunStatus(managedFw.AsKubeAdmin.CommonController.KubeRest(), releasePR, "create-github-release")
+				trReleasePr, err := managedHub.TektonController.GetTaskRunStatus(managedHub.CommonController.KubeRest(), releasePR, "create-github-release")
 				Expect(err).NotTo(HaveOccurred())
 				trReleaseURL := trReleasePr.Status.TaskRunStatusFields.Results[0].Value.StringVal
 				releaseURL := strings.Replace(trReleaseURL, "\n", "", -1)